@@ -1,33 +1,140 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/gql"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/grpcapi"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/handler"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/health"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/logging"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/metrics"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/realtime"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/route"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
+// version diisi saat build: go build -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
 func main() {
-	log.Println("===========================================")
-	log.Println("   STARTING TODO REST API SERVER")
-	log.Println("===========================================")
+	// SIGHUP dipasang paling awal: tanpa handler, SIGHUP yang datang saat
+	// start (misalnya saat menunggu database) menghentikan proses
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
 
-	// Load configuration
-	cfg := config.LoadConfig()
+	// Load configuration: default < file config < env < flag
+	cfg, err := config.LoadConfig()
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		// Logger belum dikonfigurasi, tulis apa adanya agar setiap error terbaca per baris
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 	gin.SetMode(cfg.GinMode)
+	utils.SetJWTSecret(cfg.JWTSecret)
+
+	// Snapshot config yang bisa di-reload (SIGHUP / file berubah) tanpa restart
+	settings := config.NewLive(cfg, os.Args[1:])
+
+	// Structured logger; log.Printf di package lain juga diteruskan ke sini.
+	// Level disimpan di LevelVar agar bisa diubah lewat config reload.
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(logging.ParseLevel(cfg.LogLevel))
+	settings.OnChange(func(_, next *config.Config) {
+		logLevel.Set(logging.ParseLevel(next.LogLevel))
+	})
+	slog.SetDefault(logging.New(os.Stdout, cfg.LogFormat, logLevel))
+	slog.Info("starting todo REST API server", "gin_mode", cfg.GinMode, "version", buildVersion(), "config_file", cfg.File)
+
+	// OpenTelemetry tracing; shutdown mengirim span yang masih di buffer
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:     cfg.TracingExporter,
+		ServiceName:  cfg.TracingServiceName,
+		OTLPEndpoint: cfg.OTLPEndpoint,
+		OTLPInsecure: cfg.OTLPInsecure,
+		SampleRatio:  cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		fatal("failed to initialize tracing", err)
+	}
 
 	// Initialize database
 	db, err := config.NewDatabase(cfg)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		fatal("failed to initialize database", err)
+	}
+
+	// Statistik connection pool diekspos di /metrics
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal("failed to get database handle", err)
+	}
+	if err := metrics.RegisterDB(sqlDB, cfg.DBName); err != nil {
+		fatal("failed to register database metrics", err)
+	}
+
+	// Read replica untuk query read-only; tanpa DB_REPLICA_HOSTS semua query ke primary
+	replicas, err := config.NewReplicas(cfg)
+	if err != nil {
+		fatal("failed to initialize read replicas", err)
+	}
+	for _, replica := range replicas {
+		replicaDB, err := replica.DB.DB()
+		if err != nil {
+			fatal("failed to get replica handle", err)
+		}
+		if err := metrics.RegisterDB(replicaDB, replica.Name); err != nil {
+			fatal("failed to register replica metrics", err)
+		}
 	}
+	dbCluster := database.NewCluster(db, database.Options{
+		CheckInterval: cfg.DBReplicaCheckInterval,
+		MaxLag:        cfg.DBReplicaMaxLag,
+	}, replicas...)
+	// Check pertama sebelum menerima traffic agar replica yang sehat langsung dipakai
+	dbCluster.Check(context.Background())
 
-	log.Println("✓ Configuration loaded")
+	// Schema database dari migrasi SQL versi (lihat cmd/migrate). Advisory lock
+	// di migrator membuat replica yang start bersamaan tidak saling balapan.
+	migrations, err := migrate.Embedded(cfg.DBDriver)
+	if err != nil {
+		fatal("failed to load migrations", err)
+	}
+	migrator, err := migrate.New(sqlDB, cfg.DBDriver, migrations)
+	if err != nil {
+		fatal("failed to create migrator", err)
+	}
+	if cfg.DBMigrateOnStart {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			fatal("failed to migrate database", err)
+		}
+		slog.Info("database migration completed", "applied", len(applied))
+	}
 
 	// ============================================
 	// DEPENDENCY INJECTION PATTERN
@@ -35,45 +142,224 @@ func main() {
 
 	// Layer 1: Initialize Repositories (Data Access Layer)
 	userRepo := repository.NewUserRepository(db)
-	todoRepo := repository.NewTodoRepository(db)
-	log.Println("✓ Repositories initialized")
+	todoRepo := repository.NewTodoRepository(dbCluster)
+	templateRepo := repository.NewTemplateRepository(db)
+	boardRepo := repository.NewBoardRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+
+	// Event bus: todo changes are fanned out to subscribers (webhooks, SSE, WebSocket)
+	events := event.NewBus()
+	eventLog := event.NewLog(cfg.EventLogSize)
+	events.Subscribe(eventLog.Append)
+	hub := realtime.NewHub()
+	events.Subscribe(hub.HandleEvent)
+
+	// Zona waktu untuk tanggal kalender, sudah divalidasi saat config dimuat
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		fatal("failed to load time zone", err)
+	}
 
 	// Layer 2: Initialize Services (Business Logic Layer)
 	authService := service.NewAuthService(userRepo)
-	todoService := service.NewTodoService(todoRepo)
-	log.Println("✓ Services initialized")
+	todoService := service.NewTodoService(todoRepo, boardRepo, events, func() service.TodoSettings {
+		current := settings.Load()
+		return service.TodoSettings{BlockCompletion: current.TodoBlockCompletion, WIPLimit: current.WIPLimit}
+	})
+	templateService := service.NewTemplateService(templateRepo, todoRepo, events, location)
+	boardShareService := service.NewBoardShareService(boardRepo, userRepo, todoRepo)
+	syncService := service.NewSyncService(todoRepo, todoService)
+	webhookDispatcher := service.NewWebhookDispatcher(webhookRepo)
+	webhookService := service.NewWebhookService(webhookRepo, webhookDispatcher)
+	events.Subscribe(webhookService.HandleEvent)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.IdempotencyTTL)
+
+	// Readiness: database, schema, dan checker lain yang didaftarkan subsystem
+	healthChecks := health.New(buildVersion(), cfg.HealthCheckTimeout)
+	healthChecks.Register(
+		health.DatabaseChecker(db),
+		health.MigrationChecker(migrator),
+	)
+	if len(replicas) > 0 {
+		healthChecks.Register(health.ReplicaChecker(dbCluster))
+	}
+
+	// Background workers, dihentikan lewat stopWorkers saat shutdown
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(5)
+	go func() {
+		defer workers.Done()
+		webhookDispatcher.Run(workerCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		webhookService.Run(workerCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		idempotencyService.RunCleanup(workerCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		settings.Watch(workerCtx, hangup)
+	}()
+	go func() {
+		defer workers.Done()
+		dbCluster.Watch(workerCtx)
+	}()
 
 	// Layer 3: Initialize Handlers (HTTP Layer)
 	userHandler := handler.NewUserHandler(authService)
-	healthHandler := handler.NewHealthHandler(db)
+	healthHandler := handler.NewHealthHandler(healthChecks, cfg.MonitoringToken)
 	todoHandler := handler.NewTodoHandler(todoService)
-	log.Println("✓ Handlers initialized")
+	templateHandler := handler.NewTemplateHandler(templateService)
+	boardHandler := handler.NewBoardHandler(todoService, boardShareService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	eventHandler := handler.NewEventHandler(eventLog, boardShareService)
+	webSocketHandler := handler.NewWebSocketHandler(hub, boardShareService, func() []string { return settings.Load().CORSAllowedOrigins })
+	boardShareService.OnRevoke(webSocketHandler.Revoke)
+	syncHandler := handler.NewSyncHandler(syncService)
+	graphQLHandler := handler.NewGraphQLHandler(gql.NewSchema(todoService, authService))
 
 	// ============================================
 	// GIN ROUTER SETUP
 	// ============================================
 
-	router := gin.Default()
+	router := gin.New()
+	// IP client (rate limit per IP, log) hanya diambil dari X-Forwarded-For jika
+	// request datang dari proxy terpercaya; tanpa TRUSTED_PROXIES header itu diabaikan
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		fatal("invalid trusted proxies", err)
+	}
 
 	// Global middleware
+	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Tracing())
 	router.Use(middleware.LoggerMiddleware())
-	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.Metrics())
+	router.Use(middleware.CORSMiddleware(func() []string { return settings.Load().CORSAllowedOrigins }))
 	router.Use(middleware.ErrorHandler())
 
+	// Rate limit: bucket disimpan di memory dan dihapus setelah idle lebih lama
+	// dari period limit-nya, dicek setiap menit
+	rateLimiter := middleware.NewRateLimiter(middleware.NewMemoryRateLimitStore(time.Minute))
+
 	// Setup routes
-	route.SetupRoutes(router, userHandler, healthHandler, todoHandler)
-	log.Println("✓ Routes configured")
+	route.SetupRoutes(router, userHandler, healthHandler, todoHandler, templateHandler, boardHandler, webhookHandler, eventHandler, webSocketHandler, syncHandler, graphQLHandler,
+		route.Middlewares{
+			Idempotency: middleware.Idempotency(idempotencyService),
+			Monitoring:  middleware.MonitoringAuth(cfg.MonitoringToken),
+			IPRateLimit: rateLimiter.LimitFunc("ip", func() middleware.RateLimit {
+				current := settings.Load()
+				return middleware.RateLimit{Requests: current.IPRateLimitRequests, Period: current.IPRateLimitPeriod}
+			}),
+			RateLimit: rateLimiter.LimitFunc("api", func() middleware.RateLimit {
+				current := settings.Load()
+				return middleware.RateLimit{Requests: current.RateLimitRequests, Period: current.RateLimitPeriod}
+			}),
+			AuthRateLimit: rateLimiter.LimitFunc("auth", func() middleware.RateLimit {
+				current := settings.Load()
+				return middleware.RateLimit{Requests: current.AuthRateLimitRequests, Period: current.AuthRateLimitPeriod}
+			}),
+		})
+
+	// ============================================
+	// START gRPC SERVER
+	// ============================================
+
+	// Error server (HTTP maupun gRPC) memicu shutdown yang sama dengan sinyal
+	serverErr := make(chan error, 2)
+
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+	if err != nil {
+		fatal("failed to listen on gRPC port", err)
+	}
+	grpcServer := grpcapi.NewServer(authService, todoService, boardShareService, eventLog)
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			serverErr <- fmt.Errorf("gRPC server: %w", err)
+		}
+	}()
 
 	// ============================================
 	// START SERVER
 	// ============================================
 
-	log.Println("===========================================")
-	log.Printf("   Server running on :%s", cfg.ServerPort)
-	log.Println("   API Endpoint: http://localhost:" + cfg.ServerPort)
-	log.Println("===========================================")
+	// WriteTimeout tidak berlaku untuk stream SSE (dilepas di handler) dan WebSocket (hijacked)
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.ServerPort),
+		Handler:           router,
+		ReadTimeout:       cfg.ServerReadTimeout,
+		ReadHeaderTimeout: cfg.ServerReadHeaderTimeout,
+		WriteTimeout:      cfg.ServerWriteTimeout,
+		IdleTimeout:       cfg.ServerIdleTimeout,
+	}
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- fmt.Errorf("HTTP server: %w", err)
+		}
+	}()
+
+	slog.Info("server started", "http_addr", server.Addr, "grpc_addr", grpcListener.Addr().String())
+
+	// ============================================
+	// GRACEFUL SHUTDOWN
+	// ============================================
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case <-signalCtx.Done():
+		slog.Info("shutdown signal received")
+	case err := <-serverErr:
+		slog.Error("server failed, shutting down", "error", err)
+	}
+	// Sinyal kedua langsung menghentikan proses
+	stopSignals()
+
+	gracefulShutdown(shutdownSteps{
+		delay:          cfg.ShutdownDelay,
+		timeout:        cfg.ShutdownTimeout,
+		stopAccepting:  healthChecks.SetShuttingDown,
+		closeStreams:   eventLog.Close,
+		drainHTTP:      server.Shutdown,
+		closeHTTP:      server.Close,
+		drainGRPC:      grpcServer.Shutdown,
+		drainWebSocket: hub.Close,
+		stopWorkers: func() {
+			stopWorkers()
+			workers.Wait()
+		},
+		closeDatabase: func() error {
+			return errors.Join(dbCluster.Close(), sqlDB.Close())
+		},
+		flushTraces: shutdownTracing,
+	})
 
-	if err := router.Run(":" + cfg.ServerPort); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	slog.Info("server stopped")
+}
+
+// fatal mencatat error lalu menghentikan proses
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// buildVersion versi dari ldflags, atau commit VCS jika dibangun tanpa ldflags
+func buildVersion() string {
+	if version != "dev" {
+		return version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && len(setting.Value) >= 12 {
+			return version + "+" + setting.Value[:12]
+		}
 	}
+	return version
 }
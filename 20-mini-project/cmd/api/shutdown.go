@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// shutdownSteps komponen yang dihentikan saat graceful shutdown. Setiap
+// field berupa fungsi agar urutan dan timeout bisa dites tanpa server asli.
+type shutdownSteps struct {
+	// delay jeda setelah readiness gagal sebelum mulai drain
+	delay time.Duration
+	// timeout batas waktu bersama untuk drain HTTP, gRPC dan WebSocket
+	timeout time.Duration
+
+	// stopAccepting membuat readiness gagal agar load balancer berhenti mengirim request
+	stopAccepting func()
+	// closeStreams mengakhiri stream SSE dan gRPC WatchTodos
+	closeStreams func()
+	// drainHTTP menunggu request HTTP selesai; closeHTTP memutus sisanya jika deadline lewat
+	drainHTTP func(ctx context.Context) error
+	closeHTTP func() error
+	// drainGRPC menunggu RPC selesai dan membatalkan sisanya saat deadline lewat
+	drainGRPC func(ctx context.Context)
+	// drainWebSocket mengirim close frame dan menunggu client WebSocket keluar
+	drainWebSocket func(ctx context.Context) error
+	// stopWorkers menghentikan webhook dispatcher, replica watcher dan worker lain lalu menunggunya
+	stopWorkers func()
+	// closeDatabase menutup connection pool replica dan primary
+	closeDatabase func() error
+	// flushTraces mengirim span yang tersisa
+	flushTraces func(ctx context.Context) error
+}
+
+// gracefulShutdown menghentikan server secara berurutan: berhenti menerima
+// request, drain HTTP, gRPC dan WebSocket sampai timeout, hentikan background
+// worker, lalu tutup database. Step berikutnya tetap dijalankan walaupun drain
+// melewati timeout.
+func gracefulShutdown(steps shutdownSteps) {
+	// 1. Readiness gagal, beri waktu load balancer berhenti mengirim request baru
+	steps.stopAccepting()
+	slog.Info("readiness disabled, waiting before draining", "delay", steps.delay)
+	time.Sleep(steps.delay)
+
+	// 2. Drain request yang sedang berjalan sampai deadline. Stream SSE dan gRPC
+	// WatchTodos diakhiri lewat event log, WebSocket diputus dengan close frame.
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), steps.timeout)
+	defer cancelShutdown()
+
+	steps.closeStreams()
+	if err := steps.drainHTTP(shutdownCtx); err != nil {
+		slog.Error("HTTP server did not drain in time, closing remaining connections", "error", err)
+		steps.closeHTTP()
+	}
+	steps.drainGRPC(shutdownCtx)
+	if err := steps.drainWebSocket(shutdownCtx); err != nil {
+		slog.Error("websocket clients did not disconnect in time", "error", err)
+	}
+
+	// 3. Hentikan background worker setelah tidak ada request yang bisa menambah pekerjaan
+	steps.stopWorkers()
+
+	// 4. Tutup connection pool dan kirim span yang tersisa
+	if err := steps.closeDatabase(); err != nil {
+		slog.Error("failed to close database", "error", err)
+	}
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := steps.flushTraces(flushCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+}
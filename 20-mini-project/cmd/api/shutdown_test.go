@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder mencatat urutan step shutdown yang dipanggil
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) add(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *recorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.steps...)
+}
+
+// newShutdownSteps step yang hanya mencatat namanya dan langsung selesai
+func newShutdownSteps(r *recorder) shutdownSteps {
+	return shutdownSteps{
+		delay:          10 * time.Millisecond,
+		timeout:        time.Second,
+		stopAccepting:  func() { r.add("stop accepting") },
+		closeStreams:   func() { r.add("close streams") },
+		drainHTTP:      func(context.Context) error { r.add("drain http"); return nil },
+		closeHTTP:      func() error { r.add("close http"); return nil },
+		drainGRPC:      func(context.Context) { r.add("drain grpc") },
+		drainWebSocket: func(context.Context) error { r.add("drain websocket"); return nil },
+		stopWorkers:    func() { r.add("stop workers") },
+		closeDatabase:  func() error { r.add("close database"); return nil },
+		flushTraces:    func(context.Context) error { r.add("flush traces"); return nil },
+	}
+}
+
+func TestGracefulShutdownOrder(t *testing.T) {
+	r := &recorder{}
+	steps := newShutdownSteps(r)
+
+	// Drain baru boleh dimulai setelah jeda readiness
+	var stoppedAt, drainStartedAt time.Time
+	steps.stopAccepting = func() { stoppedAt = time.Now(); r.add("stop accepting") }
+	steps.closeStreams = func() { drainStartedAt = time.Now(); r.add("close streams") }
+
+	gracefulShutdown(steps)
+
+	assert.Equal(t, []string{
+		"stop accepting",
+		"close streams",
+		"drain http",
+		"drain grpc",
+		"drain websocket",
+		"stop workers",
+		"close database",
+		"flush traces",
+	}, r.list())
+	assert.GreaterOrEqual(t, drainStartedAt.Sub(stoppedAt), steps.delay)
+}
+
+func TestGracefulShutdownTimeout(t *testing.T) {
+	r := &recorder{}
+	steps := newShutdownSteps(r)
+	steps.delay = 0
+	steps.timeout = 50 * time.Millisecond
+
+	// Request HTTP, RPC dan client WebSocket yang tidak pernah selesai sendiri
+	var deadlines []time.Time
+	var mu sync.Mutex
+	wait := func(ctx context.Context) error {
+		<-ctx.Done()
+		deadline, _ := ctx.Deadline()
+		mu.Lock()
+		deadlines = append(deadlines, deadline)
+		mu.Unlock()
+		return ctx.Err()
+	}
+	steps.drainHTTP = func(ctx context.Context) error { r.add("drain http"); return wait(ctx) }
+	steps.drainGRPC = func(ctx context.Context) { r.add("drain grpc"); wait(ctx) }
+	steps.drainWebSocket = func(ctx context.Context) error { r.add("drain websocket"); return wait(ctx) }
+	steps.closeDatabase = func() error { r.add("close database"); return errors.New("already closed") }
+
+	start := time.Now()
+	gracefulShutdown(steps)
+	elapsed := time.Since(start)
+
+	// Satu deadline bersama, bukan timeout per komponen
+	assert.Less(t, elapsed, 3*steps.timeout)
+	assert.Len(t, deadlines, 3)
+	for _, deadline := range deadlines {
+		assert.Equal(t, deadlines[0], deadline)
+	}
+
+	// Koneksi HTTP yang tersisa diputus, worker dan database tetap dihentikan
+	assert.Equal(t, []string{
+		"stop accepting",
+		"close streams",
+		"drain http",
+		"close http",
+		"drain grpc",
+		"drain websocket",
+		"stop workers",
+		"close database",
+		"flush traces",
+	}, r.list())
+}
@@ -0,0 +1,186 @@
+// Command migrate mengelola schema database dengan migrasi SQL di internal/migrate.
+//
+//	go run ./cmd/migrate [flag config] up          # terapkan semua migrasi tertunda
+//	go run ./cmd/migrate [flag config] down [N]    # rollback N migrasi terakhir (default 1)
+//	go run ./cmd/migrate [flag config] status      # daftar versi dan statusnya
+//	go run ./cmd/migrate create [--dir DIR] add_todo_labels  # buat file up/down baru
+//
+// Koneksi database memakai config yang sama dengan API (file, env, flag).
+// create hanya menulis file ke DIR (default internal/migrate/sql, relatif
+// terhadap direktori kerja) dan tidak membaca config.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/logging"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
+)
+
+const usage = `usage: migrate [config flags] <command> [args]
+       migrate create [--dir DIR] NAME
+
+commands:
+  up              apply all pending migrations
+  down [N]        roll back the last N applied migrations (default 1)
+  status          list migrations and whether they are applied
+  create NAME     create empty up/down files for every driver in DIR (default ` + migrate.Dir + `)
+
+run "migrate --help" to list config flags`
+
+func main() {
+	// create tidak butuh database, jadi config tidak dimuat maupun divalidasi
+	if len(os.Args) > 1 && os.Args[1] == "create" {
+		err := create(os.Args[2:])
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "migrate:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, args, err := config.LoadCommand("migrate", os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	slog.SetDefault(logging.New(os.Stderr, "text", logging.ParseLevel(cfg.LogLevel)))
+
+	if err := run(cfg, args[0], args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+}
+
+// create membuat pasangan file migrasi baru di --dir
+func create(args []string) error {
+	fs := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+	dir := fs.String("dir", migrate.Dir, "directory with one subdirectory of migrations per driver")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: migrate create [--dir DIR] NAME")
+	}
+	if info, err := os.Stat(*dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("migrations directory %q not found, run from the module root or pass --dir", *dir)
+	}
+
+	created, err := migrate.Create(*dir, fs.Arg(0))
+	for _, path := range created {
+		fmt.Println("created", path)
+	}
+	return err
+}
+
+// run menjalankan up, down, atau status dan menulis hasilnya ke out
+func run(cfg *config.Config, command string, args []string, out io.Writer) error {
+	switch command {
+	case "create":
+		return errors.New("create does not take config flags, usage: migrate create [--dir DIR] NAME")
+	case "up", "down", "status":
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := config.NewDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	migrations, err := migrate.Embedded(cfg.DBDriver)
+	if err != nil {
+		return err
+	}
+	migrator, err := migrate.New(sqlDB, cfg.DBDriver, migrations)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied %03d_%s\n", m.Version, m.Name)
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			if steps, err = strconv.Atoi(args[0]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[0])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Fprintln(out, "no applied migrations")
+		}
+		for _, m := range reverted {
+			fmt.Fprintf(out, "rolled back %03d_%s\n", m.Version, m.Name)
+		}
+		return nil
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", "-"
+			if s.AppliedAt != nil {
+				state, appliedAt = "applied", s.AppliedAt.Local().Format(time.RFC3339)
+			}
+			switch {
+			case s.Unknown:
+				state = "applied (unknown to this binary)"
+			case s.Modified:
+				state = "applied (modified since, checksum mismatch)"
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return w.Flush()
+	}
+	return nil
+}
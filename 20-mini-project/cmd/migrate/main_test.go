@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
+)
+
+func TestRunUpDownStatus(t *testing.T) {
+	cfg := config.Default()
+	cfg.DBDriver = config.DriverSQLite
+	cfg.DBName = filepath.Join(t.TempDir(), "migrate.db")
+	cfg.DBLogLevel = "silent"
+
+	migrations, err := migrate.Embedded(cfg.DBDriver)
+	require.NoError(t, err)
+	last := migrations[len(migrations)-1]
+
+	var out bytes.Buffer
+	require.NoError(t, run(cfg, "up", nil, &out))
+	assert.Equal(t, len(migrations), strings.Count(out.String(), "applied "))
+
+	out.Reset()
+	require.NoError(t, run(cfg, "up", nil, &out))
+	assert.Equal(t, "no pending migrations\n", out.String())
+
+	out.Reset()
+	require.NoError(t, run(cfg, "status", nil, &out))
+	assert.Equal(t, len(migrations), strings.Count(out.String(), " applied "))
+	assert.NotContains(t, out.String(), "pending")
+
+	out.Reset()
+	require.NoError(t, run(cfg, "down", nil, &out))
+	assert.Equal(t, fmt.Sprintf("rolled back %03d_%s\n", last.Version, last.Name), out.String())
+
+	out.Reset()
+	require.NoError(t, run(cfg, "status", nil, &out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, len(migrations)+1)
+	assert.Contains(t, lines[len(lines)-1], "pending")
+	assert.Contains(t, lines[1], "applied")
+
+	out.Reset()
+	require.NoError(t, run(cfg, "down", []string{"99"}, &out))
+	assert.Equal(t, len(migrations)-1, strings.Count(out.String(), "rolled back "))
+
+	out.Reset()
+	require.NoError(t, run(cfg, "down", nil, &out))
+	assert.Equal(t, "no applied migrations\n", out.String())
+
+	assert.ErrorContains(t, run(cfg, "down", []string{"0"}, &out), "invalid number of steps")
+	assert.ErrorContains(t, run(cfg, "sideways", nil, &out), "unknown command")
+	assert.ErrorContains(t, run(cfg, "create", nil, &out), "does not take config flags")
+}
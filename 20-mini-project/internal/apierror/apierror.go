@@ -0,0 +1,128 @@
+package apierror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+)
+
+// Error is an error that carries its own code
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Code.Title()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates a coded error with a message
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap attaches a code to an existing error without changing its message
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+// Errors returned by the authentication middleware
+var (
+	ErrTokenMissing   = New(CodeTokenMissing, "authorization header is missing")
+	ErrTokenMalformed = New(CodeTokenMalformed, "authorization header must be 'Bearer <token>'")
+	// ErrMonitoringDisabled and ErrMonitoringToken guard /metrics
+	ErrMonitoringDisabled = New(CodeForbidden, "monitoring endpoints are disabled, set MONITORING_TOKEN")
+	ErrMonitoringToken    = New(CodeTokenInvalid, "invalid monitoring token")
+)
+
+// Errors returned by the idempotency and rate limit middleware
+var (
+	ErrIdempotencyKeyInvalid = New(CodeInvalidIdempotencyKey, "Idempotency-Key must be at most 255 characters")
+	ErrRateLimitExceeded     = New(CodeRateLimitExceeded, "rate limit exceeded, see Retry-After")
+)
+
+// sentinelCodes memetakan error dari service layer ke code API
+var sentinelCodes = []struct {
+	err  error
+	code Code
+}{
+	{service.ErrUserNotFound, CodeUserNotFound},
+	{service.ErrUserExists, CodeUserAlreadyExists},
+	{service.ErrInvalidCredentials, CodeInvalidCredentials},
+	{service.ErrTodoNotFound, CodeTodoNotFound},
+	{service.ErrUnauthorizedAccess, CodeTodoAccessDenied},
+	{service.ErrTitleRequired, CodeInvalidTitle},
+	{service.ErrTitleTooLong, CodeInvalidTitle},
+	{service.ErrInvalidStatus, CodeInvalidStatus},
+	{service.ErrInvalidPriority, CodeInvalidPriority},
+	{service.ErrInvalidDueDate, CodeInvalidDueDate},
+	{service.ErrInvalidDependency, CodeInvalidDependency},
+	{service.ErrDependencyCycle, CodeDependencyCycle},
+	{service.ErrTodoBlocked, CodeTodoBlocked},
+	{service.ErrWIPLimitReached, CodeWIPLimitReached},
+	{service.ErrTodoModified, CodeTodoModified},
+	{service.ErrInvalidExpand, CodeInvalidExpand},
+	{service.ErrShareNotFound, CodeShareNotFound},
+	{service.ErrShareWithSelf, CodeShareWithSelf},
+	{service.ErrTemplateNotFound, CodeTemplateNotFound},
+	{service.ErrUnauthorizedTemplateAccess, CodeTemplateAccessDenied},
+	{service.ErrInvalidStartDate, CodeInvalidStartDate},
+	{service.ErrWebhookNotFound, CodeWebhookNotFound},
+	{service.ErrUnauthorizedWebhookAccess, CodeWebhookAccessDenied},
+	{service.ErrInvalidWebhookURL, CodeInvalidWebhookURL},
+	{service.ErrDeliveryNotFound, CodeDeliveryNotFound},
+	{service.ErrInvalidSyncToken, CodeInvalidSyncToken},
+	{service.ErrIdempotencyKeyReused, CodeIdempotencyKeyReused},
+	{service.ErrIdempotencyInProgress, CodeIdempotencyRequestInProgress},
+}
+
+// CodeOf returns the code for an error, falling back to a generic code for the status
+func CodeOf(status int, err error) Code {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+
+	if isValidationError(err) {
+		return CodeValidationFailed
+	}
+	if isMalformedBody(err) {
+		return CodeMalformedBody
+	}
+
+	// Error 5xx tidak boleh memakai code spesifik agar detail internal tidak bocor
+	if status < http.StatusInternalServerError {
+		for _, sentinel := range sentinelCodes {
+			if errors.Is(err, sentinel.err) {
+				return sentinel.code
+			}
+		}
+	}
+
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	default:
+		return CodeInternal
+	}
+}
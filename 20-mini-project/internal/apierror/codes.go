@@ -0,0 +1,117 @@
+package apierror
+
+// Code is a stable, machine-readable error identifier returned by API v2.
+// Codes are part of the public contract: add new ones, never rename.
+type Code string
+
+// Generic codes, also used as fallback based on the HTTP status
+const (
+	CodeBadRequest       Code = "bad_request"
+	CodeValidationFailed Code = "validation_failed"
+	CodeMalformedBody    Code = "malformed_body"
+	CodeInvalidID        Code = "invalid_id"
+	CodeInvalidFields    Code = "invalid_fields"
+	CodeInvalidExpand    Code = "invalid_expand"
+	CodeUnauthorized     Code = "unauthorized"
+	CodeForbidden        Code = "forbidden"
+	CodeNotFound         Code = "not_found"
+	CodeConflict         Code = "conflict"
+	CodeInternal         Code = "internal_error"
+)
+
+// Authentication codes
+const (
+	CodeTokenMissing       Code = "token_missing"
+	CodeTokenMalformed     Code = "token_malformed"
+	CodeTokenInvalid       Code = "token_invalid"
+	CodeInvalidCredentials Code = "invalid_credentials"
+)
+
+// Resource codes
+const (
+	CodeUserNotFound         Code = "user_not_found"
+	CodeUserAlreadyExists    Code = "user_already_exists"
+	CodeTodoNotFound         Code = "todo_not_found"
+	CodeTodoAccessDenied     Code = "todo_access_denied"
+	CodeInvalidTitle         Code = "invalid_title"
+	CodeInvalidStatus        Code = "invalid_status"
+	CodeInvalidPriority      Code = "invalid_priority"
+	CodeInvalidDueDate       Code = "invalid_due_date"
+	CodeInvalidDependency    Code = "invalid_dependency"
+	CodeDependencyCycle      Code = "dependency_cycle"
+	CodeTodoBlocked          Code = "todo_blocked"
+	CodeWIPLimitReached      Code = "wip_limit_reached"
+	CodeTodoModified         Code = "todo_modified"
+	CodeShareNotFound        Code = "share_not_found"
+	CodeShareWithSelf        Code = "share_with_self"
+	CodeTemplateNotFound     Code = "template_not_found"
+	CodeTemplateAccessDenied Code = "template_access_denied"
+	CodeInvalidStartDate     Code = "invalid_start_date"
+	CodeWebhookNotFound      Code = "webhook_not_found"
+	CodeWebhookAccessDenied  Code = "webhook_access_denied"
+	CodeInvalidWebhookURL    Code = "invalid_webhook_url"
+	CodeDeliveryNotFound     Code = "delivery_not_found"
+	CodeInvalidSyncToken     Code = "invalid_sync_token"
+)
+
+// Idempotency codes
+const (
+	CodeInvalidIdempotencyKey        Code = "invalid_idempotency_key"
+	CodeIdempotencyKeyReused         Code = "idempotency_key_reused"
+	CodeIdempotencyRequestInProgress Code = "idempotency_request_in_progress"
+)
+
+// Throttling codes
+const (
+	CodeRateLimitExceeded Code = "rate_limit_exceeded"
+)
+
+// titles adalah ringkasan tetap (bahasa Inggris) untuk setiap code
+var titles = map[Code]string{
+	CodeBadRequest:                   "Bad request",
+	CodeValidationFailed:             "Request validation failed",
+	CodeMalformedBody:                "Request body is not valid JSON",
+	CodeInvalidID:                    "Invalid resource ID",
+	CodeInvalidFields:                "Unknown field in fields parameter",
+	CodeInvalidExpand:                "Unsupported relation in expand parameter",
+	CodeUnauthorized:                 "Authentication required",
+	CodeForbidden:                    "Access denied",
+	CodeNotFound:                     "Resource not found",
+	CodeConflict:                     "Conflict with current state",
+	CodeInternal:                     "Internal server error",
+	CodeTokenMissing:                 "Authorization token is missing",
+	CodeTokenMalformed:               "Authorization header is malformed",
+	CodeTokenInvalid:                 "Authorization token is invalid or expired",
+	CodeInvalidCredentials:           "Invalid username or password",
+	CodeUserNotFound:                 "User not found",
+	CodeUserAlreadyExists:            "Username or email already exists",
+	CodeTodoNotFound:                 "Todo not found",
+	CodeTodoAccessDenied:             "Todo belongs to another user",
+	CodeInvalidStatus:                "Invalid todo status",
+	CodeInvalidPriority:              "Invalid todo priority",
+	CodeInvalidDueDate:               "Invalid due date",
+	CodeInvalidDependency:            "Dependency todo not found",
+	CodeDependencyCycle:              "Dependency would create a cycle",
+	CodeTodoBlocked:                  "Todo is blocked by unfinished todos",
+	CodeWIPLimitReached:              "Work-in-progress limit reached",
+	CodeTemplateNotFound:             "Template not found",
+	CodeTemplateAccessDenied:         "Template belongs to another user",
+	CodeInvalidStartDate:             "Invalid start date",
+	CodeWebhookNotFound:              "Webhook not found",
+	CodeWebhookAccessDenied:          "Webhook belongs to another user",
+	CodeInvalidWebhookURL:            "Invalid webhook URL",
+	CodeDeliveryNotFound:             "Webhook delivery not found",
+	CodeInvalidSyncToken:             "Invalid sync token",
+	CodeInvalidIdempotencyKey:        "Idempotency key is invalid",
+	CodeIdempotencyKeyReused:         "Idempotency key was used with a different request",
+	CodeIdempotencyRequestInProgress: "Request with this idempotency key is still in progress",
+	CodeRateLimitExceeded:            "Too many requests",
+}
+
+// Title returns the fixed summary for a code
+func (c Code) Title() string {
+	if title, ok := titles[c]; ok {
+		return title
+	}
+	return string(c)
+}
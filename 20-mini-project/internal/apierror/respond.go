@@ -0,0 +1,77 @@
+package apierror
+
+import (
+	"net/http"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// VersionKey adalah key gin context berisi versi API yang melayani request
+const VersionKey = "apiVersion"
+
+// ProblemContentType is the media type of RFC 7807 responses
+const ProblemContentType = "application/problem+json"
+
+// Respond writes an error response in the format of the API version serving
+// the request: v1 keeps dto.ErrorResponse, v2 uses RFC 7807 problem details
+// with a stable code derived from err (or from status when err is unknown).
+func Respond(c *gin.Context, status int, message string, err error) {
+	if c.GetInt(VersionKey) >= 2 {
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(status, Problem(c, status, message, err))
+		return
+	}
+
+	response := dto.ErrorResponse{
+		Success: false,
+		Message: message,
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	c.JSON(status, response)
+}
+
+// Abort is Respond followed by c.Abort, for use in middleware
+func Abort(c *gin.Context, status int, message string, err error) {
+	Respond(c, status, message, err)
+	c.Abort()
+}
+
+// Problem builds the problem details for an error
+func Problem(c *gin.Context, status int, message string, err error) dto.ProblemDetails {
+	code := CodeOf(status, err)
+
+	problem := dto.ProblemDetails{
+		Type:     "/problems/" + string(code),
+		Title:    code.Title(),
+		Status:   status,
+		Detail:   detail(status, code, message, err),
+		Instance: c.Request.URL.Path,
+		Code:     string(code),
+	}
+	if code == CodeValidationFailed {
+		problem.Errors = fieldErrors(err)
+	}
+	return problem
+}
+
+// detail memilih penjelasan yang aman ditampilkan ke client
+func detail(status int, code Code, message string, err error) string {
+	switch {
+	case status >= http.StatusInternalServerError:
+		// Jangan bocorkan pesan error internal (query, driver, dll)
+		return message
+	case code == CodeValidationFailed:
+		return "One or more fields are invalid, see errors"
+	case code == CodeMalformedBody:
+		return "Request body could not be parsed as JSON"
+	case code == CodeInvalidID:
+		return message
+	case err != nil:
+		return err.Error()
+	default:
+		return message
+	}
+}
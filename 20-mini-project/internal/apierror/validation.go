@@ -0,0 +1,125 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/go-playground/validator/v10"
+)
+
+func isValidationError(err error) bool {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &validationErrs) || errors.As(err, &typeErr)
+}
+
+func isMalformedBody(err error) bool {
+	var syntaxErr *json.SyntaxError
+	return errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// fieldErrors converts binding errors into per-field details
+func fieldErrors(err error) []dto.FieldError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []dto.FieldError{{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: fmt.Sprintf("must be of type %s", jsonType(typeErr.Type.Kind().String())),
+		}}
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	result := make([]dto.FieldError, len(validationErrs))
+	for i, fe := range validationErrs {
+		result[i] = dto.FieldError{
+			Field:   fieldPath(fe.StructNamespace()),
+			Code:    fe.Tag(),
+			Message: fieldMessage(fe),
+		}
+	}
+	return result
+}
+
+// fieldMessage builds an English message for a failed validation rule
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min":
+		if fe.Kind().String() == "string" {
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if fe.Kind().String() == "string" {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	default:
+		return "failed the " + fe.Tag() + " rule"
+	}
+}
+
+// fieldPath mengubah namespace struct (CreateTodoRequest.DueDate, SyncPushRequest.Mutations[0].Op)
+// menjadi path JSON (due_date, mutations[0].op). Semua DTO memakai json tag snake_case.
+func fieldPath(namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 1 {
+		parts = parts[1:] // Buang nama struct request
+	}
+	for i, part := range parts {
+		name, index := part, ""
+		if pos := strings.Index(part, "["); pos >= 0 {
+			name, index = part[:pos], part[pos:]
+		}
+		parts[i] = toSnakeCase(name) + index
+	}
+	return strings.Join(parts, ".")
+}
+
+// toSnakeCase converts Go field names like DueDate, WIPLimit or URL to due_date, wip_limit, url
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (prevLower || (nextLower && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func jsonType(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "number"
+	case kind == "slice" || kind == "array":
+		return "array"
+	case kind == "struct" || kind == "map" || kind == "ptr":
+		return "object"
+	default:
+		return kind
+	}
+}
@@ -1,38 +1,206 @@
 package config
 
-import "os"
+import (
+	"time"
+	// Database zona waktu ikut di-embed agar APP_TIMEZONE tetap valid di image tanpa tzdata
+	_ "time/tzdata"
+)
 
-// Config menyimpan konfigurasi aplikasi
+// DefaultJWTSecret secret bawaan untuk development. Server menolak start
+// dengan secret ini di GIN_MODE=release.
+const DefaultJWTSecret = "your-super-secret-key-change-this-in-production"
+
+// Driver database yang didukung DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// SQLiteMemory nilai DB_NAME untuk database SQLite in-memory
+const SQLiteMemory = ":memory:"
+
+// Config menyimpan konfigurasi aplikasi.
+//
+// Setiap field bisa diisi dari file config, environment variable, dan flag
+// command line (lihat Load). Tag env adalah nama environment variable; key di
+// file config adalah versi lowercase-nya (db_host) dan flag memakai tanda
+// hubung (--db-host). Field bertag reload:"true" bisa diubah tanpa restart
+// lewat Live.Reload.
 type Config struct {
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	JWTSecret  string
-	ServerPort string
-	GinMode    string
+	// DBDriver driver database: postgres atau sqlite. Untuk sqlite, DB_NAME adalah
+	// path file database atau :memory:, dan DB_HOST/DB_PORT/DB_USER diabaikan.
+	DBDriver   string `env:"DB_DRIVER"`
+	DBHost     string `env:"DB_HOST"`
+	DBPort     int    `env:"DB_PORT"`
+	DBUser     string `env:"DB_USER"`
+	DBPassword string `env:"DB_PASSWORD"`
+	DBName     string `env:"DB_NAME"`
+	JWTSecret  string `env:"JWT_SECRET"`
+	ServerPort int    `env:"SERVER_PORT"`
+	GRPCPort   int    `env:"GRPC_PORT"`
+	GinMode    string `env:"GIN_MODE"`
+
+	// File path file config yang dimuat, kosong jika hanya memakai env dan flag
+	File string
+
+	// CORSAllowedOrigins origin yang boleh memanggil API dari browser, "*" untuk semua
+	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" reload:"true"`
+	// TrustedProxies IP atau CIDR proxy yang header X-Forwarded-For-nya dipercaya
+	// untuk menentukan IP client (rate limit, log). Kosong berarti IP koneksi
+	// langsung yang dipakai dan header tersebut diabaikan.
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
+
+	// ServerReadTimeout batas waktu membaca seluruh request (header + body)
+	ServerReadTimeout time.Duration `env:"SERVER_READ_TIMEOUT"`
+	// ServerReadHeaderTimeout batas waktu membaca header request
+	ServerReadHeaderTimeout time.Duration `env:"SERVER_READ_HEADER_TIMEOUT"`
+	// ServerWriteTimeout batas waktu menulis response; SSE dan WebSocket dikecualikan
+	ServerWriteTimeout time.Duration `env:"SERVER_WRITE_TIMEOUT"`
+	// ServerIdleTimeout batas waktu koneksi keep-alive yang menganggur
+	ServerIdleTimeout time.Duration `env:"SERVER_IDLE_TIMEOUT"`
+	// ShutdownDelay jeda antara readiness gagal dan mulai drain, agar load balancer
+	// sempat berhenti mengirim request baru
+	ShutdownDelay time.Duration `env:"SHUTDOWN_DELAY"`
+	// ShutdownTimeout batas waktu menunggu request yang sedang berjalan saat shutdown
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT"`
+
+	// LogLevel level log aplikasi: debug, info, warn, error
+	LogLevel string `env:"LOG_LEVEL" reload:"true"`
+	// LogFormat format log: json atau text
+	LogFormat string `env:"LOG_FORMAT"`
+	// DBLogLevel level log query GORM: silent, error, warn, info (info mencatat semua query)
+	DBLogLevel string `env:"DB_LOG_LEVEL"`
+	// DBSlowQueryThreshold query yang lebih lambat dari ini dicatat sebagai warning
+	DBSlowQueryThreshold time.Duration `env:"DB_SLOW_QUERY_THRESHOLD"`
+	// DBSSLMode mode TLS koneksi Postgres: disable, allow, prefer, require, verify-ca, verify-full
+	DBSSLMode string `env:"DB_SSLMODE"`
+	// DBSSLRootCert path CA untuk verify-ca/verify-full, kosong berarti CA sistem
+	DBSSLRootCert string `env:"DB_SSLROOTCERT"`
+	// DBSSLCert dan DBSSLKey path sertifikat dan key client untuk mutual TLS
+	DBSSLCert string `env:"DB_SSLCERT"`
+	DBSSLKey  string `env:"DB_SSLKEY"`
+	// DBMaxOpenConns batas koneksi terbuka ke database, 0 berarti tanpa batas
+	DBMaxOpenConns int `env:"DB_MAX_OPEN_CONNS"`
+	// DBMaxIdleConns jumlah koneksi idle yang disimpan di pool
+	DBMaxIdleConns int `env:"DB_MAX_IDLE_CONNS"`
+	// DBConnMaxLifetime umur maksimal satu koneksi sebelum diganti, 0 berarti selamanya
+	DBConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME"`
+	// DBConnMaxIdleTime lama koneksi boleh idle sebelum ditutup, 0 berarti selamanya
+	DBConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME"`
+	// DBConnectTimeout batas waktu mencoba ulang koneksi awal saat start, misalnya
+	// ketika Postgres di docker-compose belum siap menerima koneksi
+	DBConnectTimeout time.Duration `env:"DB_CONNECT_TIMEOUT"`
+	// DBMigrateOnStart menjalankan migrasi yang belum diterapkan saat API start.
+	// Matikan jika migrasi dijalankan terpisah lewat cmd/migrate.
+	DBMigrateOnStart bool `env:"DB_MIGRATE_ON_START"`
+	// DBReplicaHosts read replica Postgres (host atau host:port, dipisah koma)
+	// untuk query read-only. User, password, database, TLS dan pool sama dengan
+	// primary. Kosong berarti semua query ke primary.
+	DBReplicaHosts []string `env:"DB_REPLICA_HOSTS"`
+	// DBReplicaCheckInterval jarak antar health check replica
+	DBReplicaCheckInterval time.Duration `env:"DB_REPLICA_CHECK_INTERVAL"`
+	// DBReplicaMaxLag replica yang tertinggal lebih dari ini tidak dipakai
+	// sampai mengejar, 0 berarti lag tidak diperiksa
+	DBReplicaMaxLag time.Duration `env:"DB_REPLICA_MAX_LAG"`
+
+	// TracingExporter exporter OpenTelemetry: none, stdout, otlp
+	TracingExporter string `env:"OTEL_TRACES_EXPORTER"`
+	// TracingServiceName nama service di trace (service.name)
+	TracingServiceName string `env:"OTEL_SERVICE_NAME"`
+	// OTLPEndpoint alamat collector OTLP gRPC
+	OTLPEndpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	// OTLPInsecure mengirim trace ke collector tanpa TLS
+	OTLPInsecure bool `env:"OTEL_EXPORTER_OTLP_INSECURE"`
+	// TracingSampleRatio fraksi trace baru yang direkam (0..1)
+	TracingSampleRatio float64 `env:"OTEL_TRACES_SAMPLER_ARG"`
+
+	// Timezone zona waktu IANA aplikasi, dipakai sesi database Postgres dan
+	// untuk menentukan tanggal "hari ini" (misalnya start_date template)
+	Timezone string `env:"APP_TIMEZONE"`
+
+	// TodoBlockCompletion menolak status completed selama masih ada blocker yang terbuka
+	TodoBlockCompletion bool `env:"TODO_BLOCK_COMPLETION" reload:"true"`
+	// WIPLimit batas default todo in_progress per user, 0 berarti tanpa batas
+	WIPLimit int `env:"WIP_LIMIT" reload:"true"`
+	// EventLogSize jumlah event terakhir yang disimpan untuk resume SSE (Last-Event-ID)
+	EventLogSize int `env:"EVENT_LOG_SIZE"`
+	// IdempotencyTTL lama response Idempotency-Key disimpan untuk di-replay
+	IdempotencyTTL time.Duration `env:"IDEMPOTENCY_TTL"`
+	// HealthCheckTimeout batas waktu setiap check di /health/ready
+	HealthCheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT"`
+	// MonitoringToken bearer token untuk /metrics dan detail /health/ready.
+	// Kosong berarti /metrics ditutup dan readiness hanya menampilkan status.
+	MonitoringToken string `env:"MONITORING_TOKEN"`
+
+	// RateLimitRequests dan RateLimitPeriod limit default per user untuk endpoint protected, 0 berarti tanpa batas
+	RateLimitRequests int           `env:"RATE_LIMIT_REQUESTS" reload:"true"`
+	RateLimitPeriod   time.Duration `env:"RATE_LIMIT_PERIOD" reload:"true"`
+	// AuthRateLimitRequests dan AuthRateLimitPeriod limit per IP untuk login dan register
+	AuthRateLimitRequests int           `env:"AUTH_RATE_LIMIT_REQUESTS" reload:"true"`
+	AuthRateLimitPeriod   time.Duration `env:"AUTH_RATE_LIMIT_PERIOD" reload:"true"`
+	// IPRateLimitRequests dan IPRateLimitPeriod limit per IP sebelum autentikasi,
+	// sehingga request dengan token tidak valid juga dibatasi
+	IPRateLimitRequests int           `env:"IP_RATE_LIMIT_REQUESTS" reload:"true"`
+	IPRateLimitPeriod   time.Duration `env:"IP_RATE_LIMIT_PERIOD" reload:"true"`
 }
 
-// LoadConfig memuat konfigurasi dari environment variables
-func LoadConfig() *Config {
+// Default mengembalikan konfigurasi bawaan, dipakai sebelum file, env dan flag diterapkan
+func Default() *Config {
 	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", "postgres"),
-		DBName:     getEnv("DB_NAME", "todolist_db"),
-		JWTSecret:  getEnv("JWT_SECRET", "your-super-secret-key-change-this-in-production"),
-		ServerPort: getEnv("SERVER_PORT", "8080"),
-		GinMode:    getEnv("GIN_MODE", "debug"),
-	}
-}
+		DBDriver:   DriverPostgres,
+		DBHost:     "localhost",
+		DBPort:     5432,
+		DBUser:     "postgres",
+		DBPassword: "postgres",
+		DBName:     "todolist_db",
+		JWTSecret:  DefaultJWTSecret,
+		ServerPort: 8080,
+		GRPCPort:   9090,
+		GinMode:    "debug",
+
+		CORSAllowedOrigins: []string{"*"},
+
+		ServerReadTimeout:       15 * time.Second,
+		ServerReadHeaderTimeout: 5 * time.Second,
+		ServerWriteTimeout:      30 * time.Second,
+		ServerIdleTimeout:       60 * time.Second,
+		ShutdownDelay:           5 * time.Second,
+		ShutdownTimeout:         20 * time.Second,
+
+		LogLevel:             "info",
+		LogFormat:            "json",
+		DBLogLevel:           "warn",
+		DBSlowQueryThreshold: 200 * time.Millisecond,
+		DBMigrateOnStart:     true,
+		DBSSLMode:            "disable",
+		DBMaxOpenConns:       25,
+		DBMaxIdleConns:       10,
+		DBConnMaxLifetime:    30 * time.Minute,
+		DBConnMaxIdleTime:    5 * time.Minute,
+		DBConnectTimeout:     time.Minute,
+
+		DBReplicaCheckInterval: 5 * time.Second,
+		DBReplicaMaxLag:        10 * time.Second,
+
+		TracingExporter:    "none",
+		TracingServiceName: "todolist-api",
+		OTLPEndpoint:       "localhost:4317",
+		OTLPInsecure:       true,
+		TracingSampleRatio: 1.0,
+
+		Timezone: "Asia/Jakarta",
+
+		TodoBlockCompletion: true,
+		WIPLimit:            0,
+		EventLogSize:        1000,
+		IdempotencyTTL:      24 * time.Hour,
+		HealthCheckTimeout:  2 * time.Second,
 
-// getEnv mendapatkan environment variable dengan default value
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+		RateLimitRequests:     100,
+		RateLimitPeriod:       time.Minute,
+		AuthRateLimitRequests: 10,
+		AuthRateLimitPeriod:   time.Minute,
+		IPRateLimitRequests:   300,
+		IPRateLimitPeriod:     time.Minute,
 	}
-	return value
 }
@@ -1,13 +1,24 @@
 package config
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/logging"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // DatabaseConfig holds database configuration
@@ -19,28 +30,210 @@ type DatabaseConfig struct {
 	DBName   string
 }
 
-// NewDatabase creates a new database connection
+const (
+	// dbConnectBaseBackoff jeda sebelum percobaan koneksi kedua, berlipat dua setiap percobaan
+	dbConnectBaseBackoff = 500 * time.Millisecond
+	// dbConnectMaxBackoff jeda maksimal antar percobaan koneksi
+	dbConnectMaxBackoff = 10 * time.Second
+	// dbPingTimeout batas waktu satu percobaan koneksi
+	dbPingTimeout = 5 * time.Second
+)
+
+// NewDatabase creates a new database connection for cfg.DBDriver, retrying
+// until the database accepts connections or DBConnectTimeout passes
 func NewDatabase(cfg *Config) (*gorm.DB, error) {
-	// Connection string PostgreSQL
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta",
-		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort)
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
 
-	// Koneksi ke database dengan logging
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	if err := waitForDatabase(sqlDB, cfg.DBConnectTimeout); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to connect database: %w", err)
+	}
+
+	if cfg.DBDriver == DriverSQLite {
+		slog.Info("connected to database", "driver", cfg.DBDriver, "name", cfg.DBName)
+	} else {
+		slog.Info("connected to database", "driver", cfg.DBDriver, "host", cfg.DBHost, "name", cfg.DBName, "sslmode", cfg.DBSSLMode)
+	}
+
+	// Schema dikelola lewat migrasi SQL di internal/migrate, bukan AutoMigrate
+	return db, nil
+}
+
+// NewReplicas membuka connection pool ke setiap DB_REPLICA_HOSTS dengan
+// kredensial, TLS dan pengaturan pool yang sama dengan primary. Replica yang
+// belum bisa dihubungi tidak menggagalkan start; health check di
+// database.Cluster yang menentukan kapan replica mulai dipakai.
+func NewReplicas(cfg *Config) ([]database.Replica, error) {
+	replicas := make([]database.Replica, 0, len(cfg.DBReplicaHosts))
+	for _, entry := range cfg.DBReplicaHosts {
+		host, port, err := replicaAddress(entry, cfg.DBPort)
+		if err != nil {
+			closeReplicas(replicas)
+			return nil, fmt.Errorf("invalid replica %q: %w", entry, err)
+		}
+
+		replicaCfg := *cfg
+		replicaCfg.DBHost, replicaCfg.DBPort = host, port
+		db, err := openDatabase(&replicaCfg)
+		if err != nil {
+			closeReplicas(replicas)
+			return nil, fmt.Errorf("replica %s: %w", entry, err)
+		}
+		replicas = append(replicas, database.Replica{Name: net.JoinHostPort(host, strconv.Itoa(port)), DB: db})
+	}
+	return replicas, nil
+}
+
+// openDatabase membuka connection pool tanpa menunggu database siap
+func openDatabase(cfg *Config) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.DBDriver {
+	case DriverSQLite:
+		dialector = sqlite.Open(sqliteDSN(cfg.DBName))
+	default:
+		dialector = postgres.Open(postgresDSN(cfg))
+	}
+
+	// Koneksi ke database, log query memakai slog yang sama dengan access log.
+	// Ping otomatis dimatikan karena koneksi awal dicoba ulang di waitForDatabase.
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:               logging.NewGormLogger(slog.Default(), cfg.DBLogLevel, cfg.DBSlowQueryThreshold),
+		DisableAutomaticPing: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect database: %w", err)
 	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
 
-	log.Println("✓ Successfully connected to database")
+	if cfg.DBDriver == DriverSQLite && cfg.DBName == SQLiteMemory {
+		// Setiap koneksi SQLite in-memory punya database sendiri, jadi pool
+		// dibatasi satu koneksi yang tidak pernah ditutup
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	} else {
+		sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+		sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+	}
 
-	// Auto migrate models
-	if err := db.AutoMigrate(&model.User{}, &model.Todo{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	// Span untuk setiap query, child dari span request/service di context
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
 	}
+	return db, nil
+}
 
-	log.Println("✓ Database migration completed")
+func closeReplicas(replicas []database.Replica) {
+	for _, r := range replicas {
+		if sqlDB, err := r.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+}
 
-	return db, nil
+// replicaAddress memisahkan entry DB_REPLICA_HOSTS menjadi host dan port;
+// entry tanpa port memakai port primary. IPv6 ditulis [::1]:5432.
+func replicaAddress(entry string, defaultPort int) (string, int, error) {
+	entry = strings.TrimSpace(entry)
+	host, portStr, err := net.SplitHostPort(entry)
+	if err != nil {
+		var addrErr *net.AddrError
+		if !errors.As(err, &addrErr) || addrErr.Err != "missing port in address" {
+			return "", 0, err
+		}
+		host, portStr = strings.TrimSuffix(strings.TrimPrefix(entry, "["), "]"), strconv.Itoa(defaultPort)
+	}
+	if host == "" {
+		return "", 0, errors.New("host is required")
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("port must be between 1 and 65535, got %q", portStr)
+	}
+	return host, port, nil
+}
+
+// waitForDatabase mem-ping database sampai berhasil, dengan exponential backoff
+// dan jitter di antara percobaan, sampai timeout habis. timeout 0 berarti
+// hanya satu percobaan.
+func waitForDatabase(sqlDB *sql.DB, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), dbPingTimeout)
+		err := sqlDB.PingContext(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("database not reachable after %d attempt(s): %w", attempt, err)
+		}
+		wait := min(connectBackoff(attempt), remaining)
+		slog.Warn("database not ready, retrying", "attempt", attempt, "retry_in", wait.String(), "error", err)
+		time.Sleep(wait)
+	}
+}
+
+// connectBackoff returns base*2^(attempt-1) capped at dbConnectMaxBackoff, with
+// jitter so replicas that start together do not retry in lockstep
+func connectBackoff(attempt int) time.Duration {
+	backoff := dbConnectBaseBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > dbConnectMaxBackoff {
+		backoff = dbConnectMaxBackoff
+	}
+	// Antara 50% dan 100% dari backoff
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
+
+// postgresDSN connection string PostgreSQL format key=value. Nilai dikutip
+// agar password yang berisi spasi atau tanda kutip tetap valid.
+func postgresDSN(cfg *Config) string {
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	param := func(key, value string) string {
+		return key + "='" + quote.Replace(value) + "'"
+	}
+
+	params := []string{
+		param("host", cfg.DBHost),
+		param("port", strconv.Itoa(cfg.DBPort)),
+		param("user", cfg.DBUser),
+		param("password", cfg.DBPassword),
+		param("dbname", cfg.DBName),
+		param("sslmode", cfg.DBSSLMode),
+		param("TimeZone", cfg.Timezone),
+	}
+	if cfg.DBSSLRootCert != "" {
+		params = append(params, param("sslrootcert", cfg.DBSSLRootCert))
+	}
+	if cfg.DBSSLCert != "" {
+		params = append(params, param("sslcert", cfg.DBSSLCert), param("sslkey", cfg.DBSSLKey))
+	}
+	return strings.Join(params, " ")
+}
+
+// sqliteDSN DSN SQLite dengan foreign key aktif seperti di Postgres. Database
+// file memakai WAL dan busy_timeout agar request yang bersamaan tidak langsung
+// gagal dengan "database is locked". Path di-escape karena DSN berbentuk URI:
+// tanpa escape, ? atau # di nama file dibaca sebagai awal query/fragment.
+func sqliteDSN(name string) string {
+	if name == SQLiteMemory {
+		return "file::memory:?_pragma=foreign_keys(1)"
+	}
+	path := (&url.URL{Path: name}).EscapedPath()
+	return "file:" + path + "?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
 }
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ? dan # di path tidak boleh dibaca sebagai query atau fragment URI
+func TestSQLiteDSNEscapesPath(t *testing.T) {
+	for _, name := range []string{"todo?mode=ro.db", "todo#1.db", "todo 100%.db"} {
+		t.Run(name, func(t *testing.T) {
+			cfg := Default()
+			cfg.DBDriver = DriverSQLite
+			cfg.DBName = filepath.Join(t.TempDir(), name)
+			cfg.DBLogLevel = "silent"
+
+			db, err := NewDatabase(cfg)
+			require.NoError(t, err)
+			sqlDB, err := db.DB()
+			require.NoError(t, err)
+			t.Cleanup(func() { sqlDB.Close() })
+
+			require.NoError(t, db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY)").Error)
+			var foreignKeys int
+			require.NoError(t, db.Raw("PRAGMA foreign_keys").Scan(&foreignKeys).Error)
+			assert.Equal(t, 1, foreignKeys, "query parameters still applied")
+
+			_, err = os.Stat(cfg.DBName)
+			assert.NoError(t, err, "database file created at the configured path")
+		})
+	}
+}
+
+func TestConnectBackoff(t *testing.T) {
+	for attempt := 1; attempt <= 70; attempt++ {
+		backoff := dbConnectMaxBackoff
+		if attempt < 10 {
+			backoff = min(dbConnectBaseBackoff<<(attempt-1), dbConnectMaxBackoff)
+		}
+		seen := map[time.Duration]bool{}
+		for i := 0; i < 50; i++ {
+			wait := connectBackoff(attempt)
+			require.GreaterOrEqual(t, wait, backoff/2, "attempt %d", attempt)
+			require.LessOrEqual(t, wait, backoff, "attempt %d", attempt)
+			seen[wait] = true
+		}
+		assert.Greater(t, len(seen), 1, "attempt %d has no jitter", attempt)
+	}
+}
+
+// flakyDriver gagal membuka koneksi sampai failures percobaan terlewati
+type flakyDriver struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+}
+
+func (d *flakyDriver) Open(string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.attempts++
+	if d.failures < 0 || d.attempts <= d.failures {
+		return nil, errors.New("connection refused")
+	}
+	return flakyConn{}, nil
+}
+
+type flakyConn struct{ driver.Conn }
+
+func (flakyConn) Close() error { return nil }
+
+func openFlakyDB(t *testing.T, failures int) (*sql.DB, *flakyDriver) {
+	t.Helper()
+	d := &flakyDriver{failures: failures}
+	db := sql.OpenDB(flakyConnector{d})
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+type flakyConnector struct{ d *flakyDriver }
+
+func (c flakyConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c flakyConnector) Driver() driver.Driver                        { return c.d }
+
+func TestWaitForDatabaseRetriesUntilReachable(t *testing.T) {
+	db, d := openFlakyDB(t, 1)
+	require.NoError(t, waitForDatabase(db, 10*time.Second))
+	assert.Equal(t, 2, d.attempts)
+}
+
+func TestWaitForDatabaseStopsAtDeadline(t *testing.T) {
+	db, d := openFlakyDB(t, -1)
+	start := time.Now()
+	err := waitForDatabase(db, 300*time.Millisecond)
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Contains(t, err.Error(), "not reachable after")
+	// Jeda terakhir dipotong ke sisa waktu, bukan backoff penuh
+	assert.GreaterOrEqual(t, elapsed, 300*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+	assert.GreaterOrEqual(t, d.attempts, 2)
+
+	// Timeout 0 berarti satu percobaan tanpa retry
+	db, d = openFlakyDB(t, -1)
+	require.Error(t, waitForDatabase(db, 0))
+	assert.Equal(t, 1, d.attempts)
+}
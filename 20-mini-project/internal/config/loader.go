@@ -0,0 +1,273 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig memuat konfigurasi dari file config, environment variable dan
+// flag command line proses (os.Args)
+func LoadConfig() (*Config, error) {
+	return Load(os.Args[1:])
+}
+
+// Load memuat konfigurasi dengan urutan prioritas (yang belakang menimpa yang depan):
+//
+//  1. nilai default (Default)
+//  2. file config YAML/TOML dari flag --config atau env CONFIG_FILE
+//  3. environment variable
+//  4. flag command line di args
+//
+// Nilai yang tidak bisa di-parse dan pelanggaran Validate dikumpulkan menjadi
+// satu error supaya semua masalah terlihat sekaligus. Dengan --help, Load
+// mencetak daftar flag dan mengembalikan flag.ErrHelp.
+func Load(args []string) (*Config, error) {
+	cfg, rest, err := LoadCommand("todolist-api", args)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", rest[0])
+	}
+	return cfg, nil
+}
+
+// LoadCommand sama dengan Load untuk command line tool seperti cmd/migrate:
+// argumen setelah flag terakhir (subcommand dan argumennya) dikembalikan
+// sebagai rest. name dipakai di pesan --help.
+func LoadCommand(name string, args []string) (cfg *Config, rest []string, err error) {
+	cfg = Default()
+	fields := configFields(cfg)
+
+	// Flag di-parse lebih dulu untuk mendapatkan --config, tetapi nilainya
+	// baru diterapkan setelah env agar prioritasnya paling tinggi
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path file config YAML atau TOML (env CONFIG_FILE)")
+	flagValues := make(map[string]string)
+	for _, f := range fields {
+		fs.Var(&flagValue{name: f.flag, isBool: f.value.Kind() == reflect.Bool, values: flagValues}, f.flag, "menimpa "+f.env)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	var errs []error
+
+	if *configFile != "" {
+		values, err := readFile(*configFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		byKey := make(map[string]configField, len(fields))
+		for _, f := range fields {
+			byKey[f.key] = f
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			raw := values[key]
+			f, ok := byKey[key]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown key %q", *configFile, key))
+				continue
+			}
+			if err := setFileValue(f.value, raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s: key %s: %w", *configFile, key, err))
+			}
+		}
+		cfg.File = *configFile
+	}
+
+	for _, f := range fields {
+		raw, ok := os.LookupEnv(f.env)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := setValue(f.value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("env %s: %w", f.env, err))
+		}
+	}
+
+	for _, f := range fields {
+		raw, ok := flagValues[f.flag]
+		if !ok {
+			continue
+		}
+		if err := setValue(f.value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("flag --%s: %w", f.flag, err))
+		}
+	}
+
+	// Field yang gagal di-parse tetap bernilai sebelumnya, jadi validasi tetap
+	// dijalankan agar semua masalah dilaporkan dalam satu error
+	cfg.normalize()
+	errs = append(errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return cfg, fs.Args(), nil
+}
+
+// configField satu field Config beserta nama key di setiap sumber
+type configField struct {
+	env   string // DB_HOST
+	key   string // db_host
+	flag  string // db-host
+	value reflect.Value
+}
+
+// configFields mengembalikan semua field Config yang punya tag env
+func configFields(cfg *Config) []configField {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	fields := make([]configField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		env := t.Field(i).Tag.Get("env")
+		if env == "" {
+			continue
+		}
+		key := strings.ToLower(env)
+		fields = append(fields, configField{
+			env:   env,
+			key:   key,
+			flag:  strings.ReplaceAll(key, "_", "-"),
+			value: v.Field(i),
+		})
+	}
+	return fields
+}
+
+// readFile membaca file config YAML atau TOML menjadi map key datar.
+// Section bersarang digabung dengan "_", jadi db.host sama dengan db_host.
+func readFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file %s, use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]any)
+	flatten("", raw, values)
+	return values, nil
+}
+
+func flatten(prefix string, in map[string]any, out map[string]any) {
+	for key, value := range in {
+		key = strings.ToLower(key)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flatten(key, nested, out)
+			continue
+		}
+		out[key] = value
+	}
+}
+
+// setFileValue mengisi field dari nilai file config. List di file (YAML
+// sequence / TOML array) diterima untuk field []string.
+func setFileValue(v reflect.Value, raw any) error {
+	if list, ok := raw.([]any); ok {
+		if v.Type() != reflect.TypeOf([]string(nil)) {
+			return errors.New("list is not allowed for this key")
+		}
+		items := make([]string, 0, len(list))
+		for _, item := range list {
+			items = append(items, fmt.Sprint(item))
+		}
+		v.Set(reflect.ValueOf(items))
+		return nil
+	}
+	return setValue(v, fmt.Sprint(raw))
+}
+
+// setValue mem-parse raw sesuai tipe field
+func setValue(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch v.Interface().(type) {
+	case string:
+		v.SetString(raw)
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, use e.g. 500ms, 30s, 5m", raw)
+		}
+		v.SetInt(int64(d))
+	case []string:
+		// Daftar dipisah koma: "a, b ,c"
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Type())
+	}
+	return nil
+}
+
+// flagValue menyimpan nilai flag mentah; diterapkan setelah env
+type flagValue struct {
+	name   string
+	isBool bool
+	values map[string]string
+}
+
+func (f *flagValue) String() string { return "" }
+
+func (f *flagValue) Set(raw string) error {
+	f.values[f.name] = raw
+	return nil
+}
+
+// IsBoolFlag membuat --otel-exporter-otlp-insecure bisa dipakai tanpa =true
+func (f *flagValue) IsBoolFlag() bool { return f.isBool }
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// default < file < env < flag
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfigFile(t, `
+server_port: 8081
+wip_limit: 2
+log:
+  level: warn
+rate_limit:
+  requests: 50
+`)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("WIP_LIMIT", "3")
+	t.Setenv("LOG_LEVEL", "error")
+
+	cfg, err := Load([]string{"--log-level", "debug"})
+	require.NoError(t, err)
+
+	assert.Equal(t, Default().GRPCPort, cfg.GRPCPort, "default")
+	assert.Equal(t, 8081, cfg.ServerPort, "file")
+	assert.Equal(t, 50, cfg.RateLimitRequests, "file, nested key")
+	assert.Equal(t, 3, cfg.WIPLimit, "env over file")
+	assert.Equal(t, "debug", cfg.LogLevel, "flag over env")
+	assert.Equal(t, path, cfg.File)
+}
+
+func TestLoadAggregatesErrors(t *testing.T) {
+	path := writeConfigFile(t, "unknown_key: 1\n")
+	t.Setenv("SERVER_PORT", "not-a-number")
+	t.Setenv("LOG_FORMAT", "xml")
+
+	_, err := Load([]string{"--config", path, "--wip-limit", "-1"})
+	require.Error(t, err)
+	for _, want := range []string{
+		`unknown key "unknown_key"`,
+		"env SERVER_PORT",
+		"LOG_FORMAT must be one of json, text",
+		"WIP_LIMIT must not be negative",
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadRefusesDefaultSecretInRelease(t *testing.T) {
+	for _, mode := range []string{"release", "Release", " RELEASE"} {
+		t.Run(mode, func(t *testing.T) {
+			t.Setenv("GIN_MODE", mode)
+			t.Setenv("JWT_SECRET", DefaultJWTSecret)
+
+			_, err := Load(nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "JWT_SECRET must be changed from the default value in release mode")
+
+			t.Setenv("JWT_SECRET", "a-release-secret-that-is-long-enough")
+			cfg, err := Load(nil)
+			require.NoError(t, err)
+			assert.Equal(t, "release", cfg.GinMode)
+		})
+	}
+}
+
+func TestLoadNormalizesEnumCase(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "DEBUG")
+	t.Setenv("OTEL_TRACES_EXPORTER", "Stdout")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", cfg.LogLevel)
+	assert.Equal(t, "stdout", cfg.TracingExporter)
+
+	// Config yang dibuat langsung tanpa Load tetap divalidasi apa adanya
+	cfg.GinMode = "Release"
+	assert.ErrorContains(t, cfg.Validate(), "GIN_MODE must be one of")
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// fileWatchInterval interval pengecekan perubahan file config
+const fileWatchInterval = 5 * time.Second
+
+// Live menyimpan snapshot Config yang bisa diganti saat runtime tanpa restart.
+// Hanya field bertag reload:"true" yang ikut diganti; perubahan field lain
+// dicatat sebagai warning dan baru berlaku setelah restart.
+//
+// Snapshot dari Load tidak boleh diubah. Middleware dan service membaca
+// snapshot terbaru di setiap request sehingga reload tidak memutus koneksi.
+type Live struct {
+	current atomic.Pointer[Config]
+	args    []string
+
+	mu        sync.Mutex // satu reload dalam satu waktu
+	listeners []func(old, new *Config)
+}
+
+// NewLive membuat Live dari config awal. args adalah flag command line yang
+// sama dengan saat start agar prioritas flag tetap berlaku ketika reload.
+func NewLive(cfg *Config, args []string) *Live {
+	l := &Live{args: args}
+	l.current.Store(cfg)
+	return l
+}
+
+// Load mengembalikan snapshot config terbaru
+func (l *Live) Load() *Config {
+	return l.current.Load()
+}
+
+// OnChange mendaftarkan fungsi yang dipanggil setelah snapshot baru dipasang,
+// untuk komponen yang tidak membaca snapshot per request (misalnya level log)
+func (l *Live) OnChange(fn func(old, new *Config)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.listeners = append(l.listeners, fn)
+}
+
+// Reload memuat ulang config dari file, env dan flag. Jika config baru tidak
+// valid, snapshot lama tetap dipakai dan error dikembalikan.
+func (l *Live) Reload() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	loaded, err := Load(l.args)
+	if err != nil {
+		return err
+	}
+
+	old := l.current.Load()
+	next := *old
+	var changed, restartRequired []string
+
+	oldValue := reflect.ValueOf(old).Elem()
+	loadedValue := reflect.ValueOf(loaded).Elem()
+	nextValue := reflect.ValueOf(&next).Elem()
+	for i := 0; i < oldValue.NumField(); i++ {
+		field := oldValue.Type().Field(i)
+		env := field.Tag.Get("env")
+		if env == "" || reflect.DeepEqual(oldValue.Field(i).Interface(), loadedValue.Field(i).Interface()) {
+			continue
+		}
+		if field.Tag.Get("reload") != "true" {
+			restartRequired = append(restartRequired, env)
+			continue
+		}
+		nextValue.Field(i).Set(loadedValue.Field(i))
+		changed = append(changed, fmt.Sprintf("%s: %v -> %v", env, oldValue.Field(i).Interface(), loadedValue.Field(i).Interface()))
+	}
+
+	if len(restartRequired) > 0 {
+		slog.Warn("config changes ignored until restart", "keys", restartRequired)
+	}
+	if len(changed) == 0 {
+		slog.Info("config reloaded, no changes")
+		return nil
+	}
+
+	l.current.Store(&next)
+	slog.Info("config reloaded", "changes", changed)
+	for _, fn := range l.listeners {
+		fn(old, &next)
+	}
+	return nil
+}
+
+// Watch memuat ulang config saat menerima sinyal dari hangup atau ketika
+// file config berubah, sampai ctx berakhir. Caller mendaftarkan hangup ke
+// SIGHUP (signal.Notify) sedini mungkin; sinyal yang tertampung sebelum
+// Watch berjalan tetap memicu reload.
+func (l *Live) Watch(ctx context.Context, hangup <-chan os.Signal) {
+	ticker := time.NewTicker(fileWatchInterval)
+	defer ticker.Stop()
+	lastMod := l.fileModTime()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			slog.Info("SIGHUP received, reloading config")
+		case <-ticker.C:
+			modTime := l.fileModTime()
+			if modTime.Equal(lastMod) {
+				continue
+			}
+			lastMod = modTime
+			slog.Info("config file changed, reloading config", "file", l.Load().File)
+		}
+
+		if err := l.Reload(); err != nil {
+			slog.Error("config reload failed, keeping current config", "error", err)
+		}
+	}
+}
+
+// fileModTime waktu modifikasi file config, zero jika tidak memakai file.
+// os.Stat mengikuti symlink, jadi update ConfigMap Kubernetes juga terdeteksi.
+func (l *Live) fileModTime() time.Time {
+	path := l.Load().File
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLive(t *testing.T, content string) (*Live, string) {
+	t.Helper()
+	path := writeConfigFile(t, content)
+	args := []string{"--config", path}
+	cfg, err := Load(args)
+	require.NoError(t, err)
+	return NewLive(cfg, args), path
+}
+
+func TestReloadAppliesOnlyReloadableFields(t *testing.T) {
+	live, path := newTestLive(t, "server_port: 8081\nwip_limit: 1\n")
+	initial := live.Load()
+
+	var calls [][2]*Config
+	live.OnChange(func(old, next *Config) { calls = append(calls, [2]*Config{old, next}) })
+
+	require.NoError(t, os.WriteFile(path, []byte("server_port: 9000\nwip_limit: 2\nrate_limit:\n  requests: 5\n"), 0o600))
+	require.NoError(t, live.Reload())
+
+	current := live.Load()
+	assert.Equal(t, 2, current.WIPLimit, "reloadable")
+	assert.Equal(t, 5, current.RateLimitRequests, "reloadable")
+	assert.Equal(t, 8081, current.ServerPort, "restart-only field keeps the running value")
+	require.Len(t, calls, 1)
+	assert.Same(t, initial, calls[0][0])
+	assert.Same(t, current, calls[0][1])
+
+	// Snapshot lama tidak ikut berubah
+	assert.Equal(t, 1, initial.WIPLimit)
+
+	// Hanya field restart-only yang berubah: snapshot dan listener tidak tersentuh
+	require.NoError(t, os.WriteFile(path, []byte("server_port: 9001\nwip_limit: 2\nrate_limit:\n  requests: 5\n"), 0o600))
+	require.NoError(t, live.Reload())
+	assert.Same(t, current, live.Load())
+	assert.Len(t, calls, 1)
+}
+
+func TestReloadKeepsConfigWhenInvalid(t *testing.T) {
+	live, path := newTestLive(t, "wip_limit: 1\n")
+	initial := live.Load()
+
+	require.NoError(t, os.WriteFile(path, []byte("wip_limit: -1\n"), 0o600))
+	err := live.Reload()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WIP_LIMIT must not be negative")
+	assert.Same(t, initial, live.Load())
+}
+
+// Sinyal yang diterima sebelum Watch berjalan tetap memicu reload
+func TestWatchReloadsOnBufferedHangup(t *testing.T) {
+	live, path := newTestLive(t, "wip_limit: 1\n")
+	require.NoError(t, os.WriteFile(path, []byte("wip_limit: 3\n"), 0o600))
+
+	hangup := make(chan os.Signal, 1)
+	hangup <- syscall.SIGHUP
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		live.Watch(ctx, hangup)
+	}()
+	assert.Eventually(t, func() bool { return live.Load().WIPLimit == 3 }, 2*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+// minReleaseJWTSecretLength panjang minimal JWT_SECRET di GIN_MODE=release
+const minReleaseJWTSecretLength = 32
+
+// Validate memeriksa semua nilai dan mengembalikan seluruh pelanggaran sekaligus
+func (c *Config) Validate() error {
+	if errs := c.validate(); len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// normalize menyeragamkan nilai enum ke huruf kecil supaya GIN_MODE=Release
+// diperlakukan sama dengan release, termasuk pada pengecekan khusus release
+func (c *Config) normalize() {
+	for _, value := range []*string{
+		&c.DBDriver, &c.DBSSLMode, &c.GinMode, &c.LogLevel, &c.LogFormat, &c.DBLogLevel, &c.TracingExporter,
+	} {
+		*value = strings.ToLower(strings.TrimSpace(*value))
+	}
+}
+
+func (c *Config) validate() []error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(name, value string, allowed ...string) {
+		check(slices.Contains(allowed, value), "%s must be one of %s, got %q", name, strings.Join(allowed, ", "), value)
+	}
+	positive := func(name string, d time.Duration) {
+		check(d > 0, "%s must be greater than 0, got %s", name, d)
+	}
+	port := func(name string, p int) {
+		check(p >= 1 && p <= 65535, "%s must be between 1 and 65535, got %d", name, p)
+	}
+
+	check(c.DBDriver == DriverPostgres || c.DBDriver == DriverSQLite, "DB_DRIVER must be one of %s, %s, got %q", DriverPostgres, DriverSQLite, c.DBDriver)
+	if c.DBDriver == DriverPostgres {
+		check(c.DBHost != "", "DB_HOST is required")
+		port("DB_PORT", c.DBPort)
+		check(c.DBUser != "", "DB_USER is required")
+	}
+	check(c.DBName != "", "DB_NAME is required")
+	oneOf("DB_SSLMODE", c.DBSSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	check((c.DBSSLCert == "") == (c.DBSSLKey == ""), "DB_SSLCERT and DB_SSLKEY must be set together")
+	check(c.DBMaxOpenConns >= 0, "DB_MAX_OPEN_CONNS must not be negative, got %d", c.DBMaxOpenConns)
+	check(c.DBMaxIdleConns >= 0, "DB_MAX_IDLE_CONNS must not be negative, got %d", c.DBMaxIdleConns)
+	check(c.DBMaxOpenConns == 0 || c.DBMaxIdleConns <= c.DBMaxOpenConns,
+		"DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", c.DBMaxIdleConns, c.DBMaxOpenConns)
+	check(c.DBConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME must not be negative, got %s", c.DBConnMaxLifetime)
+	check(c.DBConnMaxIdleTime >= 0, "DB_CONN_MAX_IDLE_TIME must not be negative, got %s", c.DBConnMaxIdleTime)
+	check(c.DBConnectTimeout >= 0, "DB_CONNECT_TIMEOUT must not be negative, got %s", c.DBConnectTimeout)
+	if len(c.DBReplicaHosts) > 0 {
+		check(c.DBDriver == DriverPostgres, "DB_REPLICA_HOSTS is only supported with DB_DRIVER=%s", DriverPostgres)
+		for _, entry := range c.DBReplicaHosts {
+			_, _, err := replicaAddress(entry, c.DBPort)
+			check(err == nil, "DB_REPLICA_HOSTS entry %q is invalid: %v", entry, err)
+		}
+		positive("DB_REPLICA_CHECK_INTERVAL", c.DBReplicaCheckInterval)
+		check(c.DBReplicaMaxLag >= 0, "DB_REPLICA_MAX_LAG must not be negative, got %s", c.DBReplicaMaxLag)
+	}
+	port("SERVER_PORT", c.ServerPort)
+	port("GRPC_PORT", c.GRPCPort)
+	check(c.ServerPort != c.GRPCPort, "SERVER_PORT and GRPC_PORT must differ, both are %d", c.ServerPort)
+	oneOf("GIN_MODE", c.GinMode, "debug", "release", "test")
+
+	check(c.JWTSecret != "", "JWT_SECRET is required")
+	if c.GinMode == "release" {
+		check(c.JWTSecret != DefaultJWTSecret, "JWT_SECRET must be changed from the default value in release mode")
+		check(len(c.JWTSecret) >= minReleaseJWTSecretLength, "JWT_SECRET must be at least %d characters in release mode", minReleaseJWTSecretLength)
+	}
+
+	check(len(c.CORSAllowedOrigins) > 0, "CORS_ALLOWED_ORIGINS must not be empty, use * to allow every origin")
+	for _, origin := range c.CORSAllowedOrigins {
+		check(origin == "*" || strings.HasPrefix(origin, "http://") || strings.HasPrefix(origin, "https://"),
+			"CORS_ALLOWED_ORIGINS entry %q must be * or start with http:// or https://", origin)
+	}
+	for _, proxy := range c.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(net.ParseIP(proxy) != nil || cidrErr == nil, "TRUSTED_PROXIES entry %q must be an IP address or CIDR", proxy)
+	}
+
+	positive("SERVER_READ_TIMEOUT", c.ServerReadTimeout)
+	positive("SERVER_READ_HEADER_TIMEOUT", c.ServerReadHeaderTimeout)
+	positive("SERVER_WRITE_TIMEOUT", c.ServerWriteTimeout)
+	positive("SERVER_IDLE_TIMEOUT", c.ServerIdleTimeout)
+	check(c.ShutdownDelay >= 0, "SHUTDOWN_DELAY must not be negative, got %s", c.ShutdownDelay)
+	positive("SHUTDOWN_TIMEOUT", c.ShutdownTimeout)
+
+	oneOf("LOG_LEVEL", c.LogLevel, "debug", "info", "warn", "error")
+	oneOf("LOG_FORMAT", c.LogFormat, "json", "text")
+	oneOf("DB_LOG_LEVEL", c.DBLogLevel, "silent", "error", "warn", "info")
+	positive("DB_SLOW_QUERY_THRESHOLD", c.DBSlowQueryThreshold)
+
+	oneOf("OTEL_TRACES_EXPORTER", c.TracingExporter, "none", "stdout", "otlp")
+	check(c.TracingServiceName != "", "OTEL_SERVICE_NAME is required")
+	check(c.TracingSampleRatio >= 0 && c.TracingSampleRatio <= 1, "OTEL_TRACES_SAMPLER_ARG must be between 0 and 1, got %g", c.TracingSampleRatio)
+
+	_, tzErr := time.LoadLocation(c.Timezone)
+	check(c.Timezone != "" && tzErr == nil, "APP_TIMEZONE must be an IANA time zone such as Asia/Jakarta, got %q", c.Timezone)
+	check(c.WIPLimit >= 0, "WIP_LIMIT must not be negative, got %d", c.WIPLimit)
+	check(c.EventLogSize > 0, "EVENT_LOG_SIZE must be greater than 0, got %d", c.EventLogSize)
+	positive("IDEMPOTENCY_TTL", c.IdempotencyTTL)
+	positive("HEALTH_CHECK_TIMEOUT", c.HealthCheckTimeout)
+
+	check(c.RateLimitRequests >= 0, "RATE_LIMIT_REQUESTS must not be negative, got %d", c.RateLimitRequests)
+	positive("RATE_LIMIT_PERIOD", c.RateLimitPeriod)
+	check(c.AuthRateLimitRequests >= 0, "AUTH_RATE_LIMIT_REQUESTS must not be negative, got %d", c.AuthRateLimitRequests)
+	positive("AUTH_RATE_LIMIT_PERIOD", c.AuthRateLimitPeriod)
+	check(c.IPRateLimitRequests >= 0, "IP_RATE_LIMIT_REQUESTS must not be negative, got %d", c.IPRateLimitRequests)
+	positive("IP_RATE_LIMIT_PERIOD", c.IPRateLimitPeriod)
+
+	return errs
+}
@@ -0,0 +1,241 @@
+// Package database memisahkan koneksi tulis (primary) dari koneksi baca (read
+// replica). Query read-only dibagi round-robin ke replica yang sehat; jika
+// semua replica tidak sehat, query kembali ke primary.
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/metrics"
+	"gorm.io/gorm"
+)
+
+// replicaCheckTimeout batas waktu satu health check replica
+const replicaCheckTimeout = 5 * time.Second
+
+// postgresLagQuery apakah server standby dan lama replica tertinggal dari
+// primary dalam detik. Replica yang sudah me-replay semua WAL yang diterima
+// dianggap tidak tertinggal, karena pg_last_xact_replay_timestamp ikut menua
+// saat primary tidak menerima tulisan. Di server yang bukan standby fungsi WAL
+// mengembalikan NULL sehingga lag selalu 0; pg_is_in_recovery membedakannya.
+const postgresLagQuery = `SELECT pg_is_in_recovery(), CASE
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+// ErrNotStandby replica yang dicek ternyata bukan standby, misalnya host
+// primary yang salah masuk DB_REPLICA_HOSTS atau replica yang sudah di-promote
+var ErrNotStandby = errors.New("replica is not a standby, pg_is_in_recovery() is false")
+
+// Replica satu read replica; Name dipakai di log, metric dan health check
+type Replica struct {
+	Name string
+	DB   *gorm.DB
+}
+
+// Options pengaturan health check replica
+type Options struct {
+	// CheckInterval jarak antar health check di Watch
+	CheckInterval time.Duration
+	// MaxLag replica yang tertinggal lebih dari ini, atau yang bukan standby,
+	// dianggap tidak sehat. 0 berarti keduanya tidak diperiksa (hanya ping).
+	// Hanya untuk Postgres.
+	MaxLag time.Duration
+}
+
+// ReplicaStatus hasil health check terakhir satu replica
+type ReplicaStatus struct {
+	Name      string
+	Healthy   bool
+	Lag       time.Duration
+	Error     string
+	CheckedAt time.Time
+}
+
+// Cluster primary dan read replica-nya
+type Cluster struct {
+	primary  *gorm.DB
+	replicas []*replica
+	options  Options
+	next     atomic.Uint64
+}
+
+type replica struct {
+	Replica
+
+	mu     sync.RWMutex
+	status ReplicaStatus
+}
+
+// NewCluster membuat Cluster. Tanpa replica, semua query memakai primary.
+// Replica baru dipakai setelah lolos health check pertama (Check atau Watch).
+func NewCluster(primary *gorm.DB, options Options, replicas ...Replica) *Cluster {
+	c := &Cluster{primary: primary, options: options}
+	for _, r := range replicas {
+		c.replicas = append(c.replicas, &replica{Replica: r, status: ReplicaStatus{Name: r.Name}})
+		metrics.DBReplicaHealthy.WithLabelValues(r.Name).Set(0)
+	}
+	return c
+}
+
+type primaryKey struct{}
+
+// WithPrimary menandai ctx agar semua query di dalamnya memakai primary.
+// Dipakai di alur tulis yang membaca data sebelum atau sesudah menulis, karena
+// replica bisa tertinggal beberapa saat dari primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// Primary koneksi untuk query tulis
+func (c *Cluster) Primary() *gorm.DB {
+	return c.primary
+}
+
+// Reader koneksi untuk query read-only: replica sehat berikutnya secara
+// round-robin, atau primary jika ctx ditandai WithPrimary atau tidak ada
+// replica yang sehat
+func (c *Cluster) Reader(ctx context.Context) *gorm.DB {
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary || len(c.replicas) == 0 {
+		metrics.DBReads.WithLabelValues(metrics.DBTargetPrimary).Inc()
+		return c.primary
+	}
+
+	start := c.next.Add(1)
+	for i := range c.replicas {
+		r := c.replicas[(start+uint64(i))%uint64(len(c.replicas))]
+		if r.healthy() {
+			metrics.DBReads.WithLabelValues(metrics.DBTargetReplica).Inc()
+			return r.DB
+		}
+	}
+	metrics.DBReads.WithLabelValues(metrics.DBTargetPrimary).Inc()
+	return c.primary
+}
+
+// Replicas status terakhir setiap replica, urut sesuai konfigurasi
+func (c *Cluster) Replicas() []ReplicaStatus {
+	statuses := make([]ReplicaStatus, len(c.replicas))
+	for i, r := range c.replicas {
+		r.mu.RLock()
+		statuses[i] = r.status
+		r.mu.RUnlock()
+	}
+	return statuses
+}
+
+// Check menjalankan health check semua replica secara paralel dan mencatat
+// perubahan status di log
+func (c *Cluster) Check(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, r := range c.replicas {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+			c.check(ctx, r)
+		}(r)
+	}
+	wg.Wait()
+}
+
+// Watch menjalankan Check setiap CheckInterval sampai ctx selesai
+func (c *Cluster) Watch(ctx context.Context) {
+	if len(c.replicas) == 0 {
+		return
+	}
+	ticker := time.NewTicker(c.options.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check(ctx)
+		}
+	}
+}
+
+// Close menutup connection pool semua replica; primary ditutup pemiliknya
+func (c *Cluster) Close() error {
+	var firstErr error
+	for _, r := range c.replicas {
+		sqlDB, err := r.DB.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (c *Cluster) check(ctx context.Context, r *replica) {
+	ctx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+	defer cancel()
+
+	status := ReplicaStatus{Name: r.Name, CheckedAt: time.Now()}
+	lag, err := c.probe(ctx, r.DB)
+	status.Lag = lag
+	switch {
+	case err != nil:
+		status.Error = err.Error()
+	case c.options.MaxLag > 0 && lag > c.options.MaxLag:
+		status.Error = "replication lag " + lag.String() + " exceeds " + c.options.MaxLag.String()
+	default:
+		status.Healthy = true
+	}
+
+	r.mu.Lock()
+	previous := r.status
+	r.status = status
+	r.mu.Unlock()
+
+	if status.Healthy {
+		metrics.DBReplicaHealthy.WithLabelValues(r.Name).Set(1)
+	} else {
+		metrics.DBReplicaHealthy.WithLabelValues(r.Name).Set(0)
+	}
+
+	switch {
+	case status.Healthy && !previous.Healthy:
+		slog.Info("read replica healthy", "replica", r.Name, "lag", lag.String())
+	case !status.Healthy && (previous.Healthy || previous.CheckedAt.IsZero()):
+		slog.Warn("read replica unhealthy, reads fall back to other replicas or primary", "replica", r.Name, "error", status.Error)
+	}
+}
+
+// probe mem-ping replica dan, jika MaxLag diatur, mengukur replication lag
+func (c *Cluster) probe(ctx context.Context, db *gorm.DB) (time.Duration, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return 0, err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return 0, err
+	}
+	if c.options.MaxLag <= 0 {
+		return 0, nil
+	}
+
+	var inRecovery bool
+	var seconds float64
+	if err := sqlDB.QueryRowContext(ctx, postgresLagQuery).Scan(&inRecovery, &seconds); err != nil {
+		return 0, err
+	}
+	if !inRecovery {
+		return 0, ErrNotStandby
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func (r *replica) healthy() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status.Healthy
+}
@@ -0,0 +1,53 @@
+package dto
+
+import "time"
+
+// ============================================
+// BOARD REQUEST DTOs
+// ============================================
+
+// MoveTodoRequest untuk memindahkan todo ke kolom/posisi lain di board
+type MoveTodoRequest struct {
+	Status   string `json:"status" binding:"required,oneof=pending in_progress completed"`
+	Position *int   `json:"position" binding:"omitempty,min=0"` // Kosong = paling bawah kolom
+}
+
+// UpdateBoardSettingsRequest untuk mengubah pengaturan board
+type UpdateBoardSettingsRequest struct {
+	WIPLimit *int `json:"wip_limit" binding:"required,min=0"` // 0 berarti tanpa batas
+}
+
+// ShareBoardRequest untuk menambahkan collaborator ke board
+type ShareBoardRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// ============================================
+// BOARD RESPONSE DTOs
+// ============================================
+
+// BoardColumnResponse untuk satu kolom status di board
+type BoardColumnResponse struct {
+	Status   string         `json:"status"`
+	WIPLimit int            `json:"wip_limit,omitempty"`
+	Count    int            `json:"count"`
+	Todos    []TodoResponse `json:"todos"`
+}
+
+// BoardResponse untuk response kanban board
+type BoardResponse struct {
+	Columns []BoardColumnResponse `json:"columns"`
+}
+
+// BoardSettingsResponse untuk response pengaturan board
+type BoardSettingsResponse struct {
+	WIPLimit int `json:"wip_limit"`
+}
+
+// BoardShareResponse untuk satu collaborator board
+type BoardShareResponse struct {
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	FullName  string    `json:"full_name"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// GraphQLRequest adalah body standar request GraphQL over HTTP
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+// ============================================
+// PROBLEM DETAILS (RFC 7807) DTOs
+// ============================================
+
+// ProblemDetails untuk response error API v2 (Content-Type: application/problem+json)
+type ProblemDetails struct {
+	Type     string       `json:"type"`             // URI referensi jenis error, unik per code
+	Title    string       `json:"title"`            // Ringkasan singkat yang tetap untuk setiap code
+	Status   int          `json:"status"`           // HTTP status code
+	Detail   string       `json:"detail,omitempty"` // Penjelasan spesifik untuk request ini
+	Instance string       `json:"instance"`         // Path request yang gagal
+	Code     string       `json:"code"`             // Code stabil untuk dicocokkan client, contoh: todo_not_found
+	Errors   []FieldError `json:"errors,omitempty"` // Detail per field jika code = validation_failed
+}
+
+// FieldError detail validasi untuk satu field request
+type FieldError struct {
+	Field   string `json:"field"`   // Nama field JSON, contoh: title atau mutations[0].op
+	Code    string `json:"code"`    // Aturan yang dilanggar, contoh: required, max, oneof
+	Message string `json:"message"` // Penjelasan dalam bahasa Inggris
+}
@@ -0,0 +1,64 @@
+package dto
+
+import "time"
+
+// ============================================
+// SYNC REQUEST DTOs
+// ============================================
+
+// SyncPullParams untuk query GET /sync
+type SyncPullParams struct {
+	Since string `form:"since"`                                    // Token dari response sebelumnya, kosong = sync penuh
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=1000"` // Default 500
+}
+
+// SyncMutation satu perubahan yang dibuat client saat offline
+type SyncMutation struct {
+	Op              string            `json:"op" binding:"required,oneof=create update delete"`
+	ClientRef       string            `json:"client_ref"`        // ID sementara di client, dikembalikan apa adanya
+	ID              uint              `json:"id"`                // Wajib untuk update dan delete
+	BaseVersion     *uint             `json:"base_version"`      // Versi todo yang terakhir dilihat client
+	ClientUpdatedAt *time.Time        `json:"client_updated_at"` // Waktu perubahan di client (strategi lww)
+	Data            UpdateTodoRequest `json:"data"`              // Field todo untuk create/update
+}
+
+// SyncPushRequest untuk POST /sync
+type SyncPushRequest struct {
+	// Strategy: "version" (default) menolak perubahan jika base_version sudah
+	// ketinggalan, "lww" menerapkan perubahan yang client_updated_at-nya lebih baru
+	Strategy  string         `json:"strategy" binding:"omitempty,oneof=version lww"`
+	Mutations []SyncMutation `json:"mutations" binding:"required,min=1,max=100,dive"`
+}
+
+// ============================================
+// SYNC RESPONSE DTOs
+// ============================================
+
+// SyncChange satu todo yang berubah; Deleted = true berarti tombstone
+type SyncChange struct {
+	ID        uint          `json:"id"`
+	Deleted   bool          `json:"deleted"`
+	DeletedAt *time.Time    `json:"deleted_at,omitempty"`
+	Todo      *TodoResponse `json:"todo,omitempty"`
+}
+
+// SyncPullResponse untuk response GET /sync
+type SyncPullResponse struct {
+	Changes   []SyncChange `json:"changes"`
+	NextToken string       `json:"next_token"`
+	HasMore   bool         `json:"has_more"`
+}
+
+// SyncMutationResult hasil penerapan satu mutation
+type SyncMutationResult struct {
+	Index     int           `json:"index"`
+	ClientRef string        `json:"client_ref,omitempty"`
+	Status    string        `json:"status"` // applied, conflict, rejected
+	Error     string        `json:"error,omitempty"`
+	Todo      *TodoResponse `json:"todo,omitempty"` // Kondisi todo di server setelah mutation / saat konflik
+}
+
+// SyncPushResponse untuk response POST /sync
+type SyncPushResponse struct {
+	Results []SyncMutationResult `json:"results"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// ============================================
+// TEMPLATE REQUEST DTOs
+// ============================================
+
+// TemplateItemRequest untuk satu item checklist di template
+type TemplateItemRequest struct {
+	Title         string `json:"title" binding:"required,max=200"`
+	Description   string `json:"description"`
+	Priority      string `json:"priority" binding:"omitempty,oneof=low medium high"`
+	DueOffsetDays *int   `json:"due_offset_days"` // Hari relatif terhadap start date
+}
+
+// CreateTemplateRequest untuk membuat template baru
+type CreateTemplateRequest struct {
+	Title         string                `json:"title" binding:"required,max=200"`
+	Description   string                `json:"description"`
+	Priority      string                `json:"priority" binding:"required,oneof=low medium high"`
+	DueOffsetDays *int                  `json:"due_offset_days"` // Hari relatif terhadap start date
+	Checklist     []TemplateItemRequest `json:"checklist" binding:"omitempty,dive"`
+}
+
+// UpdateTemplateRequest untuk update template
+type UpdateTemplateRequest struct {
+	Title         *string                `json:"title" binding:"omitempty,max=200"`
+	Description   *string                `json:"description"`
+	Priority      *string                `json:"priority" binding:"omitempty,oneof=low medium high"`
+	DueOffsetDays *int                   `json:"due_offset_days"`
+	Checklist     *[]TemplateItemRequest `json:"checklist" binding:"omitempty,dive"` // Jika diisi, checklist lama diganti
+}
+
+// InstantiateTemplateRequest untuk membuat todo dari template
+type InstantiateTemplateRequest struct {
+	StartDate string `json:"start_date"` // Format: YYYY-MM-DD, default hari ini
+}
+
+// ============================================
+// TEMPLATE RESPONSE DTOs
+// ============================================
+
+// TemplateItemResponse untuk response item checklist
+type TemplateItemResponse struct {
+	ID            uint   `json:"id"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Priority      string `json:"priority"`
+	DueOffsetDays *int   `json:"due_offset_days,omitempty"`
+	Position      int    `json:"position"`
+}
+
+// TemplateResponse untuk response template
+type TemplateResponse struct {
+	ID            uint                   `json:"id"`
+	Title         string                 `json:"title"`
+	Description   string                 `json:"description"`
+	Priority      string                 `json:"priority"`
+	DueOffsetDays *int                   `json:"due_offset_days,omitempty"`
+	Checklist     []TemplateItemResponse `json:"checklist"`
+	UserID        uint                   `json:"user_id"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
@@ -15,6 +15,7 @@ type CreateTodoRequest struct {
 	Status      string `json:"status" binding:"required,oneof=pending in_progress completed"`
 	Priority    string `json:"priority" binding:"required,oneof=low medium high"`
 	DueDate     string `json:"due_date" binding:"omitempty"` // Format: YYYY-MM-DD
+	BlockedBy   []uint `json:"blocked_by"`                   // ID todo yang harus selesai lebih dulu
 }
 
 // UpdateTodoRequest untuk update todo
@@ -23,7 +24,8 @@ type UpdateTodoRequest struct {
 	Description *string `json:"description"`
 	Status      *string `json:"status" binding:"omitempty,oneof=pending in_progress completed"`
 	Priority    *string `json:"priority" binding:"omitempty,oneof=low medium high"`
-	DueDate     *string `json:"due_date"` // Format: YYYY-MM-DD or empty string to clear
+	DueDate     *string `json:"due_date"`   // Format: YYYY-MM-DD or empty string to clear
+	BlockedBy   *[]uint `json:"blocked_by"` // Menggantikan daftar dependency, [] untuk menghapus semua
 }
 
 // TodoCreateRequest untuk backward compatibility (alias)
@@ -46,15 +48,20 @@ type TodoQueryParams struct {
 
 // TodoResponse untuk response todo
 type TodoResponse struct {
-	ID          uint       `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      string     `json:"status"`
-	Priority    string     `json:"priority"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	UserID      uint       `json:"user_id"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID              uint          `json:"id"`
+	Title           string        `json:"title"`
+	Description     string        `json:"description"`
+	DescriptionHTML string        `json:"description_html,omitempty"` // Opt-in via ?render=html
+	Status          string        `json:"status"`
+	Priority        string        `json:"priority"`
+	DueDate         *time.Time    `json:"due_date,omitempty"`
+	UserID          uint          `json:"user_id"`
+	BlockedBy       []uint        `json:"blocked_by"` // Todo yang memblokir todo ini
+	Blocking        []uint        `json:"blocking"`   // Todo yang diblokir oleh todo ini
+	Version         uint          `json:"version"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	User            *UserResponse `json:"user,omitempty"` // Opt-in via ?expand=user
 }
 
 // TodoListResponse untuk response list todos dengan pagination
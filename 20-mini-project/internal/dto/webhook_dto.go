@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// ============================================
+// WEBHOOK REQUEST DTOs
+// ============================================
+
+// CreateWebhookRequest untuk mendaftarkan webhook baru
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=500"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=todo.created todo.updated todo.deleted"`
+}
+
+// UpdateWebhookRequest untuk update webhook
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url" binding:"omitempty,url,max=500"`
+	Events *[]string `json:"events" binding:"omitempty,min=1,dive,oneof=todo.created todo.updated todo.deleted"`
+	Active *bool     `json:"active"`
+}
+
+// ============================================
+// WEBHOOK RESPONSE DTOs
+// ============================================
+
+// WebhookResponse untuk response webhook
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"` // Hanya dikirim sekali saat webhook dibuat
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse untuk response log pengiriman webhook
+type WebhookDeliveryResponse struct {
+	ID             uint       `json:"id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,79 @@
+package event
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Tipe event todo yang dipublikasikan oleh TodoService
+const (
+	TodoCreated = "todo.created"
+	TodoUpdated = "todo.updated"
+	TodoDeleted = "todo.deleted"
+)
+
+// Event merepresentasikan perubahan data yang terjadi di aplikasi
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	UserID    uint        `json:"-"` // Pemilik data, dipakai subscriber untuk routing
+	TodoID    uint        `json:"-"` // Todo yang berubah, dipakai untuk routing per todo
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// New membuat event baru dengan ID acak
+func New(eventType string, userID, todoID uint, data interface{}) Event {
+	return Event{
+		ID:        newID(),
+		Type:      eventType,
+		UserID:    userID,
+		TodoID:    todoID,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Publisher dipakai service untuk mengirim event
+type Publisher interface {
+	Publish(e Event)
+}
+
+// Handler menerima event dari Bus. Handler harus cepat dan tidak
+// boleh blocking karena dipanggil langsung di jalur request.
+type Handler func(e Event)
+
+// Bus meneruskan setiap event ke semua subscriber (in-process)
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewBus membuat event bus baru
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe mendaftarkan handler yang akan menerima semua event
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish mengirim event ke semua subscriber
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(e)
+	}
+}
+
+func newID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,188 @@
+package event
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// subscriberBuffer kapasitas channel per subscriber. Subscriber yang
+// tertinggal lebih dari ini akan diputus supaya Append tidak pernah blocking.
+const subscriberBuffer = 64
+
+// Entry adalah event yang sudah diberi nomor urut oleh Log
+type Entry struct {
+	Seq   uint64
+	Event Event
+}
+
+// Log menyimpan event terakhir dalam ring buffer berukuran tetap dan
+// meneruskannya ke subscriber board pemilik event (Event.UserID). Nomor urut (Seq) hanya berlaku di
+// proses ini, jadi ID yang dikirim ke client (EventID) diawali epoch acak
+// per log. Last-Event-ID dari sebelum restart atau dari instance lain
+// dikenali lewat epoch yang berbeda.
+type Log struct {
+	epoch       string
+	mu          sync.Mutex
+	entries     []Entry // ring buffer
+	start       int     // index entry tertua
+	size        int
+	nextSeq     uint64
+	subscribers map[uint]map[chan Entry]struct{} // per pemilik board
+	owners      map[chan Entry][]uint            // board yang diikuti setiap subscriber
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewLog membuat event log dengan kapasitas capacity
+func NewLog(capacity int) *Log {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &Log{
+		epoch:       newEpoch(),
+		entries:     make([]Entry, capacity),
+		nextSeq:     1,
+		subscribers: make(map[uint]map[chan Entry]struct{}),
+		owners:      make(map[chan Entry][]uint),
+		done:        make(chan struct{}),
+	}
+}
+
+// EventID mengembalikan ID event untuk client dengan format "<epoch>-<seq>"
+func (l *Log) EventID(seq uint64) string {
+	return l.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// ParseEventID kebalikan EventID. ok bernilai false jika id bukan berasal
+// dari log ini: epoch berbeda, format lama tanpa epoch, atau rusak.
+func (l *Log) ParseEventID(id string) (seq uint64, ok bool) {
+	epoch, rawSeq, found := strings.Cut(id, "-")
+	if !found || epoch != l.epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+// Close menandai log berhenti saat server shutdown. Stream yang sedang
+// berjalan (SSE, gRPC) menunggu Done lalu mengakhiri koneksi dengan rapi.
+func (l *Log) Close() {
+	l.closeOnce.Do(func() { close(l.done) })
+}
+
+// Done ditutup ketika Close dipanggil
+func (l *Log) Done() <-chan struct{} {
+	return l.done
+}
+
+// Append menyimpan event dan mengirimkannya ke subscriber milik user
+// event tersebut. Bisa langsung didaftarkan sebagai Bus handler.
+func (l *Log) Append(e Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := Entry{Seq: l.nextSeq, Event: e}
+	l.nextSeq++
+
+	if l.size < len(l.entries) {
+		l.entries[(l.start+l.size)%len(l.entries)] = entry
+		l.size++
+	} else {
+		l.entries[l.start] = entry
+		l.start = (l.start + 1) % len(l.entries)
+	}
+
+	for ch := range l.subscribers[e.UserID] {
+		select {
+		case ch <- entry:
+		default:
+			// Subscriber terlalu lambat: putus, client akan reconnect
+			// dengan Last-Event-ID dan mengambil sisa event dari log
+			l.removeLocked(ch)
+			close(ch)
+		}
+	}
+}
+
+// Since mengembalikan event dari board ownerIDs dengan Seq > lastSeq. complete
+// bernilai false jika sebagian event sudah terbuang dari ring buffer atau
+// lastSeq belum pernah diberikan log ini, sehingga client perlu memuat ulang
+// data secara penuh.
+func (l *Log) Since(ownerIDs []uint, lastSeq uint64) (entries []Entry, complete bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	complete = lastSeq < l.nextSeq
+	if complete && l.size > 0 {
+		oldest := l.entries[l.start].Seq
+		complete = lastSeq+1 >= oldest
+	}
+
+	for i := 0; i < l.size; i++ {
+		entry := l.entries[(l.start+i)%len(l.entries)]
+		if entry.Seq > lastSeq && slices.Contains(ownerIDs, entry.Event.UserID) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, complete
+}
+
+// LastSeq mengembalikan nomor urut event terakhir
+func (l *Log) LastSeq() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.nextSeq - 1
+}
+
+// Subscribe mendaftarkan subscriber untuk event dari board ownerIDs. Channel
+// ditutup jika subscriber terlalu lambat; panggil cancel saat selesai.
+func (l *Log) Subscribe(ownerIDs []uint) (<-chan Entry, func()) {
+	ch := make(chan Entry, subscriberBuffer)
+	ownerIDs = slices.Clone(ownerIDs)
+	slices.Sort(ownerIDs)
+	ownerIDs = slices.Compact(ownerIDs)
+
+	l.mu.Lock()
+	for _, ownerID := range ownerIDs {
+		if l.subscribers[ownerID] == nil {
+			l.subscribers[ownerID] = make(map[chan Entry]struct{})
+		}
+		l.subscribers[ownerID][ch] = struct{}{}
+	}
+	l.owners[ch] = ownerIDs
+	l.mu.Unlock()
+
+	cancel := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := l.owners[ch]; ok {
+			l.removeLocked(ch)
+			close(ch)
+		}
+	}
+	return ch, cancel
+}
+
+func (l *Log) removeLocked(ch chan Entry) {
+	for _, ownerID := range l.owners[ch] {
+		delete(l.subscribers[ownerID], ch)
+		if len(l.subscribers[ownerID]) == 0 {
+			delete(l.subscribers, ownerID)
+		}
+	}
+	delete(l.owners, ch)
+}
+
+func newEpoch() string {
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,124 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seqs(entries []Entry) []uint64 {
+	result := make([]uint64, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Seq
+	}
+	return result
+}
+
+func TestLogKeepsOnlyLastEntries(t *testing.T) {
+	log := NewLog(3)
+	for i := 0; i < 5; i++ {
+		log.Append(New(TodoCreated, 1, uint(i), nil))
+	}
+	assert.Equal(t, uint64(5), log.LastSeq())
+
+	// Entry 1 dan 2 sudah tertimpa
+	entries, complete := log.Since([]uint{1}, 0)
+	assert.False(t, complete)
+	assert.Equal(t, []uint64{3, 4, 5}, seqs(entries))
+
+	entries, complete = log.Since([]uint{1}, 1)
+	assert.False(t, complete, "entry 2 is gone")
+	assert.Equal(t, []uint64{3, 4, 5}, seqs(entries))
+
+	entries, complete = log.Since([]uint{1}, 2)
+	assert.True(t, complete)
+	assert.Equal(t, []uint64{3, 4, 5}, seqs(entries))
+
+	entries, complete = log.Since([]uint{1}, 5)
+	assert.True(t, complete)
+	assert.Empty(t, entries)
+}
+
+func TestSinceFiltersByUser(t *testing.T) {
+	log := NewLog(10)
+	log.Append(New(TodoCreated, 1, 1, nil))
+	log.Append(New(TodoCreated, 2, 2, nil))
+	log.Append(New(TodoUpdated, 1, 1, nil))
+
+	entries, complete := log.Since([]uint{1}, 0)
+	assert.True(t, complete)
+	assert.Equal(t, []uint64{1, 3}, seqs(entries))
+
+	entries, complete = log.Since([]uint{2}, 2)
+	assert.True(t, complete)
+	assert.Empty(t, entries)
+}
+
+func TestSinceRejectsUnknownSeq(t *testing.T) {
+	log := NewLog(10)
+	_, complete := log.Since([]uint{1}, 0)
+	assert.True(t, complete, "empty log, nothing missed")
+
+	// Seq yang belum pernah diberikan, misalnya dari proses sebelum restart
+	_, complete = log.Since([]uint{1}, 7)
+	assert.False(t, complete)
+
+	log.Append(New(TodoCreated, 1, 1, nil))
+	_, complete = log.Since([]uint{1}, 2)
+	assert.False(t, complete)
+}
+
+func TestEventIDCarriesEpoch(t *testing.T) {
+	log := NewLog(10)
+	id := log.EventID(42)
+	seq, ok := log.ParseEventID(id)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), seq)
+
+	// ID dari proses lain, format lama tanpa epoch, atau rusak
+	other := NewLog(10)
+	require.NotEqual(t, log.EventID(42), other.EventID(42))
+	for _, id := range []string{other.EventID(42), "42", "", log.EventID(1) + "x"} {
+		_, ok := log.ParseEventID(id)
+		assert.False(t, ok, id)
+	}
+}
+
+func TestSlowSubscriberIsEvicted(t *testing.T) {
+	log := NewLog(1000)
+	slow, cancelSlow := log.Subscribe([]uint{1})
+	defer cancelSlow()
+	fast, cancelFast := log.Subscribe([]uint{1})
+	defer cancelFast()
+	other, cancelOther := log.Subscribe([]uint{2})
+	defer cancelOther()
+
+	received := 0
+	for i := 0; i < subscriberBuffer+1; i++ {
+		log.Append(New(TodoCreated, 1, uint(i), nil))
+		<-fast
+		received++
+	}
+	assert.Equal(t, subscriberBuffer+1, received)
+
+	// Buffer subscriber lambat penuh: event yang sudah masuk tetap bisa
+	// dibaca, lalu channel ditutup
+	for i := 0; i < subscriberBuffer; i++ {
+		entry, ok := <-slow
+		require.True(t, ok)
+		assert.Equal(t, uint64(i+1), entry.Seq)
+	}
+	_, ok := <-slow
+	assert.False(t, ok, "slow subscriber is closed")
+
+	// Subscriber lain tetap menerima event
+	log.Append(New(TodoUpdated, 1, 1, nil))
+	entry, ok := <-fast
+	require.True(t, ok)
+	assert.Equal(t, uint64(subscriberBuffer+2), entry.Seq)
+	assert.Empty(t, other, "events are routed by user")
+
+	// cancel setelah diputus tidak panic
+	cancelSlow()
+}
@@ -0,0 +1,69 @@
+package gql
+
+import (
+	"context"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// userBatchWait adalah jeda pengumpulan key sebelum satu query batch dijalankan
+const userBatchWait = 2 * time.Millisecond
+
+type contextKey int
+
+const (
+	viewerKey contextKey = iota
+	loadersKey
+)
+
+// loaders holds the per-request dataloaders
+type loaders struct {
+	users *dataloader.Loader[uint, *dto.UserResponse]
+}
+
+// newLoaders creates the dataloaders for a single request
+func newLoaders(authService *service.AuthService) *loaders {
+	batchUsers := func(ctx context.Context, ids []uint) []*dataloader.Result[*dto.UserResponse] {
+		results := make([]*dataloader.Result[*dto.UserResponse], len(ids))
+
+		users, err := authService.GetUsersByIDs(ctx, ids)
+		for i, id := range ids {
+			switch {
+			case err != nil:
+				results[i] = &dataloader.Result[*dto.UserResponse]{Error: err}
+			case users[id] == nil:
+				results[i] = &dataloader.Result[*dto.UserResponse]{Error: service.ErrUserNotFound}
+			default:
+				results[i] = &dataloader.Result[*dto.UserResponse]{Data: users[id]}
+			}
+		}
+		return results
+	}
+
+	return &loaders{
+		users: dataloader.NewBatchedLoader(batchUsers, dataloader.WithWait[uint, *dto.UserResponse](userBatchWait)),
+	}
+}
+
+func withViewer(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, viewerKey, userID)
+}
+
+func viewerID(ctx context.Context) (uint, error) {
+	userID, ok := ctx.Value(viewerKey).(uint)
+	if !ok || userID == 0 {
+		return 0, errUnauthenticated
+	}
+	return userID, nil
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
@@ -0,0 +1,52 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+)
+
+// Error is a GraphQL error with a machine readable code in its extensions
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions is picked up by graphql-go and rendered under "extensions"
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+var (
+	errUnauthenticated = &Error{Code: "UNAUTHENTICATED", Message: "authentication required"}
+	errInvalidID       = &Error{Code: "BAD_USER_INPUT", Message: "invalid id"}
+)
+
+// toGraphQLError maps service errors to coded GraphQL errors, the same way
+// the REST handlers map them to status codes
+func toGraphQLError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrTodoNotFound), errors.Is(err, service.ErrUserNotFound):
+		return &Error{Code: "NOT_FOUND", Message: err.Error()}
+	case errors.Is(err, service.ErrUnauthorizedAccess):
+		return &Error{Code: "FORBIDDEN", Message: err.Error()}
+	case errors.Is(err, service.ErrTitleRequired), errors.Is(err, service.ErrTitleTooLong),
+		errors.Is(err, service.ErrInvalidStatus), errors.Is(err, service.ErrInvalidPriority),
+		errors.Is(err, service.ErrInvalidDueDate), errors.Is(err, service.ErrInvalidDependency),
+		errors.Is(err, service.ErrDependencyCycle):
+		return &Error{Code: "BAD_USER_INPUT", Message: err.Error()}
+	case errors.Is(err, service.ErrTodoBlocked), errors.Is(err, service.ErrWIPLimitReached),
+		errors.Is(err, service.ErrTodoModified):
+		return &Error{Code: "CONFLICT", Message: err.Error()}
+	default:
+		// Jangan bocorkan detail error internal ke client
+		slog.ErrorContext(ctx, "graphql: request failed", "error", err)
+		return &Error{Code: "INTERNAL", Message: "internal server error"}
+	}
+}
@@ -0,0 +1,238 @@
+package gql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// Resolver is the root resolver for queries and mutations
+type Resolver struct {
+	todoService *service.TodoService
+	authService *service.AuthService
+}
+
+// ============================================
+// QUERIES
+// ============================================
+
+// Viewer resolves the authenticated user
+func (r *Resolver) Viewer(ctx context.Context) (*userResolver, error) {
+	userID, err := viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := loadersFrom(ctx).users.Load(ctx, userID)()
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return &userResolver{user: user}, nil
+}
+
+// Todo resolves a single todo owned by the viewer
+func (r *Resolver) Todo(ctx context.Context, args struct{ ID graphql.ID }) (*todoResolver, error) {
+	userID, err := viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	todoID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := r.todoService.GetTodoByID(ctx, todoID, userID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return newTodoResolver(service.ToTodoResponse(todo)), nil
+}
+
+type todosArgs struct {
+	Status   *string
+	Priority *string
+	Page     int32 // Default 1 dari schema
+	Limit    int32 // Default 20 dari schema
+}
+
+// Todos resolves one page of the viewer's todos
+func (r *Resolver) Todos(ctx context.Context, args todosArgs) (*todoConnectionResolver, error) {
+	userID, err := viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page, limit := 1, 20
+	if args.Page > 1 {
+		page = int(args.Page)
+	}
+	if args.Limit >= 1 && args.Limit <= 100 {
+		limit = int(args.Limit)
+	}
+
+	todos, total, err := r.todoService.GetUserTodosPage(ctx, userID, deref(args.Status), deref(args.Priority), page, limit)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+
+	nodes := make([]*todoResolver, len(todos))
+	for i := range todos {
+		nodes[i] = newTodoResolver(service.ToTodoResponse(&todos[i]))
+	}
+
+	return &todoConnectionResolver{
+		nodes:      nodes,
+		totalCount: total,
+		page:       page,
+		limit:      limit,
+	}, nil
+}
+
+// ============================================
+// MUTATIONS
+// ============================================
+
+type createTodoInput struct {
+	Title       string
+	Description *string
+	Status      string
+	Priority    string
+	DueDate     *string
+	BlockedBy   *[]graphql.ID
+}
+
+// CreateTodo creates a todo for the viewer
+func (r *Resolver) CreateTodo(ctx context.Context, args struct{ Input createTodoInput }) (*todoResolver, error) {
+	userID, err := viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := dto.CreateTodoRequest{
+		Title:       args.Input.Title,
+		Description: deref(args.Input.Description),
+		Status:      args.Input.Status,
+		Priority:    args.Input.Priority,
+		DueDate:     deref(args.Input.DueDate),
+	}
+	if args.Input.BlockedBy != nil {
+		if req.BlockedBy, err = parseIDs(*args.Input.BlockedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	todo, err := r.todoService.CreateTodo(ctx, userID, req)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return newTodoResolver(service.ToTodoResponse(todo)), nil
+}
+
+type updateTodoInput struct {
+	Title       *string
+	Description *string
+	Status      *string
+	Priority    *string
+	DueDate     *string
+	BlockedBy   *[]graphql.ID
+}
+
+// UpdateTodo updates a todo owned by the viewer
+func (r *Resolver) UpdateTodo(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateTodoInput
+}) (*todoResolver, error) {
+	userID, err := viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	todoID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := dto.UpdateTodoRequest{
+		Title:       args.Input.Title,
+		Description: args.Input.Description,
+		Status:      args.Input.Status,
+		Priority:    args.Input.Priority,
+		DueDate:     args.Input.DueDate,
+	}
+	if args.Input.BlockedBy != nil {
+		blockedBy, err := parseIDs(*args.Input.BlockedBy)
+		if err != nil {
+			return nil, err
+		}
+		req.BlockedBy = &blockedBy
+	}
+
+	todo, err := r.todoService.UpdateTodo(ctx, todoID, userID, req)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return newTodoResolver(service.ToTodoResponse(todo)), nil
+}
+
+// DeleteTodo deletes a todo owned by the viewer
+func (r *Resolver) DeleteTodo(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	userID, err := viewerID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	todoID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.todoService.DeleteTodo(ctx, todoID, userID); err != nil {
+		return false, toGraphQLError(ctx, err)
+	}
+	return true, nil
+}
+
+// Helper functions
+
+func parseID(id graphql.ID) (uint, error) {
+	value, err := strconv.ParseUint(string(id), 10, 32)
+	if err != nil || value == 0 {
+		return 0, errInvalidID
+	}
+	return uint(value), nil
+}
+
+func parseIDs(ids []graphql.ID) ([]uint, error) {
+	result := make([]uint, len(ids))
+	for i, id := range ids {
+		value, err := parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = value
+	}
+	return result, nil
+}
+
+func toID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func toIDs(ids []uint) []graphql.ID {
+	result := make([]graphql.ID, len(ids))
+	for i, id := range ids {
+		result[i] = toID(id)
+	}
+	return result
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,42 @@
+package gql
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// maxQueryDepth membatasi kedalaman query agar tidak bisa dipakai untuk membebani server
+const maxQueryDepth = 10
+
+// Schema is the executable GraphQL schema backed by the application services
+type Schema struct {
+	schema      *graphql.Schema
+	authService *service.AuthService
+}
+
+// NewSchema parses the schema and binds it to the resolvers
+func NewSchema(todoService *service.TodoService, authService *service.AuthService) *Schema {
+	resolver := &Resolver{
+		todoService: todoService,
+		authService: authService,
+	}
+
+	return &Schema{
+		schema:      graphql.MustParseSchema(schemaSDL, resolver, graphql.MaxDepth(maxQueryDepth)),
+		authService: authService,
+	}
+}
+
+// Exec runs a query on behalf of the given user. Every call gets its own
+// dataloaders so cached users never leak between requests.
+func (s *Schema) Exec(ctx context.Context, userID uint, query, operationName string, variables map[string]interface{}) *graphql.Response {
+	ctx = withViewer(ctx, userID)
+	ctx = withLoaders(ctx, newLoaders(s.authService))
+	return s.schema.Exec(ctx, query, operationName, variables)
+}
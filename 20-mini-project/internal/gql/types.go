@@ -0,0 +1,82 @@
+package gql
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// userResolver resolves the User type
+type userResolver struct {
+	user *dto.UserResponse
+}
+
+func (r *userResolver) ID() graphql.ID          { return toID(r.user.ID) }
+func (r *userResolver) Username() string        { return r.user.Username }
+func (r *userResolver) Email() string           { return r.user.Email }
+func (r *userResolver) FullName() string        { return r.user.FullName }
+func (r *userResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.user.CreatedAt} }
+func (r *userResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.user.UpdatedAt} }
+
+// todoResolver resolves the Todo type
+type todoResolver struct {
+	todo dto.TodoResponse
+}
+
+func newTodoResolver(todo dto.TodoResponse) *todoResolver {
+	return &todoResolver{todo: todo}
+}
+
+func (r *todoResolver) ID() graphql.ID          { return toID(r.todo.ID) }
+func (r *todoResolver) Title() string           { return r.todo.Title }
+func (r *todoResolver) Description() string     { return r.todo.Description }
+func (r *todoResolver) Status() string          { return r.todo.Status }
+func (r *todoResolver) Priority() string        { return r.todo.Priority }
+func (r *todoResolver) BlockedBy() []graphql.ID { return toIDs(r.todo.BlockedBy) }
+func (r *todoResolver) Blocking() []graphql.ID  { return toIDs(r.todo.Blocking) }
+func (r *todoResolver) Version() int32          { return int32(r.todo.Version) }
+func (r *todoResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.todo.CreatedAt} }
+func (r *todoResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.todo.UpdatedAt} }
+
+// DescriptionHTML renders the Markdown description only when the field is requested
+func (r *todoResolver) DescriptionHTML(ctx context.Context) string {
+	html, err := utils.RenderMarkdown(r.todo.Description)
+	if err != nil {
+		slog.ErrorContext(ctx, "graphql: failed to render description", "todo_id", r.todo.ID, "error", err)
+		return ""
+	}
+	return html
+}
+
+func (r *todoResolver) DueDate() *graphql.Time {
+	if r.todo.DueDate == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.todo.DueDate}
+}
+
+// User resolves the owner through the request dataloader so a list of
+// todos costs a single user query instead of one per todo
+func (r *todoResolver) User(ctx context.Context) (*userResolver, error) {
+	user, err := loadersFrom(ctx).users.Load(ctx, r.todo.UserID)()
+	if err != nil {
+		return nil, toGraphQLError(ctx, err)
+	}
+	return &userResolver{user: user}, nil
+}
+
+// todoConnectionResolver resolves the TodoConnection type
+type todoConnectionResolver struct {
+	nodes      []*todoResolver
+	totalCount int64
+	page       int
+	limit      int
+}
+
+func (r *todoConnectionResolver) Nodes() []*todoResolver { return r.nodes }
+func (r *todoConnectionResolver) TotalCount() int32      { return int32(r.totalCount) }
+func (r *todoConnectionResolver) Page() int32            { return int32(r.page) }
+func (r *todoConnectionResolver) Limit() int32           { return int32(r.limit) }
@@ -0,0 +1,51 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	todov1 "github.com/adityapryg/golang-demo/20-mini-project/internal/pb/todo/v1"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authServer implements todov1.AuthServiceServer on top of service.AuthService
+type authServer struct {
+	todov1.UnimplementedAuthServiceServer
+	authService *service.AuthService
+}
+
+func newAuthServer(authService *service.AuthService) *authServer {
+	return &authServer{authService: authService}
+}
+
+// Login exchanges username and password for a JWT
+func (s *authServer) Login(ctx context.Context, req *todov1.LoginRequest) (*todov1.LoginResponse, error) {
+	if req.GetUsername() == "" || req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "username and password are required")
+	}
+
+	auth, err := s.authService.Login(ctx, dto.UserLoginRequest{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, toStatusError(ctx, err)
+	}
+
+	return &todov1.LoginResponse{
+		Token: auth.Token,
+		User:  toPBUser(&auth.User),
+	}, nil
+}
+
+// GetProfile returns the authenticated user
+func (s *authServer) GetProfile(ctx context.Context, _ *todov1.GetProfileRequest) (*todov1.GetProfileResponse, error) {
+	user, err := s.authService.GetProfile(ctx, userIDFrom(ctx))
+	if err != nil {
+		return nil, toStatusError(ctx, err)
+	}
+
+	return &todov1.GetProfileResponse{User: toPBUser(user)}, nil
+}
@@ -0,0 +1,36 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatusError maps service errors to gRPC status codes, mirroring the
+// HTTP status codes used by the REST handlers
+func toStatusError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrTodoNotFound), errors.Is(err, service.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrUnauthorizedAccess):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrTitleRequired), errors.Is(err, service.ErrTitleTooLong),
+		errors.Is(err, service.ErrInvalidStatus), errors.Is(err, service.ErrInvalidPriority),
+		errors.Is(err, service.ErrInvalidDueDate), errors.Is(err, service.ErrInvalidDependency),
+		errors.Is(err, service.ErrDependencyCycle):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrTodoBlocked), errors.Is(err, service.ErrWIPLimitReached),
+		errors.Is(err, service.ErrTodoModified):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		// Jangan bocorkan detail error internal ke client
+		slog.ErrorContext(ctx, "grpc: request failed", "error", err)
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
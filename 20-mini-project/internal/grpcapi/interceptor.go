@@ -0,0 +1,92 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	todov1 "github.com/adityapryg/golang-demo/20-mini-project/internal/pb/todo/v1"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type contextKey int
+
+const userIDKey contextKey = iota
+
+// publicMethods tidak membutuhkan token JWT
+var publicMethods = map[string]bool{
+	todov1.AuthService_Login_FullMethodName: true,
+	healthpb.Health_Check_FullMethodName:    true,
+	healthpb.Health_Watch_FullMethodName:    true,
+}
+
+// unaryAuthInterceptor validates the JWT from the "authorization" metadata
+func unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if publicMethods[info.FullMethod] || isReflection(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	ctx, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuthInterceptor is the streaming counterpart of unaryAuthInterceptor
+func streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if publicMethods[info.FullMethod] || isReflection(info.FullMethod) {
+		return handler(srv, ss)
+	}
+
+	ctx, err := authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate reads "authorization: Bearer <token>" and stores the user ID in the context
+func authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "token tidak ditemukan")
+	}
+
+	parts := strings.Split(values[0], " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, status.Error(codes.Unauthenticated, "format token tidak valid")
+	}
+
+	claims, err := utils.ValidateToken(parts[1])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "token tidak valid atau expired")
+	}
+
+	return context.WithValue(ctx, userIDKey, claims.UserID), nil
+}
+
+// userIDFrom returns the authenticated user ID set by the interceptors
+func userIDFrom(ctx context.Context) uint {
+	userID, _ := ctx.Value(userIDKey).(uint)
+	return userID
+}
+
+func isReflection(method string) bool {
+	return strings.HasPrefix(method, "/grpc.reflection.")
+}
+
+// authenticatedStream overrides Context so handlers see the user ID
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
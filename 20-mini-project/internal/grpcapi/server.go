@@ -0,0 +1,69 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	todov1 "github.com/adityapryg/golang-demo/20-mini-project/internal/pb/todo/v1"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server is the gRPC server sharing the service layer with the REST API
+type Server struct {
+	grpcServer *grpc.Server
+	health     *health.Server
+}
+
+// NewServer creates a gRPC server with JWT auth, health checking and reflection
+func NewServer(authService *service.AuthService, todoService *service.TodoService, shareService *service.BoardShareService, eventLog *event.Log) *Server {
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(streamAuthInterceptor),
+	)
+
+	todov1.RegisterAuthServiceServer(grpcServer, newAuthServer(authService))
+	todov1.RegisterTodoServiceServer(grpcServer, newTodoServer(todoService, shareService, eventLog))
+
+	// Health check standar (grpc.health.v1) untuk load balancer dan Kubernetes
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	for _, name := range []string{"", todov1.AuthService_ServiceDesc.ServiceName, todov1.TodoService_ServiceDesc.ServiceName} {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	// Reflection memudahkan debugging dengan grpcurl
+	reflection.Register(grpcServer)
+
+	return &Server{
+		grpcServer: grpcServer,
+		health:     healthServer,
+	}
+}
+
+// Serve accepts connections on the listener until Stop is called
+func (s *Server) Serve(listener net.Listener) error {
+	return s.grpcServer.Serve(listener)
+}
+
+// Shutdown marks every service as not serving and waits for in-flight RPCs
+// to finish. RPCs still running when ctx expires are cancelled.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+}
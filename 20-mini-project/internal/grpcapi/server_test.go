@@ -0,0 +1,260 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	todov1 "github.com/adityapryg/golang-demo/20-mini-project/internal/pb/todo/v1"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+// grpcTest server gRPC lengkap di atas SQLite in-memory dengan dua user
+type grpcTest struct {
+	conn     *grpc.ClientConn
+	eventLog *event.Log
+	users    [2]model.User
+}
+
+func newGRPCTest(t *testing.T) *grpcTest {
+	t.Helper()
+	cfg := config.Default()
+	cfg.DBDriver = config.DriverSQLite
+	cfg.DBName = config.SQLiteMemory
+	cfg.DBLogLevel = "silent"
+	utils.SetJWTSecret(cfg.JWTSecret)
+
+	db, err := config.NewDatabase(cfg)
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrations, err := migrate.Embedded(cfg.DBDriver)
+	require.NoError(t, err)
+	migrator, err := migrate.New(sqlDB, cfg.DBDriver, migrations)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	todoRepo := repository.NewTodoRepository(database.NewCluster(db, database.Options{}))
+	boardRepo := repository.NewBoardRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	events := event.NewBus()
+	eventLog := event.NewLog(100)
+	events.Subscribe(eventLog.Append)
+	todoService := service.NewTodoService(todoRepo, boardRepo, events, func() service.TodoSettings {
+		return service.TodoSettings{WIPLimit: cfg.WIPLimit}
+	})
+	shareService := service.NewBoardShareService(boardRepo, userRepo, todoRepo)
+
+	server := NewServer(service.NewAuthService(userRepo), todoService, shareService, eventLog)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	})
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	test := &grpcTest{conn: conn, eventLog: eventLog}
+	for i := range test.users {
+		test.users[i] = model.User{
+			Username: fmt.Sprintf("grpc%d", i),
+			Email:    fmt.Sprintf("grpc%d@example.com", i),
+			Password: "-",
+		}
+		require.NoError(t, db.Create(&test.users[i]).Error)
+	}
+	return test
+}
+
+// as mengembalikan context dengan token JWT user
+func (gt *grpcTest) as(t *testing.T, user model.User) context.Context {
+	t.Helper()
+	token, err := utils.GenerateToken(user.ID, user.Username)
+	require.NoError(t, err)
+	return withAuthorization("Bearer " + token)
+}
+
+func withAuthorization(value string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", value)
+}
+
+func TestAuthInterceptorAllowsPublicMethods(t *testing.T) {
+	gt := newGRPCTest(t)
+	ctx := context.Background()
+
+	resp, err := healthpb.NewHealthClient(gt.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	stream, err := reflectionpb.NewServerReflectionClient(gt.conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	reflection, err := stream.Recv()
+	require.NoError(t, err)
+	var services []string
+	for _, service := range reflection.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	assert.Contains(t, services, todov1.TodoService_ServiceDesc.ServiceName)
+
+	// Login publik: kredensial salah dibalas Unauthenticated dari service, bukan interceptor
+	_, err = todov1.NewAuthServiceClient(gt.conn).Login(ctx, &todov1.LoginRequest{Username: "nobody", Password: "x"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, service.ErrInvalidCredentials.Error(), status.Convert(err).Message())
+}
+
+func TestAuthInterceptorProtectsTodoService(t *testing.T) {
+	gt := newGRPCTest(t)
+	client := todov1.NewTodoServiceClient(gt.conn)
+
+	for name, ctx := range map[string]context.Context{
+		"missing token":  context.Background(),
+		"not bearer":     withAuthorization("Token abc"),
+		"invalid token":  withAuthorization("Bearer not-a-jwt"),
+		"too many parts": withAuthorization("Bearer a b"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.ListTodos(ctx, &todov1.ListTodosRequest{})
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+			stream, err := client.WatchTodos(ctx, &todov1.WatchTodosRequest{})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	}
+
+	_, err := todov1.NewAuthServiceClient(gt.conn).GetProfile(context.Background(), &todov1.GetProfileRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Token valid: user ID dari token dipakai handler
+	ctx := gt.as(t, gt.users[0])
+	created, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{Title: "Lewat gRPC", Status: "pending", Priority: "medium"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(gt.users[0].ID), created.GetTodo().GetUserId())
+
+	list, err := client.ListTodos(ctx, &todov1.ListTodosRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), list.GetTotalCount())
+}
+
+func TestToStatusErrorMapsServiceErrors(t *testing.T) {
+	ctx := context.Background()
+	for err, want := range map[error]codes.Code{
+		service.ErrTodoNotFound:       codes.NotFound,
+		service.ErrUserNotFound:       codes.NotFound,
+		service.ErrUnauthorizedAccess: codes.PermissionDenied,
+		service.ErrInvalidCredentials: codes.Unauthenticated,
+		service.ErrTitleRequired:      codes.InvalidArgument,
+		service.ErrTitleTooLong:       codes.InvalidArgument,
+		service.ErrInvalidStatus:      codes.InvalidArgument,
+		service.ErrInvalidPriority:    codes.InvalidArgument,
+		service.ErrInvalidDueDate:     codes.InvalidArgument,
+		service.ErrInvalidDependency:  codes.InvalidArgument,
+		service.ErrDependencyCycle:    codes.InvalidArgument,
+		service.ErrTodoBlocked:        codes.FailedPrecondition,
+		service.ErrWIPLimitReached:    codes.FailedPrecondition,
+		service.ErrTodoModified:       codes.FailedPrecondition,
+	} {
+		got := status.Convert(toStatusError(ctx, err))
+		assert.Equal(t, want, got.Code(), err.Error())
+		assert.Equal(t, err.Error(), got.Message())
+	}
+
+	// Error yang dibungkus tetap dikenali
+	assert.Equal(t, codes.NotFound, status.Code(toStatusError(ctx, fmt.Errorf("update todo: %w", service.ErrTodoNotFound))))
+
+	// Error internal tidak dibocorkan ke client
+	got := status.Convert(toStatusError(ctx, errors.New("pq: connection refused")))
+	assert.Equal(t, codes.Internal, got.Code())
+	assert.Equal(t, "internal server error", got.Message())
+}
+
+// watch membuka WatchTodos sebagai user dan mengirim judul todo setiap event ke channel
+func (gt *grpcTest) watch(t *testing.T, user model.User) (<-chan string, <-chan error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(gt.as(t, user))
+	t.Cleanup(cancel)
+	stream, err := todov1.NewTodoServiceClient(gt.conn).WatchTodos(ctx, &todov1.WatchTodosRequest{})
+	require.NoError(t, err)
+	// Server mengirim header setelah subscribe, jadi event berikutnya pasti diterima
+	_, err = stream.Header()
+	require.NoError(t, err)
+
+	titles := make(chan string, 10)
+	done := make(chan error, 1)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				done <- err
+				return
+			}
+			titles <- resp.GetEvent().GetType() + " " + resp.GetEvent().GetTodo().GetTitle()
+		}
+	}()
+	return titles, done
+}
+
+func TestWatchTodosStreamsOwnEvents(t *testing.T) {
+	gt := newGRPCTest(t)
+	client := todov1.NewTodoServiceClient(gt.conn)
+	titles, done := gt.watch(t, gt.users[0])
+
+	// Todo user lain tidak ikut terkirim
+	_, err := client.CreateTodo(gt.as(t, gt.users[1]), &todov1.CreateTodoRequest{Title: "Milik orang lain", Status: "pending", Priority: "medium"})
+	require.NoError(t, err)
+	created, err := client.CreateTodo(gt.as(t, gt.users[0]), &todov1.CreateTodoRequest{Title: "Punya sendiri", Status: "pending", Priority: "medium"})
+	require.NoError(t, err)
+	title := "Judul baru"
+	_, err = client.UpdateTodo(gt.as(t, gt.users[0]), &todov1.UpdateTodoRequest{Id: created.GetTodo().GetId(), Title: &title})
+	require.NoError(t, err)
+
+	for _, want := range []string{event.TodoCreated + " Punya sendiri", event.TodoUpdated + " Judul baru"} {
+		select {
+		case got := <-titles:
+			assert.Equal(t, want, got)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event received, want %q", want)
+		}
+	}
+	assert.Empty(t, titles)
+
+	// Shutdown: stream diakhiri dengan Unavailable agar client reconnect
+	gt.eventLog.Close()
+	select {
+	case err := <-done:
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not end after the log was closed")
+	}
+}
@@ -0,0 +1,227 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	todov1 "github.com/adityapryg/golang-demo/20-mini-project/internal/pb/todo/v1"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// todoServer implements todov1.TodoServiceServer on top of service.TodoService
+type todoServer struct {
+	todov1.UnimplementedTodoServiceServer
+	todoService  *service.TodoService
+	shareService *service.BoardShareService
+	eventLog     *event.Log
+}
+
+func newTodoServer(todoService *service.TodoService, shareService *service.BoardShareService, eventLog *event.Log) *todoServer {
+	return &todoServer{
+		todoService:  todoService,
+		shareService: shareService,
+		eventLog:     eventLog,
+	}
+}
+
+// CreateTodo creates a todo for the authenticated user
+func (s *todoServer) CreateTodo(ctx context.Context, req *todov1.CreateTodoRequest) (*todov1.CreateTodoResponse, error) {
+	todo, err := s.todoService.CreateTodo(ctx, userIDFrom(ctx), dto.CreateTodoRequest{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Status:      req.GetStatus(),
+		Priority:    req.GetPriority(),
+		DueDate:     req.GetDueDate(),
+		BlockedBy:   toUintIDs(req.GetBlockedBy()),
+	})
+	if err != nil {
+		return nil, toStatusError(ctx, err)
+	}
+
+	return &todov1.CreateTodoResponse{Todo: toPBTodo(service.ToTodoResponse(todo))}, nil
+}
+
+// GetTodo returns a single todo owned by the authenticated user
+func (s *todoServer) GetTodo(ctx context.Context, req *todov1.GetTodoRequest) (*todov1.GetTodoResponse, error) {
+	todo, err := s.todoService.GetTodoByID(ctx, uint(req.GetId()), userIDFrom(ctx))
+	if err != nil {
+		return nil, toStatusError(ctx, err)
+	}
+
+	return &todov1.GetTodoResponse{Todo: toPBTodo(service.ToTodoResponse(todo))}, nil
+}
+
+// ListTodos returns one page of the authenticated user's todos
+func (s *todoServer) ListTodos(ctx context.Context, req *todov1.ListTodosRequest) (*todov1.ListTodosResponse, error) {
+	page, limit := int(req.GetPage()), int(req.GetLimit())
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	todos, total, err := s.todoService.GetUserTodosPage(ctx, userIDFrom(ctx), req.GetStatus(), req.GetPriority(), page, limit)
+	if err != nil {
+		return nil, toStatusError(ctx, err)
+	}
+
+	response := &todov1.ListTodosResponse{
+		Todos:      make([]*todov1.Todo, len(todos)),
+		TotalCount: total,
+		Page:       int32(page),
+		Limit:      int32(limit),
+	}
+	for i := range todos {
+		response.Todos[i] = toPBTodo(service.ToTodoResponse(&todos[i]))
+	}
+	return response, nil
+}
+
+// UpdateTodo updates only the fields present in the request
+func (s *todoServer) UpdateTodo(ctx context.Context, req *todov1.UpdateTodoRequest) (*todov1.UpdateTodoResponse, error) {
+	update := dto.UpdateTodoRequest{
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      req.Status,
+		Priority:    req.Priority,
+		DueDate:     req.DueDate,
+	}
+	if req.GetReplaceBlockedBy() {
+		blockedBy := toUintIDs(req.GetBlockedBy())
+		update.BlockedBy = &blockedBy
+	}
+
+	todo, err := s.todoService.UpdateTodo(ctx, uint(req.GetId()), userIDFrom(ctx), update)
+	if err != nil {
+		return nil, toStatusError(ctx, err)
+	}
+
+	return &todov1.UpdateTodoResponse{Todo: toPBTodo(service.ToTodoResponse(todo))}, nil
+}
+
+// DeleteTodo deletes a todo owned by the authenticated user
+func (s *todoServer) DeleteTodo(ctx context.Context, req *todov1.DeleteTodoRequest) (*todov1.DeleteTodoResponse, error) {
+	if err := s.todoService.DeleteTodo(ctx, uint(req.GetId()), userIDFrom(ctx)); err != nil {
+		return nil, toStatusError(ctx, err)
+	}
+
+	return &todov1.DeleteTodoResponse{}, nil
+}
+
+// WatchTodos streams todo changes on the authenticated user's board and on
+// boards shared with them until the client disconnects
+func (s *todoServer) WatchTodos(_ *todov1.WatchTodosRequest, stream todov1.TodoService_WatchTodosServer) error {
+	ctx := stream.Context()
+	userID := userIDFrom(ctx)
+
+	// Visibility sama dengan SSE dan room WebSocket, dibaca dari primary
+	// karena share bisa baru saja dibuat
+	checkCtx := database.WithPrimary(ctx)
+	ownerIDs, err := s.shareService.VisibleBoards(checkCtx, userID)
+	if err != nil {
+		return toStatusError(ctx, err)
+	}
+	entries, cancel := s.eventLog.Subscribe(ownerIDs)
+	defer cancel()
+	// Header dikirim segera supaya client tahu stream sudah aktif
+	// sebelum event pertama
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.eventLog.Done():
+			// Server shutdown: client reconnect ke instance lain
+			return status.Error(codes.Unavailable, "server is shutting down, reconnect to continue")
+		case entry, ok := <-entries:
+			if !ok {
+				// Channel ditutup karena client terlalu lambat membaca
+				return status.Error(codes.ResourceExhausted, "client is too slow, reconnect to continue")
+			}
+
+			// Collaborator yang sudah dihapus berhenti menerima event tanpa reconnect
+			allowed, err := s.shareService.CanView(checkCtx, entry.Event.UserID, userID)
+			if err != nil {
+				return toStatusError(ctx, err)
+			}
+			if !allowed {
+				continue
+			}
+			if err := stream.Send(&todov1.WatchTodosResponse{Event: toPBEvent(entry.Event)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Helper functions
+
+func toUintIDs(ids []uint64) []uint {
+	result := make([]uint, len(ids))
+	for i, id := range ids {
+		result[i] = uint(id)
+	}
+	return result
+}
+
+func toUint64IDs(ids []uint) []uint64 {
+	result := make([]uint64, len(ids))
+	for i, id := range ids {
+		result[i] = uint64(id)
+	}
+	return result
+}
+
+func toPBUser(user *dto.UserResponse) *todov1.User {
+	return &todov1.User{
+		Id:        uint64(user.ID),
+		Username:  user.Username,
+		Email:     user.Email,
+		FullName:  user.FullName,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+	}
+}
+
+func toPBTodo(todo dto.TodoResponse) *todov1.Todo {
+	result := &todov1.Todo{
+		Id:          uint64(todo.ID),
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      todo.Status,
+		Priority:    todo.Priority,
+		UserId:      uint64(todo.UserID),
+		BlockedBy:   toUint64IDs(todo.BlockedBy),
+		Blocking:    toUint64IDs(todo.Blocking),
+		Version:     uint64(todo.Version),
+		CreatedAt:   timestamppb.New(todo.CreatedAt),
+		UpdatedAt:   timestamppb.New(todo.UpdatedAt),
+	}
+	if todo.DueDate != nil {
+		result.DueDate = timestamppb.New(*todo.DueDate)
+	}
+	return result
+}
+
+func toPBEvent(e event.Event) *todov1.TodoEvent {
+	result := &todov1.TodoEvent{
+		Id:        e.ID,
+		Type:      e.Type,
+		TodoId:    uint64(e.TodoID),
+		CreatedAt: timestamppb.New(e.CreatedAt),
+	}
+	if todo, ok := e.Data.(dto.TodoResponse); ok {
+		result.Todo = toPBTodo(todo)
+	}
+	return result
+}
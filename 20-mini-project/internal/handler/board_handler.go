@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// BoardHandler handles kanban board HTTP requests
+type BoardHandler struct {
+	todoService  *service.TodoService
+	shareService *service.BoardShareService
+}
+
+// NewBoardHandler creates a new board handler instance
+func NewBoardHandler(todoService *service.TodoService, shareService *service.BoardShareService) *BoardHandler {
+	return &BoardHandler{
+		todoService:  todoService,
+		shareService: shareService,
+	}
+}
+
+// GetBoard handles GET /api/v1/board
+// @Summary Get kanban board
+// @Description Retrieve the authenticated user's todos grouped into status columns
+// @Tags board
+// @Produce json
+// @Param render query string false "Set to html to include description_html"
+// @Success 200 {object} dto.SuccessResponse{data=dto.BoardResponse}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/board [get]
+// @Security BearerAuth
+func (h *BoardHandler) GetBoard(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	columns, err := h.todoService.GetBoard(c.Request.Context(), userID.(uint))
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "Failed to retrieve board", err)
+		return
+	}
+
+	response := dto.BoardResponse{
+		Columns: make([]dto.BoardColumnResponse, len(columns)),
+	}
+	for i, column := range columns {
+		todos := make([]dto.TodoResponse, len(column.Todos))
+		for j := range column.Todos {
+			todos[j] = toTodoResponse(c, &column.Todos[j])
+		}
+
+		response.Columns[i] = dto.BoardColumnResponse{
+			Status:   column.Status,
+			WIPLimit: column.WIPLimit,
+			Count:    len(todos),
+			Todos:    todos,
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Board retrieved successfully",
+		Data:    response,
+	})
+}
+
+// MoveTodo handles POST /api/v1/board/todos/:id/move
+// @Summary Move a todo on the board
+// @Description Move a todo to another column and/or position; enforces WIP limits and blockers
+// @Tags board
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param move body dto.MoveTodoRequest true "Target column and position"
+// @Success 200 {object} dto.SuccessResponse{data=dto.TodoResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/board/todos/{id}/move [post]
+// @Security BearerAuth
+func (h *BoardHandler) MoveTodo(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid todo ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	var req dto.MoveTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	todo, err := h.todoService.MoveTodo(c.Request.Context(), uint(todoID), userID.(uint), req.Status, req.Position)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		message := "Failed to move todo"
+
+		if errors.Is(err, service.ErrTodoNotFound) {
+			statusCode = http.StatusNotFound
+			message = "Todo not found"
+		} else if errors.Is(err, service.ErrUnauthorizedAccess) {
+			statusCode = http.StatusForbidden
+			message = "You don't have permission to update this todo"
+		} else if errors.Is(err, service.ErrInvalidStatus) {
+			statusCode = http.StatusBadRequest
+			message = err.Error()
+		} else if errors.Is(err, service.ErrWIPLimitReached) || errors.Is(err, service.ErrTodoBlocked) ||
+			errors.Is(err, service.ErrTodoModified) {
+			statusCode = http.StatusConflict
+			message = err.Error()
+		}
+
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Todo moved successfully",
+		Data:    toTodoResponse(c, todo),
+	})
+}
+
+// GetSettings handles GET /api/v1/board/settings
+// @Summary Get board settings
+// @Description Retrieve the authenticated user's board settings (WIP limit)
+// @Tags board
+// @Produce json
+// @Success 200 {object} dto.SuccessResponse{data=dto.BoardSettingsResponse}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/board/settings [get]
+// @Security BearerAuth
+func (h *BoardHandler) GetSettings(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	limit, err := h.todoService.GetWIPLimit(c.Request.Context(), userID.(uint))
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "Failed to retrieve board settings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Board settings retrieved successfully",
+		Data:    dto.BoardSettingsResponse{WIPLimit: limit},
+	})
+}
+
+// UpdateSettings handles PUT /api/v1/board/settings
+// @Summary Update board settings
+// @Description Set the authenticated user's in_progress WIP limit (0 disables it)
+// @Tags board
+// @Accept json
+// @Produce json
+// @Param settings body dto.UpdateBoardSettingsRequest true "Board settings"
+// @Success 200 {object} dto.SuccessResponse{data=dto.BoardSettingsResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/board/settings [put]
+// @Security BearerAuth
+func (h *BoardHandler) UpdateSettings(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	var req dto.UpdateBoardSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	if err := h.todoService.SetWIPLimit(c.Request.Context(), userID.(uint), *req.WIPLimit); err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "Failed to update board settings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Board settings updated successfully",
+		Data:    dto.BoardSettingsResponse{WIPLimit: *req.WIPLimit},
+	})
+}
+
+// GetShares handles GET /api/v1/board/shares
+// @Summary List board collaborators
+// @Description Users the authenticated user's board is shared with. Collaborators can follow the board
+// @Description and its todos live over the WebSocket channel; only the owner can change todos.
+// @Tags board
+// @Produce json
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.BoardShareResponse}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/board/shares [get]
+// @Security BearerAuth
+func (h *BoardHandler) GetShares(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	shares, err := h.shareService.GetShares(c.Request.Context(), userID.(uint))
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "Failed to retrieve board collaborators", err)
+		return
+	}
+
+	response := make([]dto.BoardShareResponse, len(shares))
+	for i := range shares {
+		response[i] = toBoardShareResponse(&shares[i])
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Board collaborators retrieved successfully",
+		Data:    response,
+	})
+}
+
+// Share handles POST /api/v1/board/shares
+// @Summary Share the board with a collaborator
+// @Description Give another user live read access to the authenticated user's board and todos
+// @Tags board
+// @Accept json
+// @Produce json
+// @Param share body dto.ShareBoardRequest true "Collaborator username"
+// @Success 201 {object} dto.SuccessResponse{data=dto.BoardShareResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/board/shares [post]
+// @Security BearerAuth
+func (h *BoardHandler) Share(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	var req dto.ShareBoardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	share, err := h.shareService.ShareBoard(c.Request.Context(), userID.(uint), req.Username)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		message := "Failed to share board"
+
+		if errors.Is(err, service.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			message = "User not found"
+		} else if errors.Is(err, service.ErrShareWithSelf) {
+			statusCode = http.StatusBadRequest
+			message = err.Error()
+		}
+
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Message: "Board shared successfully",
+		Data:    toBoardShareResponse(share),
+	})
+}
+
+// Unshare handles DELETE /api/v1/board/shares/:user_id
+// @Summary Remove a board collaborator
+// @Description Revoke a collaborator's access; their open WebSocket subscriptions to the board are closed
+// @Tags board
+// @Produce json
+// @Param user_id path int true "Collaborator user ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/board/shares/{user_id} [delete]
+// @Security BearerAuth
+func (h *BoardHandler) Unshare(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	collaboratorID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid user ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	if err := h.shareService.UnshareBoard(c.Request.Context(), userID.(uint), uint(collaboratorID)); err != nil {
+		statusCode := http.StatusInternalServerError
+		message := "Failed to remove collaborator"
+
+		if errors.Is(err, service.ErrShareNotFound) {
+			statusCode = http.StatusNotFound
+			message = err.Error()
+		}
+
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Collaborator removed successfully",
+	})
+}
+
+func toBoardShareResponse(share *model.BoardShare) dto.BoardShareResponse {
+	return dto.BoardShareResponse{
+		UserID:    share.UserID,
+		Username:  share.User.Username,
+		FullName:  share.User.FullName,
+		CreatedAt: share.CreatedAt,
+	}
+}
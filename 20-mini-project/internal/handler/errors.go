@@ -0,0 +1,6 @@
+package handler
+
+import "github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+
+// errMissingUserID terjadi jika handler protected dipanggil tanpa AuthMiddleware
+var errMissingUserID = apierror.New(apierror.CodeUnauthorized, "User ID not found in context")
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// sseHeartbeatInterval interval komentar heartbeat agar koneksi tidak
+// diputus oleh proxy/load balancer saat tidak ada event
+const sseHeartbeatInterval = 15 * time.Second
+
+// EventHandler handles the Server-Sent Events stream of todo changes
+type EventHandler struct {
+	eventLog     *event.Log
+	shareService *service.BoardShareService
+	heartbeat    time.Duration
+}
+
+// NewEventHandler creates a new event handler instance
+func NewEventHandler(eventLog *event.Log, shareService *service.BoardShareService) *EventHandler {
+	return &EventHandler{
+		eventLog:     eventLog,
+		shareService: shareService,
+		heartbeat:    sseHeartbeatInterval,
+	}
+}
+
+// Stream handles GET /api/v1/events
+// @Summary Stream todo changes
+// @Description Server-Sent Events stream of todo.created, todo.updated and todo.deleted events on the authenticated user's board
+// @Description and on boards shared with them (see /board/shares).
+// @Description Send Last-Event-ID to resume; a "reset" event means missed events are no longer available and data should be reloaded.
+// @Tags events
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Success 200 {string} string "text/event-stream"
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/v1/events [get]
+// @Security BearerAuth
+func (h *EventHandler) Stream(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	// Last-Event-ID dikirim otomatis oleh EventSource saat reconnect
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_event_id")
+	}
+
+	// Board yang boleh diikuti sama dengan room WebSocket: milik sendiri dan
+	// yang dibagikan. Dibaca dari primary karena share bisa baru saja dibuat.
+	ctx := database.WithPrimary(c.Request.Context())
+	ownerIDs, err := h.shareService.VisibleBoards(ctx, userID.(uint))
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "Failed to open event stream", err)
+		return
+	}
+
+	// Subscribe dulu sebelum replay supaya tidak ada event yang terlewat
+	entries, cancel := h.eventLog.Subscribe(ownerIDs)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Matikan buffering di nginx
+	c.Status(http.StatusOK)
+
+	// Stream berumur panjang: lepas WriteTimeout server untuk koneksi ini
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	var sent uint64
+	if lastID != "" {
+		lastSeq, ok := h.eventLog.ParseEventID(lastID)
+		var backlog []event.Entry
+		complete := false
+		if ok {
+			backlog, complete = h.eventLog.Since(ownerIDs, lastSeq)
+		}
+		if !complete {
+			// Event yang terlewat sudah terbuang dari log, atau ID berasal dari
+			// proses lain (restart, instance lain) yang nomor urutnya tidak berlaku di sini
+			sent = h.eventLog.LastSeq()
+			writeSSE(c.Writer, h.eventLog.EventID(sent), "reset", "{}")
+		} else {
+			sent = lastSeq
+			for _, entry := range backlog {
+				if h.canView(ctx, userID.(uint), entry) {
+					sent = h.writeEntry(c.Writer, entry)
+				}
+			}
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-h.eventLog.Done():
+			// Server shutdown: client reconnect dengan Last-Event-ID. Instance lain
+			// punya log sendiri, jadi client akan menerima reset dan memuat ulang data.
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		case entry, ok := <-entries:
+			if !ok {
+				// Diputus karena terlalu lambat, client akan reconnect
+				return
+			}
+			if entry.Seq <= sent {
+				continue // Sudah dikirim saat replay
+			}
+			if !h.canView(ctx, userID.(uint), entry) {
+				continue
+			}
+			sent = h.writeEntry(c.Writer, entry)
+			c.Writer.Flush()
+		}
+	}
+}
+
+// canView re-checks access to the board of an entry, so a collaborator who
+// was removed stops receiving events without reconnecting
+func (h *EventHandler) canView(ctx context.Context, userID uint, entry event.Entry) bool {
+	allowed, err := h.shareService.CanView(ctx, entry.Event.UserID, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "sse: failed to check board access", "owner_id", entry.Event.UserID, "error", err)
+		return false
+	}
+	return allowed
+}
+
+// writeEntry writes an event log entry as an SSE message and returns its sequence number
+func (h *EventHandler) writeEntry(w io.Writer, entry event.Entry) uint64 {
+	data, err := json.Marshal(entry.Event)
+	if err != nil {
+		return entry.Seq
+	}
+	writeSSE(w, h.eventLog.EventID(entry.Seq), entry.Event.Type, string(data))
+	return entry.Seq
+}
+
+// writeSSE writes a single Server-Sent Events message
+func writeSSE(w io.Writer, id, eventType, data string) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, eventType, data)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseMessage satu pesan SSE; komentar heartbeat disimpan di comment
+type sseMessage struct {
+	id, event, data, comment string
+}
+
+// openStream membuka GET /events sebagai user 1 dan mengirim setiap pesan ke channel
+func openStream(t *testing.T, h *EventHandler, lastEventID string) <-chan sseMessage {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/events", func(c *gin.Context) { c.Set("userID", uint(1)) }, h.Stream)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	messages := make(chan sseMessage, 100)
+	go func() {
+		defer close(messages)
+		scanner := bufio.NewScanner(resp.Body)
+		var msg sseMessage
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				messages <- msg
+				msg = sseMessage{}
+			case strings.HasPrefix(line, ":"):
+				msg.comment = strings.TrimSpace(line[1:])
+			default:
+				field, value, _ := strings.Cut(line, ": ")
+				switch field {
+				case "id":
+					msg.id = value
+				case "event":
+					msg.event = value
+				case "data":
+					msg.data = value
+				}
+			}
+		}
+	}()
+	return messages
+}
+
+func nextMessage(t *testing.T, messages <-chan sseMessage) sseMessage {
+	t.Helper()
+	select {
+	case msg, ok := <-messages:
+		require.True(t, ok, "stream ended")
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+		return sseMessage{}
+	}
+}
+
+// newTestShareService BoardShareService di atas database SQLite in-memory tanpa share
+func newTestShareService(t *testing.T) *service.BoardShareService {
+	t.Helper()
+	cfg := config.Default()
+	cfg.DBDriver = config.DriverSQLite
+	cfg.DBName = config.SQLiteMemory
+	cfg.DBLogLevel = "silent"
+
+	db, err := config.NewDatabase(cfg)
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrations, err := migrate.Embedded(cfg.DBDriver)
+	require.NoError(t, err)
+	migrator, err := migrate.New(sqlDB, cfg.DBDriver, migrations)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	return service.NewBoardShareService(
+		repository.NewBoardRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewTodoRepository(database.NewCluster(db, database.Options{})),
+	)
+}
+
+func newTestEventHandler(t *testing.T, eventLog *event.Log) *EventHandler {
+	t.Helper()
+	h := NewEventHandler(eventLog, newTestShareService(t))
+	h.heartbeat = time.Hour
+	return h
+}
+
+func TestStreamReplaysFromLastEventID(t *testing.T) {
+	eventLog := event.NewLog(10)
+	eventLog.Append(event.New(event.TodoCreated, 1, 1, nil))
+	eventLog.Append(event.New(event.TodoCreated, 2, 2, nil))
+	eventLog.Append(event.New(event.TodoUpdated, 1, 1, nil))
+
+	messages := openStream(t, newTestEventHandler(t, eventLog), eventLog.EventID(1))
+	msg := nextMessage(t, messages)
+	assert.Equal(t, eventLog.EventID(3), msg.id)
+	assert.Equal(t, event.TodoUpdated, msg.event)
+
+	// Event baru diteruskan langsung, event user lain tidak
+	eventLog.Append(event.New(event.TodoDeleted, 2, 2, nil))
+	eventLog.Append(event.New(event.TodoDeleted, 1, 1, nil))
+	msg = nextMessage(t, messages)
+	assert.Equal(t, eventLog.EventID(5), msg.id)
+	assert.Equal(t, event.TodoDeleted, msg.event)
+}
+
+func TestStreamSendsResetForUnknownEventID(t *testing.T) {
+	eventLog := event.NewLog(2)
+	for i := 0; i < 4; i++ {
+		eventLog.Append(event.New(event.TodoCreated, 1, 1, nil))
+	}
+	restarted := event.NewLog(2)
+
+	for name, lastEventID := range map[string]string{
+		"history gone":   eventLog.EventID(1),
+		"other instance": restarted.EventID(4),
+		"legacy id":      "4",
+		"future seq":     eventLog.EventID(9),
+	} {
+		t.Run(name, func(t *testing.T) {
+			msg := nextMessage(t, openStream(t, newTestEventHandler(t, eventLog), lastEventID))
+			assert.Equal(t, "reset", msg.event)
+			assert.Equal(t, eventLog.EventID(4), msg.id, "reset carries an id the client can resume from")
+		})
+	}
+}
+
+func TestStreamSendsHeartbeats(t *testing.T) {
+	eventLog := event.NewLog(10)
+	h := newTestEventHandler(t, eventLog)
+	h.heartbeat = 20 * time.Millisecond
+
+	messages := openStream(t, h, "")
+	for i := 0; i < 2; i++ {
+		msg := nextMessage(t, messages)
+		assert.Equal(t, "heartbeat", msg.comment)
+		assert.Empty(t, msg.event)
+	}
+}
+
+func TestStreamEndsOnShutdown(t *testing.T) {
+	eventLog := event.NewLog(10)
+	messages := openStream(t, newTestEventHandler(t, eventLog), "")
+	eventLog.Close()
+
+	select {
+	case _, ok := <-messages:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not end after the log was closed")
+	}
+}
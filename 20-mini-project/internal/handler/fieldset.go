@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// todoFields adalah nama field JSON dto.TodoResponse yang boleh dipilih lewat ?fields=
+var todoFields = jsonFieldNames(reflect.TypeOf(dto.TodoResponse{}))
+
+// todoView holds the ?fields= and ?expand= options of a todo GET request
+type todoView struct {
+	fields []string
+	expand []string
+}
+
+// parseTodoView reads ?fields=id,title and ?expand=user from the query string
+func parseTodoView(c *gin.Context) (todoView, error) {
+	view := todoView{
+		fields: splitQueryList(c.Query("fields")),
+		expand: splitQueryList(c.Query("expand")),
+	}
+
+	for _, field := range view.fields {
+		if !todoFields[field] {
+			return view, apierror.New(apierror.CodeInvalidFields, fmt.Sprintf("unknown field: %s", field))
+		}
+	}
+
+	// Relasi yang di-expand selalu ikut dikirim walaupun tidak ada di ?fields=
+	if len(view.fields) > 0 {
+		view.fields = append(view.fields, view.expand...)
+	}
+
+	return view, nil
+}
+
+// render converts a todo into its response, trimmed to the requested fields
+func (v todoView) render(c *gin.Context, todo *model.Todo) interface{} {
+	response := toTodoResponse(c, todo)
+	if len(v.fields) == 0 {
+		return response
+	}
+	return selectFields(response, v.fields)
+}
+
+// selectFields returns only the given JSON fields of a struct as a map
+func selectFields(value interface{}, fields []string) map[string]interface{} {
+	wanted := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		wanted[field] = true
+	}
+
+	v := reflect.ValueOf(value)
+	t := v.Type()
+	result := make(map[string]interface{}, len(fields))
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if wanted[name] {
+			result[name] = v.Field(i).Interface()
+		}
+	}
+	return result
+}
+
+// jsonFieldNames returns the JSON names of all exported fields of a struct type
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// splitQueryList splits "a, b,,a" into ["a", "b"]
+func splitQueryList(value string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" && !seen[part] {
+			seen[part] = true
+			result = append(result, part)
+		}
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/gql"
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLHandler handles GraphQL HTTP requests
+type GraphQLHandler struct {
+	schema *gql.Schema
+}
+
+// NewGraphQLHandler creates a new GraphQL handler instance
+func NewGraphQLHandler(schema *gql.Schema) *GraphQLHandler {
+	return &GraphQLHandler{
+		schema: schema,
+	}
+}
+
+// Query handles POST /graphql
+// @Summary Execute a GraphQL query
+// @Description Run a GraphQL query or mutation for the authenticated user. Errors are returned in the GraphQL "errors" array with an extensions.code.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body dto.GraphQLRequest true "GraphQL request"
+// @Success 200 {object} object
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /graphql [post]
+// @Security BearerAuth
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	var req dto.GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	response := h.schema.Exec(c.Request.Context(), userID.(uint), req.Query, req.OperationName, req.Variables)
+
+	// Sesuai konvensi GraphQL, error eksekusi tetap dikirim dengan status 200
+	c.JSON(http.StatusOK, response)
+}
@@ -3,41 +3,37 @@ package handler
 import (
 	"net/http"
 
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/health"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-// HealthHandler handles health check requests
+// HealthHandler handles liveness and readiness probes
 type HealthHandler struct {
-	db *gorm.DB
+	health          *health.Health
+	monitoringToken string
 }
 
-// NewHealthHandler creates a new health handler instance
-func NewHealthHandler(db *gorm.DB) *HealthHandler {
-	return &HealthHandler{db: db}
+// NewHealthHandler creates a new health handler instance. Readiness details
+// are only shown to requests carrying monitoringToken.
+func NewHealthHandler(h *health.Health, monitoringToken string) *HealthHandler {
+	return &HealthHandler{health: h, monitoringToken: monitoringToken}
 }
 
-// HealthCheck checks if API and database are running
+// HealthCheck is the original health endpoint, kept with its original
+// response body for existing monitors. It only reflects the database.
 // @Summary Health check
-// @Description Check if API and database connection are healthy
+// @Description Check if API and database connection are healthy (legacy, prefer /health/ready)
 // @Tags health
 // @Produce json
 // @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
 // @Router /health [get]
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
-	// Check database connection
-	sqlDB, err := h.db.DB()
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status":   "error",
-			"message":  "database connection failed",
-			"database": "disconnected",
-		})
-		return
-	}
+	report := h.health.Ready(c.Request.Context())
 
-	// Ping database
-	if err := sqlDB.Ping(); err != nil {
+	database, ok := report.Components["database"]
+	if !ok || database.Status != health.StatusUp {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
 			"status":   "error",
 			"message":  "database ping failed",
@@ -46,10 +42,49 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 		return
 	}
 
-	// All checks passed
 	c.JSON(http.StatusOK, gin.H{
 		"status":   "ok",
 		"message":  "API is running",
 		"database": "connected",
 	})
 }
+
+// Live reports whether the process is running. It never checks dependencies,
+// so a database outage does not make Kubernetes restart the pod.
+// @Summary Liveness probe
+// @Description Check if the API process is alive
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":         health.StatusUp,
+		"version":        h.health.Version(),
+		"uptime_seconds": int64(h.health.Uptime().Seconds()),
+	})
+}
+
+// Ready reports whether the API can serve traffic: database, migrations and
+// every registered checker must be up. Latency, errors and details are only
+// included for requests with the monitoring token.
+// @Summary Readiness probe
+// @Description Check database, migrations and registered dependencies
+// @Tags health
+// @Produce json
+// @Param Authorization header string false "Bearer MONITORING_TOKEN for component details"
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.health.Ready(c.Request.Context())
+	if !middleware.HasMonitoringToken(c, h.monitoringToken) {
+		report = report.Summary()
+	}
+
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SyncHandler handles offline-first delta sync HTTP requests
+type SyncHandler struct {
+	syncService *service.SyncService
+}
+
+// NewSyncHandler creates a new sync handler instance
+func NewSyncHandler(syncService *service.SyncService) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+	}
+}
+
+// Pull handles GET /api/v1/sync
+// @Summary Pull todo changes
+// @Description Retrieve todos changed since the given token, including tombstones for deleted todos
+// @Tags sync
+// @Produce json
+// @Param since query string false "Token from the previous pull; empty for a full sync"
+// @Param limit query int false "Maximum number of changes (default 500, max 1000)"
+// @Success 200 {object} dto.SuccessResponse{data=dto.SyncPullResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/sync [get]
+// @Security BearerAuth
+func (h *SyncHandler) Pull(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	var params dto.SyncPullParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.syncService.Pull(c.Request.Context(), userID.(uint), params.Since, params.Limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidSyncToken) {
+			status = http.StatusBadRequest
+		}
+		apierror.Respond(c, status, "Failed to pull changes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Changes retrieved successfully",
+		Data:    response,
+	})
+}
+
+// Push handles POST /api/v1/sync
+// @Summary Push offline mutations
+// @Description Apply a batch of create/update/delete mutations and report per-mutation results (applied, conflict or rejected)
+// @Tags sync
+// @Accept json
+// @Produce json
+// @Param request body dto.SyncPushRequest true "Mutations to apply"
+// @Success 200 {object} dto.SuccessResponse{data=dto.SyncPushResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/v1/sync [post]
+// @Security BearerAuth
+func (h *SyncHandler) Push(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	var req dto.SyncPushRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	response := h.syncService.Push(c.Request.Context(), userID.(uint), req)
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Mutations processed",
+		Data:    response,
+	})
+}
@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateHandler handles todo template HTTP requests
+type TemplateHandler struct {
+	templateService *service.TemplateService
+}
+
+// NewTemplateHandler creates a new template handler instance
+func NewTemplateHandler(templateService *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// Create handles POST /api/v1/templates
+// @Summary Create a new todo template
+// @Description Create a reusable checklist template for the authenticated user
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body dto.CreateTemplateRequest true "Template data"
+// @Success 201 {object} dto.SuccessResponse{data=dto.TemplateResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/templates [post]
+// @Security BearerAuth
+func (h *TemplateHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	var req dto.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(c.Request.Context(), userID.(uint), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		message := "Failed to create template"
+
+		if errors.Is(err, service.ErrInvalidPriority) {
+			statusCode = http.StatusBadRequest
+			message = err.Error()
+		}
+
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Message: "Template created successfully",
+		Data:    toTemplateResponse(template),
+	})
+}
+
+// GetAll handles GET /api/v1/templates
+// @Summary Get all templates for authenticated user
+// @Description Retrieve all todo templates for the authenticated user
+// @Tags templates
+// @Produce json
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.TemplateResponse}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/templates [get]
+// @Security BearerAuth
+func (h *TemplateHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	templates, err := h.templateService.GetUserTemplates(c.Request.Context(), userID.(uint))
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "Failed to retrieve templates", err)
+		return
+	}
+
+	responses := make([]dto.TemplateResponse, len(templates))
+	for i := range templates {
+		responses[i] = toTemplateResponse(&templates[i])
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Templates retrieved successfully",
+		Data:    responses,
+	})
+}
+
+// GetByID handles GET /api/v1/templates/:id
+// @Summary Get a specific template
+// @Description Retrieve a specific todo template by ID for the authenticated user
+// @Tags templates
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.TemplateResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/templates/{id} [get]
+// @Security BearerAuth
+func (h *TemplateHandler) GetByID(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid template ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	template, err := h.templateService.GetTemplateByID(c.Request.Context(), uint(templateID), userID.(uint))
+	if err != nil {
+		statusCode, message := templateErrorStatus(err, "Failed to retrieve template")
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Template retrieved successfully",
+		Data:    toTemplateResponse(template),
+	})
+}
+
+// Update handles PUT /api/v1/templates/:id
+// @Summary Update a template
+// @Description Update a todo template; a provided checklist replaces the existing one
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param template body dto.UpdateTemplateRequest true "Template data to update"
+// @Success 200 {object} dto.SuccessResponse{data=dto.TemplateResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/templates/{id} [put]
+// @Security BearerAuth
+func (h *TemplateHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid template ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	var req dto.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(c.Request.Context(), uint(templateID), userID.(uint), req)
+	if err != nil {
+		statusCode, message := templateErrorStatus(err, "Failed to update template")
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Template updated successfully",
+		Data:    toTemplateResponse(template),
+	})
+}
+
+// Delete handles DELETE /api/v1/templates/:id
+// @Summary Delete a template
+// @Description Delete a todo template for the authenticated user
+// @Tags templates
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/templates/{id} [delete]
+// @Security BearerAuth
+func (h *TemplateHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid template ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), uint(templateID), userID.(uint)); err != nil {
+		statusCode, message := templateErrorStatus(err, "Failed to delete template")
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Template deleted successfully",
+		Data:    nil,
+	})
+}
+
+// Instantiate handles POST /api/v1/templates/:id/instantiate
+// @Summary Create todos from a template
+// @Description Create one todo for the template and one per checklist item, with due dates relative to start_date
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param request body dto.InstantiateTemplateRequest false "Start date (defaults to today)"
+// @Success 201 {object} dto.SuccessResponse{data=[]dto.TodoResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/templates/{id}/instantiate [post]
+// @Security BearerAuth
+func (h *TemplateHandler) Instantiate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid template ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	// Body bersifat opsional, tanpa body start date = hari ini
+	var req dto.InstantiateTemplateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
+			return
+		}
+	}
+
+	todos, err := h.templateService.InstantiateTemplate(c.Request.Context(), uint(templateID), userID.(uint), req.StartDate)
+	if err != nil {
+		statusCode, message := templateErrorStatus(err, "Failed to instantiate template")
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	responses := make([]dto.TodoResponse, len(todos))
+	for i := range todos {
+		responses[i] = toTodoResponse(c, &todos[i])
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Message: "Todos created from template successfully",
+		Data:    responses,
+	})
+}
+
+// templateErrorStatus maps template service errors to HTTP status codes
+func templateErrorStatus(err error, fallback string) (int, string) {
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		return http.StatusNotFound, "Template not found"
+	case errors.Is(err, service.ErrUnauthorizedTemplateAccess):
+		return http.StatusForbidden, "You don't have permission to access this template"
+	case errors.Is(err, service.ErrInvalidPriority), errors.Is(err, service.ErrInvalidStartDate):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusInternalServerError, fallback
+	}
+}
+
+// toTemplateResponse converts a template model into its response DTO
+func toTemplateResponse(template *model.TodoTemplate) dto.TemplateResponse {
+	checklist := make([]dto.TemplateItemResponse, len(template.Items))
+	for i, item := range template.Items {
+		checklist[i] = dto.TemplateItemResponse{
+			ID:            item.ID,
+			Title:         item.Title,
+			Description:   item.Description,
+			Priority:      item.Priority,
+			DueOffsetDays: item.DueOffsetDays,
+			Position:      item.Position,
+		}
+	}
+
+	return dto.TemplateResponse{
+		ID:            template.ID,
+		Title:         template.Title,
+		Description:   template.Description,
+		Priority:      template.Priority,
+		DueOffsetDays: template.DueOffsetDays,
+		Checklist:     checklist,
+		UserID:        template.UserID,
+		CreatedAt:     template.CreatedAt,
+		UpdatedAt:     template.UpdatedAt,
+	}
+}
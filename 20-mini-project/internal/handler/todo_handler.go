@@ -2,11 +2,15 @@ package handler
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -30,9 +34,11 @@ func NewTodoHandler(todoService *service.TodoService) *TodoHandler {
 // @Accept json
 // @Produce json
 // @Param todo body dto.CreateTodoRequest true "Todo data"
+// @Param render query string false "Set to html to include description_html"
 // @Success 201 {object} dto.SuccessResponse{data=dto.TodoResponse}
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/v1/todos [post]
 // @Security BearerAuth
@@ -40,53 +46,37 @@ func (h *TodoHandler) Create(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "Unauthorized",
-			Error:   "User ID not found in context",
-		})
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
 		return
 	}
 
 	var req dto.CreateTodoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid request data",
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
 		return
 	}
 
-	todo, err := h.todoService.CreateTodo(userID.(uint), req)
+	todo, err := h.todoService.CreateTodo(c.Request.Context(), userID.(uint), req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		message := "Failed to create todo"
 
-		if errors.Is(err, service.ErrInvalidStatus) || errors.Is(err, service.ErrInvalidPriority) {
+		if errors.Is(err, service.ErrTitleRequired) || errors.Is(err, service.ErrTitleTooLong) ||
+			errors.Is(err, service.ErrInvalidStatus) || errors.Is(err, service.ErrInvalidPriority) ||
+			errors.Is(err, service.ErrInvalidDueDate) || errors.Is(err, service.ErrInvalidDependency) {
 			statusCode = http.StatusBadRequest
 			message = err.Error()
+		} else if errors.Is(err, service.ErrTodoBlocked) || errors.Is(err, service.ErrWIPLimitReached) ||
+			errors.Is(err, service.ErrTodoModified) {
+			statusCode = http.StatusConflict
+			message = err.Error()
 		}
 
-		c.JSON(statusCode, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, statusCode, message, err)
 		return
 	}
 
-	response := dto.TodoResponse{
-		ID:          todo.ID,
-		Title:       todo.Title,
-		Description: todo.Description,
-		Status:      todo.Status,
-		Priority:    todo.Priority,
-		DueDate:     todo.DueDate,
-		UserID:      todo.UserID,
-		CreatedAt:   todo.CreatedAt,
-		UpdatedAt:   todo.UpdatedAt,
-	}
+	response := toTodoResponse(c, todo)
 
 	c.JSON(http.StatusCreated, dto.SuccessResponse{
 		Success: true,
@@ -103,6 +93,9 @@ func (h *TodoHandler) Create(c *gin.Context) {
 // @Produce json
 // @Param status query string false "Filter by status (pending, in_progress, completed)"
 // @Param priority query string false "Filter by priority (low, medium, high)"
+// @Param render query string false "Set to html to include description_html"
+// @Param fields query string false "Comma separated fields to return, e.g. id,title,status"
+// @Param expand query string false "Comma separated relations to embed (user; project is not supported)"
 // @Success 200 {object} dto.SuccessResponse{data=[]dto.TodoResponse}
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
@@ -112,11 +105,7 @@ func (h *TodoHandler) Create(c *gin.Context) {
 func (h *TodoHandler) GetAll(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "Unauthorized",
-			Error:   "User ID not found in context",
-		})
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
 		return
 	}
 
@@ -124,38 +113,31 @@ func (h *TodoHandler) GetAll(c *gin.Context) {
 	status := c.Query("status")
 	priority := c.Query("priority")
 
-	todos, err := h.todoService.GetUserTodos(userID.(uint), status, priority)
+	view, err := parseTodoView(c)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	todos, err := h.todoService.GetUserTodos(c.Request.Context(), userID.(uint), status, priority, view.expand...)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		message := "Failed to retrieve todos"
 
-		if errors.Is(err, service.ErrInvalidStatus) || errors.Is(err, service.ErrInvalidPriority) {
+		if errors.Is(err, service.ErrInvalidStatus) || errors.Is(err, service.ErrInvalidPriority) ||
+			errors.Is(err, service.ErrInvalidExpand) {
 			statusCode = http.StatusBadRequest
 			message = err.Error()
 		}
 
-		c.JSON(statusCode, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, statusCode, message, err)
 		return
 	}
 
-	// Convert to response DTOs
-	responses := make([]dto.TodoResponse, len(todos))
-	for i, todo := range todos {
-		responses[i] = dto.TodoResponse{
-			ID:          todo.ID,
-			Title:       todo.Title,
-			Description: todo.Description,
-			Status:      todo.Status,
-			Priority:    todo.Priority,
-			DueDate:     todo.DueDate,
-			UserID:      todo.UserID,
-			CreatedAt:   todo.CreatedAt,
-			UpdatedAt:   todo.UpdatedAt,
-		}
+	// Convert to response DTOs, trimmed to ?fields= if given
+	responses := make([]interface{}, len(todos))
+	for i := range todos {
+		responses[i] = view.render(c, &todos[i])
 	}
 
 	c.JSON(http.StatusOK, dto.SuccessResponse{
@@ -172,6 +154,9 @@ func (h *TodoHandler) GetAll(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Todo ID"
+// @Param render query string false "Set to html to include description_html"
+// @Param fields query string false "Comma separated fields to return, e.g. id,title,status"
+// @Param expand query string false "Comma separated relations to embed (user; project is not supported)"
 // @Success 200 {object} dto.SuccessResponse{data=dto.TodoResponse}
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
@@ -182,26 +167,24 @@ func (h *TodoHandler) GetAll(c *gin.Context) {
 func (h *TodoHandler) GetByID(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "Unauthorized",
-			Error:   "User ID not found in context",
-		})
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
 		return
 	}
 
 	// Parse todo ID
 	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid todo ID",
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Invalid todo ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	view, err := parseTodoView(c)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, err.Error(), err)
 		return
 	}
 
-	todo, err := h.todoService.GetTodoByID(uint(todoID), userID.(uint))
+	todo, err := h.todoService.GetTodoByID(c.Request.Context(), uint(todoID), userID.(uint), view.expand...)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		message := "Failed to retrieve todo"
@@ -212,27 +195,16 @@ func (h *TodoHandler) GetByID(c *gin.Context) {
 		} else if errors.Is(err, service.ErrUnauthorizedAccess) {
 			statusCode = http.StatusForbidden
 			message = "You don't have permission to access this todo"
+		} else if errors.Is(err, service.ErrInvalidExpand) {
+			statusCode = http.StatusBadRequest
+			message = err.Error()
 		}
 
-		c.JSON(statusCode, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, statusCode, message, err)
 		return
 	}
 
-	response := dto.TodoResponse{
-		ID:          todo.ID,
-		Title:       todo.Title,
-		Description: todo.Description,
-		Status:      todo.Status,
-		Priority:    todo.Priority,
-		DueDate:     todo.DueDate,
-		UserID:      todo.UserID,
-		CreatedAt:   todo.CreatedAt,
-		UpdatedAt:   todo.UpdatedAt,
-	}
+	response := view.render(c, todo)
 
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Success: true,
@@ -249,46 +221,36 @@ func (h *TodoHandler) GetByID(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Todo ID"
 // @Param todo body dto.UpdateTodoRequest true "Todo data to update"
+// @Param render query string false "Set to html to include description_html"
 // @Success 200 {object} dto.SuccessResponse{data=dto.TodoResponse}
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/v1/todos/{id} [put]
 // @Security BearerAuth
 func (h *TodoHandler) Update(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "Unauthorized",
-			Error:   "User ID not found in context",
-		})
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
 		return
 	}
 
 	// Parse todo ID
 	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid todo ID",
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Invalid todo ID", apierror.Wrap(apierror.CodeInvalidID, err))
 		return
 	}
 
 	var req dto.UpdateTodoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid request data",
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
 		return
 	}
 
-	todo, err := h.todoService.UpdateTodo(uint(todoID), userID.(uint), req)
+	todo, err := h.todoService.UpdateTodo(c.Request.Context(), uint(todoID), userID.(uint), req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		message := "Failed to update todo"
@@ -299,33 +261,26 @@ func (h *TodoHandler) Update(c *gin.Context) {
 		} else if errors.Is(err, service.ErrUnauthorizedAccess) {
 			statusCode = http.StatusForbidden
 			message = "You don't have permission to update this todo"
-		} else if errors.Is(err, service.ErrInvalidStatus) || errors.Is(err, service.ErrInvalidPriority) {
+		} else if errors.Is(err, service.ErrTitleRequired) || errors.Is(err, service.ErrTitleTooLong) ||
+			errors.Is(err, service.ErrInvalidStatus) || errors.Is(err, service.ErrInvalidPriority) ||
+			errors.Is(err, service.ErrInvalidDueDate) || errors.Is(err, service.ErrInvalidDependency) ||
+			errors.Is(err, service.ErrDependencyCycle) {
 			statusCode = http.StatusBadRequest
 			message = err.Error()
+		} else if errors.Is(err, service.ErrTodoBlocked) || errors.Is(err, service.ErrWIPLimitReached) ||
+			errors.Is(err, service.ErrTodoModified) {
+			statusCode = http.StatusConflict
+			message = err.Error()
 		} else if errors.Is(err, gorm.ErrRecordNotFound) {
 			statusCode = http.StatusNotFound
 			message = "Todo not found"
 		}
 
-		c.JSON(statusCode, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, statusCode, message, err)
 		return
 	}
 
-	response := dto.TodoResponse{
-		ID:          todo.ID,
-		Title:       todo.Title,
-		Description: todo.Description,
-		Status:      todo.Status,
-		Priority:    todo.Priority,
-		DueDate:     todo.DueDate,
-		UserID:      todo.UserID,
-		CreatedAt:   todo.CreatedAt,
-		UpdatedAt:   todo.UpdatedAt,
-	}
+	response := toTodoResponse(c, todo)
 
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Success: true,
@@ -351,26 +306,18 @@ func (h *TodoHandler) Update(c *gin.Context) {
 func (h *TodoHandler) Delete(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Success: false,
-			Message: "Unauthorized",
-			Error:   "User ID not found in context",
-		})
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
 		return
 	}
 
 	// Parse todo ID
 	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid todo ID",
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Invalid todo ID", apierror.Wrap(apierror.CodeInvalidID, err))
 		return
 	}
 
-	err = h.todoService.DeleteTodo(uint(todoID), userID.(uint))
+	err = h.todoService.DeleteTodo(c.Request.Context(), uint(todoID), userID.(uint))
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		message := "Failed to delete todo"
@@ -381,13 +328,12 @@ func (h *TodoHandler) Delete(c *gin.Context) {
 		} else if errors.Is(err, service.ErrUnauthorizedAccess) {
 			statusCode = http.StatusForbidden
 			message = "You don't have permission to delete this todo"
+		} else if errors.Is(err, service.ErrTodoModified) {
+			statusCode = http.StatusConflict
+			message = err.Error()
 		}
 
-		c.JSON(statusCode, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, statusCode, message, err)
 		return
 	}
 
@@ -397,3 +343,21 @@ func (h *TodoHandler) Delete(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+// toTodoResponse converts a todo model into its response DTO.
+// With ?render=html the Markdown description is also rendered to sanitized HTML.
+func toTodoResponse(c *gin.Context, todo *model.Todo) dto.TodoResponse {
+	response := service.ToTodoResponse(todo)
+
+	if c.Query("render") == "html" {
+		html, err := utils.RenderMarkdown(todo.Description)
+		if err != nil {
+			// Response tetap dikirim tanpa description_html
+			slog.ErrorContext(c.Request.Context(), "todo: failed to render description", "todo_id", todo.ID, "error", err)
+		} else {
+			response.DescriptionHTML = html
+		}
+	}
+
+	return response
+}
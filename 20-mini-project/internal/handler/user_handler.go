@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
@@ -38,30 +39,25 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 	// Parse and validate request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid input",
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Invalid input", err)
 		return
 	}
 
 	// Call service
-	user, err := h.authService.Register(req)
+	user, err := h.authService.Register(c.Request.Context(), req)
 	if err != nil {
 		// Map service errors to HTTP status codes
 		statusCode := http.StatusInternalServerError
 		message := "Failed to register user"
+		var publicErr error // Error internal tidak dikirim ke client
 
 		if errors.Is(err, service.ErrUserExists) {
 			statusCode = http.StatusBadRequest
 			message = err.Error()
+			publicErr = err
 		}
 
-		c.JSON(statusCode, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-		})
+		apierror.Respond(c, statusCode, message, publicErr)
 		return
 	}
 
@@ -90,29 +86,24 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	// Parse and validate request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid input",
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Invalid input", err)
 		return
 	}
 
 	// Call service
-	authResp, err := h.authService.Login(req)
+	authResp, err := h.authService.Login(c.Request.Context(), req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		message := "Failed to login"
+		var publicErr error // Error internal tidak dikirim ke client
 
 		if errors.Is(err, service.ErrInvalidCredentials) {
 			statusCode = http.StatusUnauthorized
 			message = err.Error()
+			publicErr = err
 		}
 
-		c.JSON(statusCode, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-		})
+		apierror.Respond(c, statusCode, message, publicErr)
 		return
 	}
 
@@ -141,20 +132,19 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 
 	// Call service
-	user, err := h.authService.GetProfile(userID)
+	user, err := h.authService.GetProfile(c.Request.Context(), userID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		message := "Failed to get profile"
+		var publicErr error // Error internal tidak dikirim ke client
 
 		if errors.Is(err, service.ErrUserNotFound) {
 			statusCode = http.StatusNotFound
 			message = err.Error()
+			publicErr = err
 		}
 
-		c.JSON(statusCode, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-		})
+		apierror.Respond(c, statusCode, message, publicErr)
 		return
 	}
 
@@ -188,32 +178,28 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 	// Parse and validate request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Message: "Invalid input",
-			Error:   err.Error(),
-		})
+		apierror.Respond(c, http.StatusBadRequest, "Invalid input", err)
 		return
 	}
 
 	// Call service
-	user, err := h.authService.UpdateProfile(userID, req)
+	user, err := h.authService.UpdateProfile(c.Request.Context(), userID, req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		message := "Failed to update profile"
+		var publicErr error // Error internal tidak dikirim ke client
 
 		if errors.Is(err, service.ErrUserNotFound) {
 			statusCode = http.StatusNotFound
 			message = err.Error()
+			publicErr = err
 		} else if errors.Is(err, service.ErrUserExists) {
 			statusCode = http.StatusBadRequest
 			message = err.Error()
+			publicErr = err
 		}
 
-		c.JSON(statusCode, dto.ErrorResponse{
-			Success: false,
-			Message: message,
-		})
+		apierror.Respond(c, statusCode, message, publicErr)
 		return
 	}
 
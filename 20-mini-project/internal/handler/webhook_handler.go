@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles webhook HTTP requests
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler instance
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// Create handles POST /api/v1/webhooks
+// @Summary Register a webhook
+// @Description Register an endpoint that receives HMAC-signed todo events. The secret is only returned once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body dto.CreateWebhookRequest true "Webhook data"
+// @Success 201 {object} dto.SuccessResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks [post]
+// @Security BearerAuth
+func (h *WebhookHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), userID.(uint), req)
+	if err != nil {
+		statusCode, message := webhookErrorStatus(err, "Failed to create webhook")
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	response := toWebhookResponse(webhook)
+	response.Secret = webhook.Secret
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Message: "Webhook created successfully",
+		Data:    response,
+	})
+}
+
+// GetAll handles GET /api/v1/webhooks
+// @Summary Get all webhooks
+// @Description Retrieve all webhooks registered by the authenticated user
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.WebhookResponse}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks [get]
+// @Security BearerAuth
+func (h *WebhookHandler) GetAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	webhooks, err := h.webhookService.GetUserWebhooks(c.Request.Context(), userID.(uint))
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "Failed to retrieve webhooks", err)
+		return
+	}
+
+	responses := make([]dto.WebhookResponse, len(webhooks))
+	for i := range webhooks {
+		responses[i] = toWebhookResponse(&webhooks[i])
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Webhooks retrieved successfully",
+		Data:    responses,
+	})
+}
+
+// Update handles PUT /api/v1/webhooks/:id
+// @Summary Update a webhook
+// @Description Update URL, subscribed events or active flag of a webhook
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param webhook body dto.UpdateWebhookRequest true "Webhook data to update"
+// @Success 200 {object} dto.SuccessResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id} [put]
+// @Security BearerAuth
+func (h *WebhookHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid webhook ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), uint(webhookID), userID.(uint), req)
+	if err != nil {
+		statusCode, message := webhookErrorStatus(err, "Failed to update webhook")
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Webhook updated successfully",
+		Data:    toWebhookResponse(webhook),
+	})
+}
+
+// Delete handles DELETE /api/v1/webhooks/:id
+// @Summary Delete a webhook
+// @Description Delete a webhook of the authenticated user
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id} [delete]
+// @Security BearerAuth
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid webhook ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), uint(webhookID), userID.(uint)); err != nil {
+		statusCode, message := webhookErrorStatus(err, "Failed to delete webhook")
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Webhook deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GetDeliveries handles GET /api/v1/webhooks/:id/deliveries
+// @Summary Get webhook delivery logs
+// @Description Retrieve the latest delivery attempts of a webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.WebhookDeliveryResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+// @Security BearerAuth
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid webhook ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(c.Request.Context(), uint(webhookID), userID.(uint))
+	if err != nil {
+		statusCode, message := webhookErrorStatus(err, "Failed to retrieve deliveries")
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	responses := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		responses[i] = toWebhookDeliveryResponse(&deliveries[i])
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Deliveries retrieved successfully",
+		Data:    responses,
+	})
+}
+
+// Redeliver handles POST /api/v1/webhooks/:id/deliveries/:deliveryId/redeliver
+// @Summary Redeliver a webhook event
+// @Description Queue a new delivery with the same payload as an earlier delivery
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param deliveryId path int true "Delivery ID"
+// @Success 202 {object} dto.SuccessResponse{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+// @Security BearerAuth
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid webhook ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "Invalid delivery ID", apierror.Wrap(apierror.CodeInvalidID, err))
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), uint(webhookID), uint(deliveryID), userID.(uint))
+	if err != nil {
+		statusCode, message := webhookErrorStatus(err, "Failed to redeliver event")
+		apierror.Respond(c, statusCode, message, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse{
+		Success: true,
+		Message: "Redelivery queued successfully",
+		Data:    toWebhookDeliveryResponse(delivery),
+	})
+}
+
+// webhookErrorStatus maps webhook service errors to HTTP status codes
+func webhookErrorStatus(err error, fallback string) (int, string) {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound):
+		return http.StatusNotFound, "Webhook not found"
+	case errors.Is(err, service.ErrDeliveryNotFound):
+		return http.StatusNotFound, "Delivery not found"
+	case errors.Is(err, service.ErrUnauthorizedWebhookAccess):
+		return http.StatusForbidden, "You don't have permission to access this webhook"
+	case errors.Is(err, service.ErrInvalidWebhookURL):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusInternalServerError, fallback
+	}
+}
+
+// toWebhookResponse converts a webhook model into its response DTO (without secret)
+func toWebhookResponse(webhook *model.Webhook) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    strings.Split(webhook.Events, ","),
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+// toWebhookDeliveryResponse converts a delivery model into its response DTO
+func toWebhookDeliveryResponse(delivery *model.WebhookDelivery) dto.WebhookDeliveryResponse {
+	return dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
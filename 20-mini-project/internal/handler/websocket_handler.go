@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/realtime"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketHandler handles the real-time collaboration channel
+type WebSocketHandler struct {
+	hub            *realtime.Hub
+	shareService   *service.BoardShareService
+	allowedOrigins func() []string
+	upgrader       websocket.Upgrader
+}
+
+// NewWebSocketHandler creates a new websocket handler instance. allowedOrigins
+// returns the browser origins allowed to connect (CORS_ALLOWED_ORIGINS).
+func NewWebSocketHandler(hub *realtime.Hub, shareService *service.BoardShareService, allowedOrigins func() []string) *WebSocketHandler {
+	h := &WebSocketHandler{
+		hub:            hub,
+		shareService:   shareService,
+		allowedOrigins: allowedOrigins,
+	}
+	h.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     h.checkOrigin,
+	}
+	return h
+}
+
+// checkOrigin menolak halaman dari origin lain yang membuka WebSocket atas
+// nama user (cross-site WebSocket hijacking). Browser selalu mengirim Origin;
+// client non-browser tanpa Origin tetap diizinkan.
+func (h *WebSocketHandler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	origins := h.allowedOrigins()
+	return slices.Contains(origins, "*") || slices.Contains(origins, origin)
+}
+
+// Revoke re-checks the rooms a user follows after their access to a board
+// was removed; matches service.BoardShareService.OnRevoke
+func (h *WebSocketHandler) Revoke(ownerID, userID uint) {
+	h.hub.Reauthorize(userID)
+}
+
+// Connect handles GET /api/v1/ws
+// @Summary Real-time collaboration channel
+// @Description Upgrade to a WebSocket. Send {"type":"subscribe","room":"todo:12"} or "board:<userID>" to receive
+// @Description todo change broadcasts, and {"type":"presence","room":"todo:12","state":"editing"} to share presence
+// @Description (state: viewing, editing or idle). Rooms of boards shared with the user (see /board/shares) may be joined too.
+// @Description Browsers may pass the JWT as ?access_token= instead of the Authorization header.
+// @Tags realtime
+// @Param access_token query string false "JWT token (alternative to Authorization header)"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/v1/ws [get]
+// @Security BearerAuth
+func (h *WebSocketHandler) Connect(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Respond(c, http.StatusUnauthorized, "Unauthorized", errMissingUserID)
+		return
+	}
+	username, _ := c.Get("username")
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader sudah menulis response error ke client
+		return
+	}
+
+	name, _ := username.(string)
+	ctx := c.Request.Context() // Tetap hidup selama Run memblokir handler
+	authorize := func(userID uint, room string) error {
+		return h.authorizeRoom(ctx, userID, room)
+	}
+	client := realtime.NewClient(h.hub, conn, userID.(uint), name, authorize)
+	client.Run(ctx)
+}
+
+// authorizeRoom allows a user to join the board room and todo rooms of
+// their own board or of a board shared with them
+func (h *WebSocketHandler) authorizeRoom(ctx context.Context, userID uint, room string) error {
+	kind, rawID, found := strings.Cut(room, ":")
+	if !found {
+		return errors.New("invalid room, use todo:<id> or board:<user_id>")
+	}
+
+	id, err := strconv.ParseUint(rawID, 10, 32)
+	if err != nil {
+		return errors.New("invalid room id")
+	}
+
+	// Clients usually join right after creating the todo or sharing the board,
+	// before a replica may have caught up, so the access check reads from the primary
+	ctx = database.WithPrimary(ctx)
+
+	switch kind {
+	case "board":
+		allowed, err := h.shareService.CanView(ctx, uint(id), userID)
+		if err != nil {
+			return errors.New("failed to check board access")
+		}
+		if !allowed {
+			return errors.New("you don't have permission to access this board")
+		}
+		return nil
+	case "todo":
+		allowed, err := h.shareService.CanViewTodo(ctx, uint(id), userID)
+		if err != nil {
+			return errors.New("failed to check todo access")
+		}
+		if !allowed {
+			return errors.New("todo not found")
+		}
+		return nil
+	default:
+		return errors.New("invalid room, use todo:<id> or board:<user_id>")
+	}
+}
@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
+	"gorm.io/gorm"
+)
+
+// DatabaseChecker memastikan koneksi database bisa di-ping
+func DatabaseChecker(db *gorm.DB) Checker {
+	return databaseChecker{db: db}
+}
+
+type databaseChecker struct {
+	db *gorm.DB
+}
+
+func (c databaseChecker) Name() string { return "database" }
+
+func (c databaseChecker) Check(ctx context.Context) (Details, error) {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	stats := sqlDB.Stats()
+	return Details{
+		"open_connections": stats.OpenConnections,
+		"in_use":           stats.InUse,
+		"idle":             stats.Idle,
+	}, nil
+}
+
+// MigrationChecker memastikan schema sudah dimigrasi: tidak ada migrasi yang
+// tertunda dan tidak ada file migrasi yang diubah setelah diterapkan.
+// Details berisi versi schema saat ini dan jumlah migrasi tertunda.
+func MigrationChecker(migrator *migrate.Migrator) Checker {
+	return migrationChecker{migrator: migrator}
+}
+
+type migrationChecker struct {
+	migrator *migrate.Migrator
+}
+
+func (c migrationChecker) Name() string { return "migrations" }
+
+func (c migrationChecker) Check(ctx context.Context) (Details, error) {
+	pending, version, err := c.migrator.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := Details{"version": version, "pending": pending}
+	if pending > 0 {
+		return details, fmt.Errorf("%d pending migration(s), run: migrate up", pending)
+	}
+	return details, nil
+}
+
+// ReplicaChecker melaporkan status read replica dari health check terakhir
+// database.Cluster. Replica yang tidak sehat tidak membuat instance not ready
+// karena query baca otomatis kembali ke primary.
+func ReplicaChecker(cluster *database.Cluster) Checker {
+	return replicaChecker{cluster: cluster}
+}
+
+type replicaChecker struct {
+	cluster *database.Cluster
+}
+
+func (c replicaChecker) Name() string { return "replicas" }
+
+func (c replicaChecker) Check(ctx context.Context) (Details, error) {
+	statuses := c.cluster.Replicas()
+	healthy := 0
+	replicas := make(map[string]any, len(statuses))
+	for _, status := range statuses {
+		replica := map[string]any{"healthy": status.Healthy, "lag_ms": status.Lag.Milliseconds()}
+		if status.Error != "" {
+			replica["error"] = status.Error
+		}
+		if status.Healthy {
+			healthy++
+		}
+		replicas[status.Name] = replica
+	}
+	return Details{"healthy": healthy, "total": len(statuses), "replicas": replicas}, nil
+}
@@ -0,0 +1,199 @@
+// Package health menyediakan liveness dan readiness check. Subsystem lain
+// mendaftarkan Checker sendiri sehingga readiness mencakup semua dependency.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status komponen maupun keseluruhan
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Checker adalah satu dependency yang harus sehat agar instance siap menerima traffic
+type Checker interface {
+	// Name dipakai sebagai key komponen di response readiness
+	Name() string
+	// Check mengembalikan error jika dependency tidak sehat. Details opsional
+	// (contoh versi schema) ikut ditampilkan di response.
+	Check(ctx context.Context) (Details, error)
+}
+
+// Details informasi tambahan sebuah komponen
+type Details map[string]any
+
+// CheckFunc mengadaptasi fungsi biasa menjadi Checker
+func CheckFunc(name string, fn func(ctx context.Context) error) Checker {
+	return checkFunc{name: name, fn: fn}
+}
+
+type checkFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (c checkFunc) Name() string { return c.name }
+
+func (c checkFunc) Check(ctx context.Context) (Details, error) {
+	return nil, c.fn(ctx)
+}
+
+// Component hasil check satu dependency
+type Component struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	Details   Details `json:"details,omitempty"`
+}
+
+// Report hasil readiness check
+type Report struct {
+	Status        string               `json:"status"`
+	Version       string               `json:"version"`
+	UptimeSeconds int64                `json:"uptime_seconds"`
+	Components    map[string]Component `json:"components"`
+}
+
+// Summary report tanpa latency, pesan error dan details komponen, untuk client
+// tanpa token monitoring. Probe hanya butuh status; detail seperti statistik
+// pool dan host replica tidak untuk publik.
+func (r Report) Summary() Report {
+	summary := r
+	summary.Components = make(map[string]Component, len(r.Components))
+	for name, component := range r.Components {
+		summary.Components[name] = Component{Status: component.Status}
+	}
+	return summary
+}
+
+// Health menyimpan checker terdaftar dan info build
+type Health struct {
+	version   string
+	timeout   time.Duration
+	startedAt time.Time
+
+	mu           sync.RWMutex
+	checkers     []Checker
+	shuttingDown bool
+}
+
+// New membuat Health. timeout membatasi setiap check agar readiness tidak
+// menggantung ketika sebuah dependency lambat.
+func New(version string, timeout time.Duration) *Health {
+	return &Health{
+		version:   version,
+		timeout:   timeout,
+		startedAt: time.Now(),
+	}
+}
+
+// Register menambahkan checker ke readiness
+func (h *Health) Register(checkers ...Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers = append(h.checkers, checkers...)
+}
+
+// SetShuttingDown membuat readiness gagal sehingga load balancer berhenti
+// mengirim request baru sebelum server mulai drain
+func (h *Health) SetShuttingDown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.shuttingDown = true
+}
+
+// Version versi build yang sedang berjalan
+func (h *Health) Version() string {
+	return h.version
+}
+
+// Uptime lama proses sudah berjalan
+func (h *Health) Uptime() time.Duration {
+	return time.Since(h.startedAt)
+}
+
+// Ready menjalankan semua checker secara paralel. Status keseluruhan up hanya
+// jika semua komponen up.
+func (h *Health) Ready(ctx context.Context) Report {
+	h.mu.RLock()
+	checkers := append([]Checker(nil), h.checkers...)
+	shuttingDown := h.shuttingDown
+	h.mu.RUnlock()
+
+	if shuttingDown {
+		return Report{
+			Status:        StatusDown,
+			Version:       h.version,
+			UptimeSeconds: int64(h.Uptime().Seconds()),
+			Components: map[string]Component{
+				"server": {Status: StatusDown, Error: "server is shutting down"},
+			},
+		}
+	}
+
+	components := make([]Component, len(checkers))
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			components[i] = h.run(ctx, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:        StatusUp,
+		Version:       h.version,
+		UptimeSeconds: int64(h.Uptime().Seconds()),
+		Components:    make(map[string]Component, len(checkers)),
+	}
+	for i, checker := range checkers {
+		if components[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+		report.Components[checker.Name()] = components[i]
+	}
+	return report
+}
+
+// run menjalankan satu checker dengan timeout. Checker yang mengabaikan ctx
+// tetap dianggap down setelah timeout agar readiness tidak ikut menggantung.
+func (h *Health) run(ctx context.Context, checker Checker) Component {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	type result struct {
+		details Details
+		err     error
+	}
+	done := make(chan result, 1)
+
+	start := time.Now()
+	go func() {
+		details, err := checker.Check(ctx)
+		done <- result{details: details, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res.err = ctx.Err()
+	}
+
+	component := Component{
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   res.details,
+	}
+	if res.err != nil {
+		component.Status = StatusDown
+		component.Error = res.err.Error()
+	}
+	return component
+}
@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// detailedChecker checker dengan details, untuk memastikan details ikut di report
+type detailedChecker struct{}
+
+func (detailedChecker) Name() string { return "schema" }
+
+func (detailedChecker) Check(ctx context.Context) (Details, error) {
+	return Details{"version": 3}, nil
+}
+
+func TestReadyAllUp(t *testing.T) {
+	h := New("v1.2.3", time.Second)
+	h.Register(
+		CheckFunc("database", func(ctx context.Context) error { return nil }),
+		detailedChecker{},
+	)
+
+	report := h.Ready(context.Background())
+	if report.Status != StatusUp {
+		t.Fatalf("status = %s, want %s", report.Status, StatusUp)
+	}
+	if report.Version != "v1.2.3" {
+		t.Errorf("version = %s, want v1.2.3", report.Version)
+	}
+	if len(report.Components) != 2 {
+		t.Fatalf("components = %v, want database and schema", report.Components)
+	}
+	if got := report.Components["schema"].Details["version"]; got != 3 {
+		t.Errorf("schema details version = %v, want 3", got)
+	}
+}
+
+func TestReadyDownWhenAnyComponentFails(t *testing.T) {
+	h := New("dev", time.Second)
+	h.Register(
+		CheckFunc("database", func(ctx context.Context) error { return nil }),
+		CheckFunc("cache", func(ctx context.Context) error { return errors.New("connection refused") }),
+	)
+
+	report := h.Ready(context.Background())
+	if report.Status != StatusDown {
+		t.Fatalf("status = %s, want %s", report.Status, StatusDown)
+	}
+	if got := report.Components["database"].Status; got != StatusUp {
+		t.Errorf("database status = %s, want %s", got, StatusUp)
+	}
+	cache := report.Components["cache"]
+	if cache.Status != StatusDown || cache.Error != "connection refused" {
+		t.Errorf("cache = %+v, want down with the check error", cache)
+	}
+}
+
+// Checker yang mengabaikan ctx tetap dianggap down setelah timeout, dan
+// checker dijalankan paralel sehingga readiness tidak menunggu jumlah semuanya
+func TestReadyTimesOutSlowCheckers(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	h := New("dev", 100*time.Millisecond)
+	for _, name := range []string{"slow-1", "slow-2", "slow-3"} {
+		h.Register(CheckFunc(name, func(ctx context.Context) error {
+			<-release
+			return nil
+		}))
+	}
+
+	start := time.Now()
+	report := h.Ready(context.Background())
+	elapsed := time.Since(start)
+
+	if report.Status != StatusDown {
+		t.Fatalf("status = %s, want %s", report.Status, StatusDown)
+	}
+	for name, component := range report.Components {
+		if component.Status != StatusDown || component.Error != context.DeadlineExceeded.Error() {
+			t.Errorf("%s = %+v, want down with %v", name, component, context.DeadlineExceeded)
+		}
+	}
+	if elapsed > 250*time.Millisecond {
+		t.Errorf("Ready took %s, checks should run in parallel with a 100ms timeout", elapsed)
+	}
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger meneruskan log GORM ke slog sehingga query SQL memakai format
+// dan request_id yang sama dengan access log HTTP
+type GormLogger struct {
+	logger        *slog.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+}
+
+// NewGormLogger creates a GORM logger. level is one of silent, error, warn,
+// info (info logs every query); queries slower than slowThreshold are logged as warnings.
+func NewGormLogger(logger *slog.Logger, level string, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{
+		logger:        logger,
+		level:         ParseGormLevel(level),
+		slowThreshold: slowThreshold,
+	}
+}
+
+// ParseGormLevel converts a level name to a GORM log level, defaulting to warn
+func ParseGormLevel(level string) gormlogger.LogLevel {
+	switch strings.ToLower(level) {
+	case "silent":
+		return gormlogger.Silent
+	case "error":
+		return gormlogger.Error
+	case "info":
+		return gormlogger.Info
+	default:
+		return gormlogger.Warn
+	}
+}
+
+// LogMode implements gormlogger.Interface
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+// Info implements gormlogger.Interface
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, args...), slog.String("component", "gorm"))
+	}
+}
+
+// Warn implements gormlogger.Interface
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, args...), slog.String("component", "gorm"))
+	}
+}
+
+// Error implements gormlogger.Interface
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, args...), slog.String("component", "gorm"))
+	}
+}
+
+// Trace implements gormlogger.Interface, dipanggil GORM setelah setiap query
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	attrs := func() []slog.Attr {
+		sql, rows := fc()
+		return []slog.Attr{
+			slog.String("component", "gorm"),
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+		}
+	}
+
+	switch {
+	// Record not found adalah hasil normal (repository mengembalikan nil), bukan error
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		l.logger.LogAttrs(ctx, slog.LevelError, "sql query failed", append(attrs(), slog.String("error", err.Error()))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		l.logger.LogAttrs(ctx, slog.LevelWarn, "slow sql query", attrs()...)
+	case l.level >= gormlogger.Info:
+		l.logger.LogAttrs(ctx, slog.LevelInfo, "sql query", attrs()...)
+	}
+}
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// records mem-parse output JSON handler, satu record per baris
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		record := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", line, err)
+		}
+		out = append(out, record)
+	}
+	return out
+}
+
+func TestParseLevel(t *testing.T) {
+	for level, want := range map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+		"bogus":   slog.LevelInfo,
+	} {
+		if got := ParseLevel(level); got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", level, got, want)
+		}
+	}
+}
+
+func TestLoggerAddsRequestIDFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "json", slog.LevelInfo)
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-1"), "with id")
+	logger.InfoContext(context.Background(), "without id")
+	logger.DebugContext(context.Background(), "below level")
+	logger.With("component", "test").WarnContext(WithRequestID(context.Background(), "req-2"), "derived logger")
+
+	got := records(t, &buf)
+	if len(got) != 3 {
+		t.Fatalf("got %d records, want 3:\n%s", len(got), buf.String())
+	}
+	if got[0]["request_id"] != "req-1" {
+		t.Errorf("request_id = %v, want req-1", got[0]["request_id"])
+	}
+	if _, ok := got[1]["request_id"]; ok {
+		t.Errorf("record without request ID in context has request_id %v", got[1]["request_id"])
+	}
+	if got[2]["request_id"] != "req-2" || got[2]["component"] != "test" {
+		t.Errorf("derived logger record = %v, want request_id req-2 and component test", got[2])
+	}
+}
+
+func TestGormLoggerTrace(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithRequestID(context.Background(), "req-sql")
+	query := func() (string, int64) { return "SELECT * FROM todos", 3 }
+
+	warn := NewGormLogger(New(&buf, "json", slog.LevelDebug), "warn", 100*time.Millisecond)
+	warn.Trace(ctx, time.Now(), query, nil)                        // Cepat, di bawah level
+	warn.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)     // Bukan error
+	warn.Trace(ctx, time.Now(), query, errors.New("syntax error")) // Error
+	warn.Trace(ctx, time.Now().Add(-time.Second), query, nil)      // Lambat
+
+	got := records(t, &buf)
+	if len(got) != 2 {
+		t.Fatalf("got %d records, want 2:\n%s", len(got), buf.String())
+	}
+	failed := got[0]
+	for key, want := range map[string]any{
+		"msg":        "sql query failed",
+		"level":      "ERROR",
+		"error":      "syntax error",
+		"sql":        "SELECT * FROM todos",
+		"rows":       float64(3),
+		"request_id": "req-sql",
+		"component":  "gorm",
+	} {
+		if failed[key] != want {
+			t.Errorf("failed query %s = %v, want %v", key, failed[key], want)
+		}
+	}
+	if got[1]["msg"] != "slow sql query" || got[1]["level"] != "WARN" {
+		t.Errorf("slow query record = %v", got[1])
+	}
+
+	// Level info mencatat setiap query, silent tidak mencatat apa pun
+	buf.Reset()
+	NewGormLogger(New(&buf, "json", slog.LevelDebug), "info", 0).Trace(ctx, time.Now(), query, nil)
+	NewGormLogger(New(&buf, "json", slog.LevelDebug), "silent", 0).Trace(ctx, time.Now(), query, errors.New("ignored"))
+	got = records(t, &buf)
+	if len(got) != 1 || got[0]["msg"] != "sql query" {
+		t.Errorf("got %v, want a single sql query record", got)
+	}
+}
+
+// Level dari LevelVar bisa diubah saat runtime (dipakai config reload)
+func TestLoggerLevelCanChangeAtRuntime(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	logger := New(&buf, "json", level)
+
+	logger.Debug("hidden")
+	level.Set(slog.LevelDebug)
+	logger.Debug("visible")
+
+	got := records(t, &buf)
+	if len(got) != 1 || got[0]["msg"] != "visible" {
+		t.Errorf("got %v, want only the record logged after the level change", got)
+	}
+}
@@ -0,0 +1,119 @@
+// Package metrics mendefinisikan metric Prometheus aplikasi dan handler /metrics
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefix semua metric aplikasi
+const namespace = "todolist"
+
+// Hasil login untuk label result
+const (
+	LoginSucceeded = "succeeded"
+	LoginFailed    = "failed"
+)
+
+// Tujuan query read-only untuk label target
+const (
+	DBTargetPrimary = "primary"
+	DBTargetReplica = "replica"
+)
+
+// Registry menampung semua metric yang diekspos di /metrics. Registry sendiri
+// (bukan prometheus.DefaultRegisterer) agar isi /metrics hanya milik aplikasi ini.
+var Registry = prometheus.NewRegistry()
+
+// HTTP metrics, diisi oleh middleware.Metrics
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+)
+
+// Domain metrics, diisi oleh service layer
+var (
+	TodosCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "todos_created_total",
+		Help:      "Total todos created, including todos instantiated from templates.",
+	})
+
+	TodosCompleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "todos_completed_total",
+		Help:      "Total todos that moved into the completed status.",
+	})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Total login attempts by result (succeeded, failed).",
+	}, []string{"result"})
+)
+
+// Database metrics, diisi oleh database.Cluster
+var (
+	DBReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_reads_total",
+		Help:      "Read-only repository queries by target (primary, replica).",
+	}, []string{"target"})
+
+	DBReplicaHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "db_replica_healthy",
+		Help:      "Whether a read replica passed its last health check (1) or not (0).",
+	}, []string{"replica"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		TodosCreated,
+		TodosCompleted,
+		Logins,
+		DBReads,
+		DBReplicaHealthy,
+	)
+
+	// Inisialisasi label agar series muncul dengan nilai 0 sebelum ada login
+	Logins.WithLabelValues(LoginSucceeded)
+	Logins.WithLabelValues(LoginFailed)
+	DBReads.WithLabelValues(DBTargetPrimary)
+	DBReads.WithLabelValues(DBTargetReplica)
+}
+
+// RegisterDB mengekspos statistik connection pool (sql.DBStats) sebagai go_sql_* gauge
+func RegisterDB(db *sql.DB, dbName string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler melayani metric dalam format teks Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -1,10 +1,11 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
-	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -15,22 +16,14 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Ambil token dari header Authorization
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Success: false,
-				Message: "Token tidak ditemukan",
-			})
-			c.Abort()
+			apierror.Abort(c, http.StatusUnauthorized, "Token tidak ditemukan", apierror.ErrTokenMissing)
 			return
 		}
 
 		// Format: Bearer <token>
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Success: false,
-				Message: "Format token tidak valid",
-			})
-			c.Abort()
+			apierror.Abort(c, http.StatusUnauthorized, "Format token tidak valid", apierror.ErrTokenMalformed)
 			return
 		}
 
@@ -39,12 +32,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Validasi token
 		claims, err := utils.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Success: false,
-				Message: "Token tidak valid atau expired",
-				Error:   err.Error(),
-			})
-			c.Abort()
+			apierror.Abort(c, http.StatusUnauthorized, "Token tidak valid atau expired", apierror.Wrap(apierror.CodeTokenInvalid, err))
 			return
 		}
 
@@ -58,7 +46,7 @@ func AuthMiddleware() gin.HandlerFunc {
 
 // GetUserID mengambil user ID dari context
 func GetUserID(c *gin.Context) uint {
-	userID, exists := c.Get("user_id")
+	userID, exists := c.Get("userID")
 	if !exists {
 		return 0
 	}
@@ -73,3 +61,45 @@ func GetUsername(c *gin.Context) string {
 	}
 	return username.(string)
 }
+
+// WebSocketAuthMiddleware sama seperti AuthMiddleware, tetapi juga menerima
+// token dari query ?access_token=... karena browser tidak bisa mengirim
+// header Authorization saat membuka koneksi WebSocket
+func WebSocketAuthMiddleware() gin.HandlerFunc {
+	auth := AuthMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		auth(c)
+	}
+}
+
+// MonitoringAuth membatasi endpoint operasional seperti /metrics untuk pemegang
+// MONITORING_TOKEN (header Authorization: Bearer <token>). Tanpa token yang
+// dikonfigurasi endpoint ditutup, karena isinya (statistik pool, host replica)
+// tidak untuk publik.
+func MonitoringAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			apierror.Abort(c, http.StatusForbidden, "Endpoint monitoring tidak aktif", apierror.ErrMonitoringDisabled)
+			return
+		}
+		if !HasMonitoringToken(c, token) {
+			apierror.Abort(c, http.StatusUnauthorized, "Token monitoring tidak valid", apierror.ErrMonitoringToken)
+			return
+		}
+		c.Next()
+	}
+}
+
+// HasMonitoringToken melaporkan apakah request membawa MONITORING_TOKEN yang benar
+func HasMonitoringToken(c *gin.Context, token string) bool {
+	if token == "" {
+		return false
+	}
+	expected := "Bearer " + token
+	return subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte(expected)) == 1
+}
@@ -1,7 +1,10 @@
 package middleware
 
 import (
-	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"net/http"
+	"slices"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,22 +16,39 @@ func ErrorHandler() gin.HandlerFunc {
 		// Cek jika ada error
 		if len(c.Errors) > 0 {
 			err := c.Errors.Last()
-			c.JSON(500, dto.ErrorResponse{
-				Success: false,
-				Message: "Internal server error",
-				Error:   err.Error(),
-			})
+			apierror.Respond(c, http.StatusInternalServerError, "Internal server error", err)
 		}
 	}
 }
 
-// CORSMiddleware menangani CORS
-func CORSMiddleware() gin.HandlerFunc {
+// APIVersion menandai versi API yang melayani request, dipakai untuk
+// memilih format error (v1: ErrorResponse, v2: problem details)
+func APIVersion(version int) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		c.Set(apierror.VersionKey, version)
+		c.Next()
+	}
+}
+
+// CORSMiddleware menangani CORS. allowedOrigins mengembalikan origin yang
+// boleh memanggil API dari browser ("*" untuk semua origin) dan dibaca di
+// setiap request agar perubahan dari config reload langsung berlaku.
+func CORSMiddleware(allowedOrigins func() []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origins := allowedOrigins()
+		if slices.Contains(origins, "*") {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			// Response berbeda per origin, cache harus membedakannya
+			c.Writer.Header().Add("Vary", "Origin")
+			if origin := c.GetHeader("Origin"); slices.Contains(origins, origin) {
+				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+		}
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Idempotency-Key, X-Request-ID, traceparent, tracestate")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "RateLimit-Policy, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After, Idempotent-Replayed, X-Request-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader header yang dikirim client untuk request mutasi
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader menandai response hasil replay
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength panjang maksimal Idempotency-Key
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency menyimpan response request mutasi (POST, PUT, PATCH, DELETE)
+// yang membawa header Idempotency-Key dan me-replay response tersebut saat
+// client mengirim ulang request yang sama. Harus dipasang setelah AuthMiddleware
+// karena key di-scope per user.
+func Idempotency(idempotencyService *service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			apierror.Abort(c, http.StatusBadRequest, "Idempotency-Key maksimal 255 karakter", apierror.ErrIdempotencyKeyInvalid)
+			return
+		}
+
+		// Baca body untuk fingerprint lalu kembalikan agar bisa dibaca handler
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apierror.Abort(c, http.StatusBadRequest, "Failed to read request body", nil)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		path := c.Request.URL.RequestURI()
+		record, err := idempotencyService.Begin(c.Request.Context(), c.GetUint("userID"), key, c.Request.Method, path, requestFingerprint(c.Request.Method, path, body))
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			message := "Failed to process idempotency key"
+			var publicErr error // Error internal tidak dikirim ke client
+
+			if errors.Is(err, service.ErrIdempotencyKeyReused) {
+				statusCode = http.StatusUnprocessableEntity
+				message = err.Error()
+				publicErr = err
+			} else if errors.Is(err, service.ErrIdempotencyInProgress) {
+				statusCode = http.StatusConflict
+				message = err.Error()
+				publicErr = err
+			}
+
+			apierror.Abort(c, statusCode, message, publicErr)
+			return
+		}
+
+		// Request yang sama sudah pernah selesai: kirim ulang response tersimpan
+		if record.Completed() {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(record.StatusCode, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		if err := idempotencyService.Complete(c.Request.Context(), record, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			slog.ErrorContext(c.Request.Context(), "idempotency: failed to complete key", "key", key, "error", err)
+		}
+	}
+}
+
+// isMutatingMethod menentukan method yang diproses middleware Idempotency
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// requestFingerprint menghasilkan hash dari method, path dan body request
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder menyalin body response sambil tetap menulis ke client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -1,13 +1,14 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// LoggerMiddleware mencatat informasi request
+// LoggerMiddleware mencatat informasi request sebagai structured log.
+// request_id ditambahkan otomatis dari context (lihat RequestID).
 func LoggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Start timer
@@ -18,14 +19,34 @@ func LoggerMiddleware() gin.HandlerFunc {
 
 		// Hitung waktu eksekusi
 		duration := time.Since(startTime)
+		status := c.Writer.Status()
 
-		// Log informasi request
-		log.Printf("[%s] %s %s - Status: %d - Duration: %v",
-			c.Request.Method,
-			c.Request.URL.Path,
-			c.ClientIP(),
-			c.Writer.Status(),
-			duration,
-		)
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()), // Template route, contoh /api/v1/todos/:id
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(duration.Microseconds())/1000),
+			slog.Int("response_size", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		}
+		if userID := GetUserID(c); userID != 0 {
+			attrs = append(attrs, slog.Uint64("user_id", uint64(userID)))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+
+		// Level mengikuti status: 5xx error, 4xx warning
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		slog.LogAttrs(c.Request.Context(), level, "http request", attrs...)
 	}
 }
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute label route untuk request yang tidak cocok dengan route manapun,
+// agar path acak (scanner, 404) tidak membuat label baru tanpa batas
+const unmatchedRoute = "unmatched"
+
+// Metrics mencatat jumlah dan latency request per route template dan status
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		labels := []string{c.Request.Method, route, strconv.Itoa(c.Writer.Status())}
+
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(startTime).Seconds())
+	}
+}
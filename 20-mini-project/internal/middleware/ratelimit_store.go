@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// MemoryRateLimitStore menyimpan token bucket di memory proses. Cocok untuk
+// satu instance; limit tidak dibagi antar instance dan hilang saat restart.
+type MemoryRateLimitStore struct {
+	mu         sync.Mutex
+	buckets    map[string]*tokenBucket
+	sweepEvery time.Duration
+	lastSweep  time.Time
+}
+
+// tokenBucket state satu key
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+	period   time.Duration // Period limit saat bucket terakhir dipakai
+}
+
+// NewMemoryRateLimitStore creates an in-memory store that looks for idle
+// buckets at most once per sweepEvery. A bucket is evicted once it has been
+// idle for its own limit period, when it would have been full anyway, so
+// periods raised by a config reload are honoured.
+func NewMemoryRateLimitStore(sweepEvery time.Duration) *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:    make(map[string]*tokenBucket),
+		sweepEvery: sweepEvery,
+		lastSweep:  time.Now(),
+	}
+}
+
+// Take implements RateLimitStore
+func (s *MemoryRateLimitStore) Take(key string, limit RateLimit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.evictIdle(now)
+
+	capacity := float64(limit.Requests)
+	perToken := limit.Period / time.Duration(limit.Requests) // Waktu isi ulang satu token
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, lastSeen: now}
+		s.buckets[key] = bucket
+	}
+
+	// Isi ulang token sesuai waktu yang berlalu sejak request terakhir
+	elapsed := now.Sub(bucket.lastSeen)
+	bucket.tokens = math.Min(capacity, bucket.tokens+float64(elapsed)/float64(perToken))
+	bucket.lastSeen = now
+	bucket.period = limit.Period
+
+	result := RateLimitResult{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - bucket.tokens) * float64(perToken))
+	}
+	result.Remaining = int(bucket.tokens)
+	result.ResetAfter = time.Duration((capacity - bucket.tokens) * float64(perToken))
+
+	return result, nil
+}
+
+// evictIdle menghapus bucket yang tidak dipakai selama period-nya sendiri.
+// Dijalankan paling sering sekali per sweepEvery agar Take tetap murah.
+func (s *MemoryRateLimitStore) evictIdle(now time.Time) {
+	if now.Sub(s.lastSweep) < s.sweepEvery {
+		return
+	}
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.lastSeen) >= bucket.period {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
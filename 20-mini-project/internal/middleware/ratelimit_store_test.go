@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Bucket dihapus setelah idle selama period-nya sendiri, bukan period saat store dibuat
+func TestMemoryStoreEvictsByBucketPeriod(t *testing.T) {
+	store := NewMemoryRateLimitStore(0)
+	short := RateLimit{Requests: 1, Period: time.Minute}
+	long := RateLimit{Requests: 1, Period: time.Hour} // Misalnya setelah reload menaikkan period
+
+	for key, limit := range map[string]RateLimit{"short": short, "long": long} {
+		result, err := store.Take(key, limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	// Mundurkan waktu pemakaian terakhir seolah dua menit berlalu
+	for _, bucket := range store.buckets {
+		bucket.lastSeen = bucket.lastSeen.Add(-2 * time.Minute)
+	}
+	store.evictIdle(time.Now())
+
+	assert.NotContains(t, store.buckets, "short")
+	require.Contains(t, store.buckets, "long")
+
+	// Bucket dengan period panjang tetap kosong, request berikutnya ditolak
+	result, err := store.Take("long", long)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+}
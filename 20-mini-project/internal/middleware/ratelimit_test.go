@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitFunc membaca limit di setiap request, sehingga hasil config reload
+// langsung berlaku tanpa membuat ulang router
+func TestLimitFuncFollowsReloadedLimit(t *testing.T) {
+	var current atomic.Pointer[RateLimit]
+	current.Store(&RateLimit{Requests: 1, Period: time.Hour})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	limiter := NewRateLimiter(NewMemoryRateLimitStore(time.Hour))
+	router.GET("/todos", limiter.LimitFunc("api", func() RateLimit { return *current.Load() }), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/todos", nil))
+		return w
+	}
+
+	if w := get(); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "1" {
+		t.Fatalf("first request: status %d, RateLimit-Limit %q", w.Code, w.Header().Get("RateLimit-Limit"))
+	}
+	if w := get(); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: status %d, want 429", w.Code)
+	}
+
+	// Limit dimatikan lewat reload: request lolos tanpa header rate limit
+	current.Store(&RateLimit{Requests: 0, Period: time.Hour})
+	if w := get(); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+		t.Fatalf("disabled limit: status %d, RateLimit-Limit %q", w.Code, w.Header().Get("RateLimit-Limit"))
+	}
+
+	// Limit dinaikkan: policy baru langsung dipakai untuk bucket yang sama
+	current.Store(&RateLimit{Requests: 5, Period: time.Hour})
+	w := get()
+	if got := w.Header().Get("RateLimit-Policy"); got != "5;w=3600" {
+		t.Errorf("RateLimit-Policy = %q, want 5;w=3600", got)
+	}
+}
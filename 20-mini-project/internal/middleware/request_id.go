@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader header untuk korelasi request antar service dan log
+	RequestIDHeader = "X-Request-ID"
+	// maxRequestIDLength panjang maksimal request ID dari client
+	maxRequestIDLength = 128
+)
+
+// RequestID memakai X-Request-ID dari client (misalnya dari load balancer)
+// atau membuat yang baru, lalu mengirimnya kembali di response dan menyimpannya
+// di context request agar ikut tercatat di log HTTP maupun SQL
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set("requestID", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// validRequestID menolak ID kosong, terlalu panjang, atau berisi karakter
+// yang bisa merusak log (spasi, newline, karakter kontrol)
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID membuat ID acak 128-bit dalam hex
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
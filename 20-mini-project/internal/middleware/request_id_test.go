@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// newLoggedRouter memasang logger JSON ke buffer sebagai slog default
+func newLoggedRouter(t *testing.T) (*gin.Engine, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, "json", slog.LevelInfo))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(LoggerMiddleware())
+	router.GET("/api/v1/todos/:id", func(c *gin.Context) {
+		slog.InfoContext(c.Request.Context(), "handler log")
+		c.Status(http.StatusNoContent)
+	})
+	return router, &buf
+}
+
+func TestRequestIDIsEchoedAndLogged(t *testing.T) {
+	router, buf := newLoggedRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/todos/7", nil)
+	req.Header.Set(RequestIDHeader, "lb-abc-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if got := w.Header().Get(RequestIDHeader); got != "lb-abc-123" {
+		t.Fatalf("response %s = %q, want lb-abc-123", RequestIDHeader, got)
+	}
+
+	// Log handler dan access log membawa request_id yang sama
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var records [2]map[string]any
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &records[i]); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", line, err)
+		}
+		if records[i]["request_id"] != "lb-abc-123" {
+			t.Errorf("log line %q has request_id %v", line, records[i]["request_id"])
+		}
+	}
+	access := records[1]
+	if access["msg"] != "http request" || access["route"] != "/api/v1/todos/:id" || access["status"] != float64(http.StatusNoContent) {
+		t.Errorf("access log = %v", access)
+	}
+}
+
+func TestRequestIDRejectsUnsafeValues(t *testing.T) {
+	router, _ := newLoggedRouter(t)
+	generated := regexp.MustCompile("^[0-9a-f]{32}$")
+
+	for _, value := range []string{"", "has space", "line\nbreak", strings.Repeat("x", 129)} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/todos/7", nil)
+		if value != "" {
+			req.Header[RequestIDHeader] = []string{value}
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if got := w.Header().Get(RequestIDHeader); !generated.MatchString(got) {
+			t.Errorf("request ID %q was replaced with %q, want a generated 32 hex ID", value, got)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package migrate
+
+import "sort"
+
+// dialect query migrator yang berbeda antar driver database
+type dialect struct {
+	// lock dan unlock mencegah dua proses bermigrasi bersamaan, kosong jika
+	// driver tidak membutuhkannya
+	lock, unlock string
+	tableExists  string
+	createTable  string
+	insert       string
+	delete       string
+}
+
+var dialects = map[string]dialect{
+	"postgres": {
+		lock:        "SELECT pg_advisory_lock($1)",
+		unlock:      "SELECT pg_advisory_unlock($1)",
+		tableExists: "SELECT to_regclass('schema_migrations') IS NOT NULL",
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)`,
+		insert: "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, $4)",
+		delete: "DELETE FROM schema_migrations WHERE version = $1",
+	},
+	// SQLite dipakai untuk development dan test dalam satu proses; transaksi
+	// tulis SQLite sudah saling mengunci, jadi tidak perlu advisory lock
+	"sqlite": {
+		tableExists: "SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'",
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at DATETIME NOT NULL
+		)`,
+		insert: "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+		delete: "DELETE FROM schema_migrations WHERE version = ?",
+	},
+}
+
+// Drivers daftar driver database yang punya migrasi
+func Drivers() []string {
+	drivers := make([]string, 0, len(dialects))
+	for driver := range dialects {
+		drivers = append(drivers, driver)
+	}
+	sort.Strings(drivers)
+	return drivers
+}
@@ -0,0 +1,283 @@
+// Package migrate menerapkan migrasi SQL berversi ke database.
+//
+// Versi yang sudah diterapkan dicatat di tabel schema_migrations bersama
+// checksum SQL-nya. Setiap migrasi berjalan dalam satu transaksi. Di Postgres,
+// pg_advisory_lock memastikan hanya satu replica yang bermigrasi pada satu
+// waktu; replica lain menunggu lalu mendapati schema sudah terbaru.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// lockID key advisory lock untuk migrasi aplikasi ini
+const lockID int64 = 0x746f646f6c697374 // "todolist"
+
+// ErrChecksumMismatch file migrasi diubah setelah diterapkan ke database
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+// Status kondisi satu versi migrasi
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // nil jika belum diterapkan
+	// Unknown versi tercatat di database tetapi tidak ada di binary ini,
+	// biasanya diterapkan oleh versi aplikasi yang lebih baru
+	Unknown bool
+	// Modified SQL up sudah berubah sejak diterapkan
+	Modified bool
+}
+
+// Migrator menerapkan migrasi ke satu database
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+}
+
+// New membuat Migrator untuk database driver (postgres, sqlite) dengan
+// migrasi yang sudah di-parse (lihat Embedded)
+func New(db *sql.DB, driver string, migrations []Migration) (*Migrator, error) {
+	d, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+	return &Migrator{db: db, dialect: d, migrations: migrations}, nil
+}
+
+// Up menerapkan semua migrasi yang belum diterapkan, urut dari versi terkecil,
+// dan mengembalikan migrasi yang baru diterapkan
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(done); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			start := time.Now()
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, m.dialect.insert,
+					migration.Version, migration.Name, migration.Checksum, time.Now().UTC())
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %03d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			slog.Info("migration applied", "version", migration.Version, "name", migration.Name, "duration_ms", time.Since(start).Milliseconds())
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down me-rollback steps migrasi terakhir yang sudah diterapkan dan
+// mengembalikan migrasi yang di-rollback
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1")
+	}
+
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(done); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if strings.TrimSpace(migration.Down) == "" {
+				return fmt.Errorf("migration %03d_%s has no down SQL", migration.Version, migration.Name)
+			}
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, m.dialect.delete, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("rollback %03d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			slog.Info("migration rolled back", "version", migration.Version, "name", migration.Name)
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status mengembalikan kondisi setiap versi, termasuk versi di database yang
+// tidak dikenal binary ini, urut berdasarkan versi
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	done, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	known := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := done[migration.Version]; ok {
+			appliedAt := record.appliedAt
+			status.AppliedAt = &appliedAt
+			status.Modified = record.checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range done {
+		if known[version] {
+			continue
+		}
+		appliedAt := record.appliedAt
+		statuses = append(statuses, Status{Version: version, Name: record.name, AppliedAt: &appliedAt, Unknown: true})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Pending jumlah migrasi binary ini yang belum diterapkan dan versi schema
+// tertinggi yang sudah diterapkan
+func (m *Migrator) Pending(ctx context.Context) (pending int, version int64, err error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, status := range statuses {
+		if status.Modified {
+			return 0, 0, fmt.Errorf("%w: version %d", ErrChecksumMismatch, status.Version)
+		}
+		if status.AppliedAt == nil {
+			pending++
+		} else if status.Version > version {
+			version = status.Version
+		}
+	}
+	return pending, version, nil
+}
+
+// verify menolak melanjutkan jika ada migrasi yang diubah setelah diterapkan.
+// Versi di database yang tidak dikenal dibiarkan agar binary lama tetap bisa
+// start saat rolling deploy.
+func (m *Migrator) verify(done map[int64]appliedRecord) error {
+	var modified []string
+	for _, migration := range m.migrations {
+		if record, ok := done[migration.Version]; ok && record.checksum != migration.Checksum {
+			modified = append(modified, fmt.Sprintf("%03d_%s", migration.Version, migration.Name))
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("%w: %s was changed after it was applied, add a new migration instead", ErrChecksumMismatch, strings.Join(modified, ", "))
+	}
+	return nil
+}
+
+// withLock menjalankan fn di satu koneksi yang memegang advisory lock.
+// Lock level session, jadi semua query migrasi harus memakai koneksi yang sama.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.dialect.lock != "" {
+		start := time.Now()
+		if _, err := conn.ExecContext(ctx, m.dialect.lock, lockID); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if waited := time.Since(start); waited > time.Second {
+			slog.Info("migration lock acquired", "waited_ms", waited.Milliseconds())
+		}
+		defer func() {
+			// Context bisa sudah berakhir; unlock tetap harus dikirim
+			if _, err := conn.ExecContext(context.Background(), m.dialect.unlock, lockID); err != nil {
+				slog.Error("failed to release migration lock", "error", err)
+			}
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+type appliedRecord struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// appliedVersions membaca schema_migrations; tabel yang belum ada berarti
+// belum ada migrasi yang diterapkan
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]appliedRecord, error) {
+	var exists bool
+	if err := conn.QueryRowContext(ctx, m.dialect.tableExists).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	done := make(map[int64]appliedRecord)
+	if !exists {
+		return done, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int64
+		var record appliedRecord
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+		}
+		done[version] = record
+	}
+	return done, rows.Err()
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
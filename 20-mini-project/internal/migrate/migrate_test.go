@@ -0,0 +1,531 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	sqlite "github.com/glebarez/go-sqlite"
+	gormsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testLock meniru pg_advisory_lock untuk SQLite: fungsi SQL test_lock menunggu
+// sampai lock bebas, test_unlock melepasnya
+var testLock = struct {
+	sem      chan struct{}
+	mu       sync.Mutex
+	acquired []int64
+	released []int64
+	onLock   func() // dipanggil setelah lock didapat
+}{sem: make(chan struct{}, 1)}
+
+func init() {
+	sqlite.MustRegisterScalarFunction("test_lock", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		testLock.sem <- struct{}{}
+		testLock.mu.Lock()
+		testLock.acquired = append(testLock.acquired, args[0].(int64))
+		onLock := testLock.onLock
+		testLock.mu.Unlock()
+		if onLock != nil {
+			onLock()
+		}
+		return true, nil
+	})
+	sqlite.MustRegisterScalarFunction("test_unlock", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		testLock.mu.Lock()
+		testLock.released = append(testLock.released, args[0].(int64))
+		testLock.mu.Unlock()
+		<-testLock.sem
+		return true, nil
+	})
+}
+
+// lockingSQLite dialect SQLite dengan lock seperti Postgres
+func lockingSQLite() dialect {
+	d := dialects["sqlite"]
+	d.lock, d.unlock = "SELECT test_lock(?)", "SELECT test_unlock(?)"
+	return d
+}
+
+func newTestSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "migrate.db")
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func testMigrations(t *testing.T) []Migration {
+	t.Helper()
+	migrations, err := Parse(fstest.MapFS{
+		"001_create_labels.up.sql":     {Data: []byte("CREATE TABLE labels (id INTEGER PRIMARY KEY, name TEXT NOT NULL);")},
+		"001_create_labels.down.sql":   {Data: []byte("DROP TABLE labels;")},
+		"002_add_label_color.up.sql":   {Data: []byte("ALTER TABLE labels ADD COLUMN color TEXT;")},
+		"002_add_label_color.down.sql": {Data: []byte("ALTER TABLE labels DROP COLUMN color;")},
+		"003_seed_labels.up.sql":       {Data: []byte("INSERT INTO labels (name) VALUES ('bug');")},
+	})
+	require.NoError(t, err)
+	return migrations
+}
+
+func hasTable(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count))
+	return count > 0
+}
+
+func TestUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLite(t)
+	migrations := testMigrations(t)
+	migrator, err := New(db, "sqlite", migrations)
+	require.NoError(t, err)
+
+	pending, version, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, pending)
+	assert.Zero(t, version)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 3)
+	for i, m := range applied {
+		assert.Equal(t, int64(i+1), m.Version)
+	}
+	assert.True(t, hasTable(t, db, "labels"))
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied, "up is idempotent")
+
+	// 003 tidak punya file down, rollback berhenti di sana tanpa mengubah apa pun
+	_, err = migrator.Down(ctx, 1)
+	assert.ErrorContains(t, err, "003_seed_labels has no down SQL")
+	_, version, err = migrator.Pending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+
+	// Binary tanpa 003 me-rollback dari versi tertinggi yang dikenalnya
+	migrator, err = New(db, "sqlite", migrations[:2])
+	require.NoError(t, err)
+	reverted, err := migrator.Down(ctx, 5)
+	require.NoError(t, err)
+	require.Len(t, reverted, 2)
+	assert.Equal(t, int64(2), reverted[0].Version)
+	assert.Equal(t, int64(1), reverted[1].Version)
+	assert.False(t, hasTable(t, db, "labels"))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.Nil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.True(t, statuses[2].Unknown, "version applied by a newer binary")
+	assert.NotNil(t, statuses[2].AppliedAt)
+
+	_, err = migrator.Down(ctx, 0)
+	assert.Error(t, err)
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLite(t)
+	migrations := testMigrations(t)
+	migrations[1].Up = "ALTER TABLE labels ADD COLUMN color TEXT; SELECT * FROM missing_table;"
+	migrator, err := New(db, "sqlite", migrations)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	assert.ErrorContains(t, err, "migration 002_add_label_color failed")
+	require.Len(t, applied, 1)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+
+	// Kolom dari statement pertama ikut di-rollback
+	_, err = db.Exec("SELECT color FROM labels")
+	assert.Error(t, err)
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLite(t)
+	migrator, err := New(db, "sqlite", testMigrations(t))
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	// File 001 diubah setelah diterapkan
+	changed := testMigrations(t)
+	changed[0].Up = "CREATE TABLE labels (id INTEGER PRIMARY KEY, name TEXT NOT NULL, color TEXT);"
+	changed[0].Checksum = "changed"
+	migrator, err = New(db, "sqlite", changed)
+	require.NoError(t, err)
+
+	_, err = migrator.Up(ctx)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.ErrorContains(t, err, "001_create_labels")
+	_, err = migrator.Down(ctx, 1)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	_, _, err = migrator.Pending(ctx)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Modified)
+	assert.False(t, statuses[1].Modified)
+}
+
+func TestUpWaitsForLock(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLite(t)
+	migrator := &Migrator{db: db, dialect: lockingSQLite(), migrations: testMigrations(t)}
+
+	// Proses lain sedang memegang lock
+	testLock.sem <- struct{}{}
+	done := make(chan error, 1)
+	go func() {
+		_, err := migrator.Up(ctx)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("up finished while the lock was held: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.False(t, hasTable(t, db, "schema_migrations"), "nothing runs before the lock is acquired")
+
+	<-testLock.sem
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("up did not continue after the lock was released")
+	}
+	assert.True(t, hasTable(t, db, "labels"))
+}
+
+func TestConcurrentUpAppliesOnce(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLite(t)
+	migrations := testMigrations(t)
+	testLock.mu.Lock()
+	testLock.acquired, testLock.released = nil, nil
+	testLock.mu.Unlock()
+
+	var wg sync.WaitGroup
+	results := make([][]Migration, 3)
+	errs := make([]error, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			migrator := &Migrator{db: db, dialect: lockingSQLite(), migrations: migrations}
+			results[i], errs[i] = migrator.Up(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for i := range results {
+		require.NoError(t, errs[i])
+		total += len(results[i])
+	}
+	assert.Equal(t, len(migrations), total, "every migration is applied exactly once")
+
+	testLock.mu.Lock()
+	defer testLock.mu.Unlock()
+	assert.Equal(t, []int64{lockID, lockID, lockID}, testLock.acquired)
+	assert.Equal(t, []int64{lockID, lockID, lockID}, testLock.released)
+}
+
+func TestLockIsReleasedWhenMigrationFails(t *testing.T) {
+	db := newTestSQLite(t)
+	migrations := testMigrations(t)
+	migrations[0].Up = "CREATE TABLE broken ("
+	migrator := &Migrator{db: db, dialect: lockingSQLite(), migrations: migrations}
+
+	_, err := migrator.Up(context.Background())
+	require.Error(t, err)
+
+	assertLockReleased(t)
+
+	// Context berakhir saat lock dipegang, unlock tetap dikirim
+	ctx, cancel := context.WithCancel(context.Background())
+	testLock.mu.Lock()
+	testLock.onLock = cancel
+	testLock.mu.Unlock()
+	t.Cleanup(func() {
+		testLock.mu.Lock()
+		testLock.onLock = nil
+		testLock.mu.Unlock()
+	})
+	_, err = migrator.Up(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assertLockReleased(t)
+}
+
+func assertLockReleased(t *testing.T) {
+	t.Helper()
+	select {
+	case testLock.sem <- struct{}{}:
+		<-testLock.sem
+	case <-time.After(time.Second):
+		t.Fatal("migration lock was not released")
+	}
+}
+
+// baselineUser dan baselineTodo model sebelum ada migrasi SQL; database lama
+// dibuat dengan AutoMigrate(&model.User{}, &model.Todo{}) dari model ini
+type baselineUser struct {
+	ID        uint   `gorm:"primaryKey"`
+	Username  string `gorm:"unique;not null;size:50;index"`
+	Email     string `gorm:"unique;not null;size:100;index"`
+	Password  string `gorm:"not null"`
+	FullName  string `gorm:"size:100"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineTodo struct {
+	ID          uint   `gorm:"primaryKey"`
+	Title       string `gorm:"not null;size:200"`
+	Description string `gorm:"type:text"`
+	Status      string `gorm:"type:varchar(20);default:'pending';index"`
+	Priority    string `gorm:"type:varchar(10);default:'medium'"`
+	DueDate     *time.Time
+	UserID      uint         `gorm:"not null;index"`
+	User        baselineUser `gorm:"foreignKey:UserID"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+func (baselineTodo) TableName() string { return "todos" }
+
+// seedBaseline membuat schema AutoMigrate lama dengan satu user dan satu todo
+func seedBaseline(t *testing.T, gormDB *gorm.DB) {
+	t.Helper()
+	require.NoError(t, gormDB.AutoMigrate(&baselineUser{}, &baselineTodo{}))
+	user := baselineUser{Username: "budi", Email: "budi@example.com", Password: "hash"}
+	require.NoError(t, gormDB.Create(&user).Error)
+	require.NoError(t, gormDB.Create(&baselineTodo{Title: "Belajar Go", Status: "in_progress", UserID: user.ID}).Error)
+}
+
+// assertUpgradedTodos memastikan todo lama tetap ada dan kolom baru terisi default
+func assertUpgradedTodos(t *testing.T, db *sql.DB) {
+	t.Helper()
+	var title, status string
+	var position, version int64
+	require.NoError(t, db.QueryRow("SELECT title, status, position, version FROM todos").Scan(&title, &status, &position, &version))
+	assert.Equal(t, "Belajar Go", title)
+	assert.Equal(t, "in_progress", status)
+	assert.Zero(t, position)
+	assert.Equal(t, int64(1), version)
+
+	_, err := db.Exec("INSERT INTO todos (title, user_id, position, version) SELECT 'Baru', id, 1, 1 FROM users")
+	require.NoError(t, err)
+}
+
+func TestEmbeddedUpgradesAutoMigrateSchema(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "baseline.db")
+	gormDB, err := gorm.Open(gormsqlite.Open("file:"+path+"?_pragma=foreign_keys(1)"), &gorm.Config{})
+	require.NoError(t, err)
+	db, err := gormDB.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	seedBaseline(t, gormDB)
+
+	migrations, err := Embedded("sqlite")
+	require.NoError(t, err)
+	migrator, err := New(db, "sqlite", migrations)
+	require.NoError(t, err)
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations))
+
+	assertUpgradedTodos(t, db)
+	var indexes int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = 'todos' AND name LIKE 'idx_todos_%'").Scan(&indexes))
+	assert.Equal(t, 3, indexes, "indexes are recreated with the table")
+}
+
+// TestEmbeddedUpDownStatus menerapkan dan me-rollback schema aplikasi yang
+// sebenarnya dengan lock seperti di Postgres
+func TestEmbeddedUpDownStatus(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLite(t)
+	migrations, err := Embedded("sqlite")
+	require.NoError(t, err)
+	testLock.mu.Lock()
+	testLock.acquired, testLock.released = nil, nil
+	testLock.mu.Unlock()
+	migrator := &Migrator{db: db, dialect: lockingSQLite(), migrations: migrations}
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations))
+	for _, table := range []string{"users", "todos", "board_settings", "idempotency_keys"} {
+		assert.True(t, hasTable(t, db, table), table)
+	}
+	pending, version, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+	assert.Equal(t, migrations[len(migrations)-1].Version, version)
+
+	reverted, err := migrator.Down(ctx, len(migrations))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(migrations))
+	assert.False(t, hasTable(t, db, "todos"))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, len(migrations))
+	for _, status := range statuses {
+		assert.Nil(t, status.AppliedAt, "%03d_%s", status.Version, status.Name)
+	}
+
+	// Up lagi setelah down penuh menghasilkan schema yang sama
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.True(t, hasTable(t, db, "todos"))
+
+	testLock.mu.Lock()
+	defer testLock.mu.Unlock()
+	assert.Equal(t, []int64{lockID, lockID, lockID}, testLock.acquired)
+	assert.Equal(t, []int64{lockID, lockID, lockID}, testLock.released)
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for _, driver := range Drivers() {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, driver), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sqlite", "004_existing.up.sql"), []byte("SELECT 1;"), 0o644))
+
+	created, err := Create(dir, "Add Todo Labels!")
+	require.NoError(t, err)
+	assert.Len(t, created, 2*len(Drivers()))
+	for _, driver := range Drivers() {
+		assert.FileExists(t, filepath.Join(dir, driver, "005_add_todo_labels.up.sql"))
+		assert.FileExists(t, filepath.Join(dir, driver, "005_add_todo_labels.down.sql"))
+	}
+
+	_, err = Create(dir, "  --- ")
+	assert.Error(t, err)
+	_, err = Create(filepath.Join(dir, "missing"), "labels")
+	assert.Error(t, err)
+}
+
+// TestPostgresAdvisoryLock menjalankan migrasi bersamaan di Postgres sungguhan.
+// Set TEST_POSTGRES_DSN ke database kosong yang boleh diubah untuk menjalankannya.
+func TestPostgresAdvisoryLock(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+	gormDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	db, err := gormDB.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations := testMigrations(t)[:2]
+	var wg sync.WaitGroup
+	applied := make([]int, 4)
+	errs := make([]error, 4)
+	for i := range applied {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			migrator, err := New(db, "postgres", migrations)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			done, err := migrator.Up(ctx)
+			applied[i], errs[i] = len(done), err
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for i := range applied {
+		require.NoError(t, errs[i])
+		total += applied[i]
+	}
+	assert.Equal(t, len(migrations), total)
+
+	migrator, err := New(db, "postgres", migrations)
+	require.NoError(t, err)
+	reverted, err := migrator.Down(ctx, len(migrations))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(migrations))
+
+	// Lock sudah dilepas oleh semua koneksi
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	var free bool
+	require.NoError(t, conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockID).Scan(&free))
+	assert.True(t, free)
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockID)
+	require.NoError(t, err)
+}
+
+// TestPostgresEmbeddedMigrations menjalankan up, down, dan status schema
+// aplikasi di Postgres sungguhan, dimulai dari schema AutoMigrate lama.
+// Set TEST_POSTGRES_DSN ke database kosong yang boleh diubah untuk menjalankannya.
+func TestPostgresEmbeddedMigrations(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+	gormDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	db, err := gormDB.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	seedBaseline(t, gormDB)
+
+	migrations, err := Embedded("postgres")
+	require.NoError(t, err)
+	migrator, err := New(db, "postgres", migrations)
+	require.NoError(t, err)
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations))
+	assertUpgradedTodos(t, db)
+
+	reverted, err := migrator.Down(ctx, len(migrations))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(migrations))
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.Nil(t, status.AppliedAt, "%03d_%s", status.Version, status.Name)
+	}
+	_, err = db.Exec("DROP TABLE schema_migrations")
+	require.NoError(t, err)
+}
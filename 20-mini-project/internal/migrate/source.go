@@ -0,0 +1,140 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Files migrasi SQL bawaan aplikasi per driver database, ikut ter-compile ke dalam binary
+//
+//go:embed sql/postgres/*.sql sql/sqlite/*.sql
+var files embed.FS
+
+// Dir lokasi file migrasi di source tree, satu subdirektori per driver.
+// Tujuan default perintah create.
+const Dir = "internal/migrate/sql"
+
+// fileName format nama file: 001_create_tables.up.sql / 001_create_tables.down.sql
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration satu versi schema
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // SHA-256 dari SQL up, untuk mendeteksi file yang diubah setelah diterapkan
+}
+
+// Embedded mengembalikan migrasi bawaan aplikasi untuk driver (postgres, sqlite)
+func Embedded(driver string) ([]Migration, error) {
+	if _, ok := dialects[driver]; !ok {
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+	sub, err := fs.Sub(files, "sql/"+driver)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(sub)
+}
+
+// Parse membaca semua file migrasi di root fsys, urut berdasarkan versi.
+// Setiap versi wajib punya file up; file down boleh tidak ada, tetapi versi
+// tersebut tidak bisa di-rollback.
+func Parse(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, expected e.g. 002_add_labels.up.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %03d_%s has no up SQL", m.Version, m.Name)
+		}
+		sum := sha256.Sum256([]byte(m.Up))
+		m.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Create membuat pasangan file up/down kosong dengan versi berikutnya di
+// subdirektori setiap driver di dir, agar schema semua driver tetap setara,
+// dan mengembalikan path file yang dibuat
+func Create(dir, name string) ([]string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return nil, fmt.Errorf("migration name is required")
+	}
+
+	// Versi berikutnya dihitung dari semua driver supaya nomornya sama
+	var version int64 = 1
+	for _, driver := range Drivers() {
+		existing, err := Parse(os.DirFS(filepath.Join(dir, driver)))
+		if err != nil {
+			return nil, err
+		}
+		if n := len(existing); n > 0 && existing[n-1].Version >= version {
+			version = existing[n-1].Version + 1
+		}
+	}
+
+	var created []string
+	for _, driver := range Drivers() {
+		base := filepath.Join(dir, driver, fmt.Sprintf("%03d_%s", version, name))
+		up, down := base+".up.sql", base+".down.sql"
+		if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0o644); err != nil {
+			return created, fmt.Errorf("failed to create migration: %w", err)
+		}
+		if err := os.WriteFile(down, []byte("-- revert "+name+"\n"), 0o644); err != nil {
+			return created, fmt.Errorf("failed to create migration: %w", err)
+		}
+		created = append(created, up, down)
+	}
+	return created, nil
+}
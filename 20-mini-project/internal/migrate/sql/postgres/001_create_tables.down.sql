@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS board_settings;
+DROP TABLE IF EXISTS todo_dependencies;
+DROP TABLE IF EXISTS todo_template_items;
+DROP TABLE IF EXISTS todo_templates;
+DROP TABLE IF EXISTS todos;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS board_shares (
+    owner_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (owner_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_board_shares_user_id ON board_shares (user_id);
@@ -0,0 +1,3 @@
+DELETE FROM board_settings WHERE wip_limit IS NULL;
+ALTER TABLE board_settings ALTER COLUMN wip_limit SET DEFAULT 0;
+ALTER TABLE board_settings ALTER COLUMN wip_limit SET NOT NULL;
@@ -0,0 +1,5 @@
+-- NULL berarti user belum mengatur WIP limit dan memakai default WIP_LIMIT.
+-- Baris bisa dibuat tanpa limit, sehingga pengecekan WIP limit selalu punya
+-- baris board_settings untuk di-lock.
+ALTER TABLE board_settings ALTER COLUMN wip_limit DROP NOT NULL;
+ALTER TABLE board_settings ALTER COLUMN wip_limit DROP DEFAULT;
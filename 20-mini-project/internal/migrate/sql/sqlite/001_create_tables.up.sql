@@ -0,0 +1,154 @@
+-- Schema awal versi SQLite, harus tetap setara dengan postgres/001_create_tables.up.sql
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username VARCHAR(50) NOT NULL UNIQUE,
+    email VARCHAR(100) NOT NULL UNIQUE,
+    password TEXT NOT NULL,
+    full_name VARCHAR(100),
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS todos (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) DEFAULT 'pending',
+    priority VARCHAR(10) DEFAULT 'medium',
+    position BIGINT NOT NULL DEFAULT 0,
+    version BIGINT NOT NULL DEFAULT 1,
+    due_date DATETIME,
+    user_id BIGINT NOT NULL,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    CONSTRAINT fk_users_todos FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+-- Tabel todos dari AutoMigrate(&model.User{}, &model.Todo{}) belum punya kolom
+-- position dan version. SQLite tidak mengenal ADD COLUMN IF NOT EXISTS, jadi
+-- tabel dibuat ulang dengan kolom lama disalin; di database baru tabelnya
+-- masih kosong.
+CREATE TABLE todos_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) DEFAULT 'pending',
+    priority VARCHAR(10) DEFAULT 'medium',
+    position BIGINT NOT NULL DEFAULT 0,
+    version BIGINT NOT NULL DEFAULT 1,
+    due_date DATETIME,
+    user_id BIGINT NOT NULL,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    CONSTRAINT fk_users_todos FOREIGN KEY (user_id) REFERENCES users (id)
+);
+INSERT INTO todos_new (id, title, description, status, priority, due_date, user_id, created_at, updated_at, deleted_at)
+    SELECT id, title, description, status, priority, due_date, user_id, created_at, updated_at, deleted_at FROM todos;
+DROP TABLE todos;
+ALTER TABLE todos_new RENAME TO todos;
+CREATE INDEX IF NOT EXISTS idx_todos_status ON todos (status);
+CREATE INDEX IF NOT EXISTS idx_todos_user_id ON todos (user_id);
+CREATE INDEX IF NOT EXISTS idx_todos_deleted_at ON todos (deleted_at);
+
+CREATE TABLE IF NOT EXISTS todo_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    priority VARCHAR(10) DEFAULT 'medium',
+    due_offset_days BIGINT,
+    user_id BIGINT NOT NULL,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    CONSTRAINT fk_todo_templates_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_todo_templates_user_id ON todo_templates (user_id);
+CREATE INDEX IF NOT EXISTS idx_todo_templates_deleted_at ON todo_templates (deleted_at);
+
+CREATE TABLE IF NOT EXISTS todo_template_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    template_id BIGINT NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    priority VARCHAR(10) DEFAULT 'medium',
+    due_offset_days BIGINT,
+    position BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME,
+    updated_at DATETIME,
+    CONSTRAINT fk_todo_templates_items FOREIGN KEY (template_id) REFERENCES todo_templates (id)
+);
+CREATE INDEX IF NOT EXISTS idx_todo_template_items_template_id ON todo_template_items (template_id);
+
+CREATE TABLE IF NOT EXISTS todo_dependencies (
+    todo_id BIGINT NOT NULL,
+    depends_on_id BIGINT NOT NULL,
+    created_at DATETIME,
+    PRIMARY KEY (todo_id, depends_on_id)
+);
+CREATE INDEX IF NOT EXISTS idx_todo_dependencies_depends_on_id ON todo_dependencies (depends_on_id);
+
+CREATE TABLE IF NOT EXISTS board_settings (
+    user_id BIGINT PRIMARY KEY,
+    wip_limit BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id BIGINT NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    events VARCHAR(200) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    CONSTRAINT fk_webhooks_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks (user_id);
+CREATE INDEX IF NOT EXISTS idx_webhooks_deleted_at ON webhooks (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id BIGINT NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts BIGINT NOT NULL DEFAULT 0,
+    last_status_code BIGINT,
+    last_error TEXT,
+    next_attempt_at DATETIME,
+    delivered_at DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id BIGINT NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(500) NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL,
+    status_code BIGINT NOT NULL DEFAULT 0,
+    content_type VARCHAR(100),
+    body BLOB,
+    created_at DATETIME,
+    updated_at DATETIME,
+    expires_at DATETIME NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_user_key ON idempotency_keys (user_id, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
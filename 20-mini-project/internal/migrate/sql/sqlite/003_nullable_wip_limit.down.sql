@@ -0,0 +1,10 @@
+CREATE TABLE board_settings_old (
+    user_id BIGINT PRIMARY KEY,
+    wip_limit BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+INSERT INTO board_settings_old (user_id, wip_limit, created_at, updated_at)
+    SELECT user_id, wip_limit, created_at, updated_at FROM board_settings WHERE wip_limit IS NOT NULL;
+DROP TABLE board_settings;
+ALTER TABLE board_settings_old RENAME TO board_settings;
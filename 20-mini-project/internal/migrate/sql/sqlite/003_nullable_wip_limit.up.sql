@@ -0,0 +1,12 @@
+-- NULL berarti user belum mengatur WIP limit dan memakai default WIP_LIMIT.
+-- SQLite tidak bisa mengubah constraint kolom, jadi tabel dibuat ulang.
+CREATE TABLE board_settings_new (
+    user_id BIGINT PRIMARY KEY,
+    wip_limit BIGINT,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+INSERT INTO board_settings_new (user_id, wip_limit, created_at, updated_at)
+    SELECT user_id, wip_limit, created_at, updated_at FROM board_settings;
+DROP TABLE board_settings;
+ALTER TABLE board_settings_new RENAME TO board_settings;
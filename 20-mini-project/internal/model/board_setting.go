@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// BoardSetting menyimpan pengaturan kanban board milik user
+type BoardSetting struct {
+	UserID    uint `gorm:"primaryKey"`
+	WIPLimit  *int `gorm:"column:wip_limit"` // Batas todo in_progress, 0 berarti tanpa batas, nil berarti default WIP_LIMIT
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName override nama tabel
+func (BoardSetting) TableName() string {
+	return "board_settings"
+}
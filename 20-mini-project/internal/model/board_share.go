@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// BoardShare memberi user lain (collaborator) akses baca ke board pemilik:
+// ikut room realtime board dan todo milik pemilik
+type BoardShare struct {
+	OwnerID   uint `gorm:"primaryKey"`       // Pemilik board
+	UserID    uint `gorm:"primaryKey;index"` // Collaborator
+	User      User `gorm:"foreignKey:UserID"`
+	CreatedAt time.Time
+}
+
+// TableName override nama tabel
+func (BoardShare) TableName() string {
+	return "board_shares"
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// IdempotencyKey menyimpan hasil request mutasi berdasarkan header Idempotency-Key
+// sehingga retry dari client mendapat response yang sama tanpa efek ganda
+type IdempotencyKey struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"not null;uniqueIndex:idx_idempotency_user_key"`
+	Key         string `gorm:"not null;size:255;uniqueIndex:idx_idempotency_user_key"`
+	Method      string `gorm:"not null;size:10"`
+	Path        string `gorm:"not null;size:500"`
+	Fingerprint string `gorm:"not null;size:64"`   // SHA-256 dari method, path dan body request
+	StatusCode  int    `gorm:"not null;default:0"` // 0 berarti request masih diproses
+	ContentType string `gorm:"size:100"`
+	Body        []byte
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+// TableName override nama tabel
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// Completed menandakan response sudah tersimpan dan bisa di-replay
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -13,12 +13,18 @@ type Todo struct {
 	Description string `gorm:"type:text"`
 	Status      string `gorm:"type:varchar(20);default:'pending';index"`
 	Priority    string `gorm:"type:varchar(10);default:'medium'"`
+	Position    int    `gorm:"not null;default:0"` // Urutan di dalam kolom board (per status)
+	Version     uint   `gorm:"not null;default:1"` // Naik setiap update, dipakai untuk deteksi konflik sync
 	DueDate     *time.Time
 	UserID      uint `gorm:"not null;index"`
 	User        User `gorm:"foreignKey:UserID"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	// Diisi oleh TodoRepository.LoadDependencies, tidak disimpan sebagai kolom
+	BlockedBy []uint `gorm:"-"` // ID todo yang harus selesai sebelum todo ini
+	Blocking  []uint `gorm:"-"` // ID todo yang menunggu todo ini selesai
 }
 
 // TableName override nama tabel
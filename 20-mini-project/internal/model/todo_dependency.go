@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// TodoDependency merepresentasikan relasi "todo A diblokir oleh todo B"
+type TodoDependency struct {
+	TodoID      uint `gorm:"primaryKey"`       // Todo yang diblokir
+	DependsOnID uint `gorm:"primaryKey;index"` // Todo yang harus selesai lebih dulu
+	CreatedAt   time.Time
+}
+
+// TableName override nama tabel
+func (TodoDependency) TableName() string {
+	return "todo_dependencies"
+}
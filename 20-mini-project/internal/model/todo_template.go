@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TodoTemplate merepresentasikan template checklist yang bisa dipakai berulang
+type TodoTemplate struct {
+	ID            uint               `gorm:"primaryKey"`
+	Title         string             `gorm:"not null;size:200"`
+	Description   string             `gorm:"type:text"`
+	Priority      string             `gorm:"type:varchar(10);default:'medium'"`
+	DueOffsetDays *int               // Jumlah hari dari start date, nil berarti tanpa due date
+	UserID        uint               `gorm:"not null;index"`
+	User          User               `gorm:"foreignKey:UserID"`
+	Items         []TodoTemplateItem `gorm:"foreignKey:TemplateID"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+}
+
+// TableName override nama tabel
+func (TodoTemplate) TableName() string {
+	return "todo_templates"
+}
+
+// TodoTemplateItem merepresentasikan satu item checklist di dalam template
+type TodoTemplateItem struct {
+	ID            uint   `gorm:"primaryKey"`
+	TemplateID    uint   `gorm:"not null;index"`
+	Title         string `gorm:"not null;size:200"`
+	Description   string `gorm:"type:text"`
+	Priority      string `gorm:"type:varchar(10);default:'medium'"`
+	DueOffsetDays *int
+	Position      int `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// TableName override nama tabel
+func (TodoTemplateItem) TableName() string {
+	return "todo_template_items"
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status pengiriman webhook
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSending   = "sending" // Sedang dikirim oleh salah satu instance
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+// Webhook merepresentasikan endpoint milik user yang menerima event todo
+type Webhook struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	User      User   `gorm:"foreignKey:UserID"`
+	URL       string `gorm:"not null;size:500"`
+	Secret    string `gorm:"not null;size:100"` // Dipakai untuk HMAC signature, jangan expose setelah dibuat
+	Events    string `gorm:"not null;size:200"` // Daftar event dipisah koma, contoh: todo.created,todo.deleted
+	Active    bool   `gorm:"not null;default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// TableName override nama tabel
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// WebhookDelivery mencatat setiap pengiriman event ke sebuah webhook
+type WebhookDelivery struct {
+	ID             uint   `gorm:"primaryKey"`
+	WebhookID      uint   `gorm:"not null;index"`
+	EventID        string `gorm:"not null;size:64;index"`
+	EventType      string `gorm:"not null;size:50"`
+	Payload        string `gorm:"type:text;not null"`
+	Status         string `gorm:"type:varchar(20);not null;default:'pending';index"`
+	Attempts       int    `gorm:"not null;default:0"`
+	LastStatusCode int
+	LastError      string     `gorm:"type:text"`
+	NextAttemptAt  *time.Time `gorm:"index"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TableName override nama tabel
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,254 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// writeWait batas waktu menulis satu pesan ke client
+	writeWait = 10 * time.Second
+	// pongWait batas waktu menunggu pong dari client
+	pongWait = 60 * time.Second
+	// pingPeriod interval ping, harus lebih kecil dari pongWait
+	pingPeriod = (pongWait * 9) / 10
+	// maxMessageSize ukuran maksimal pesan dari client
+	maxMessageSize = 4096
+	// sendBuffer kapasitas antrian pesan keluar per client
+	sendBuffer = 64
+)
+
+// presenceStates state presence yang boleh dikirim client; "left" hanya
+// dikirim server saat client keluar dari room
+var presenceStates = []string{"viewing", "editing", "idle"}
+
+// Authorizer memeriksa apakah user boleh bergabung ke sebuah room
+type Authorizer func(userID uint, room string) error
+
+// Client adalah satu koneksi WebSocket milik user yang sudah login
+type Client struct {
+	hub       *Hub
+	conn      *websocket.Conn
+	send      chan []byte
+	userID    uint
+	username  string
+	authorize Authorizer
+	ctx       context.Context // context request upgrade, untuk logging
+
+	mu          sync.Mutex
+	rooms       map[string]struct{}
+	closed      bool
+	closeCode   int // close frame yang dikirim writePump saat antrian ditutup
+	closeReason string
+}
+
+// NewClient membungkus koneksi WebSocket yang sudah di-upgrade
+func NewClient(hub *Hub, conn *websocket.Conn, userID uint, username string, authorize Authorizer) *Client {
+	return &Client{
+		hub:       hub,
+		conn:      conn,
+		send:      make(chan []byte, sendBuffer),
+		userID:    userID,
+		username:  username,
+		authorize: authorize,
+		rooms:     make(map[string]struct{}),
+	}
+}
+
+// Run menjalankan write loop di goroutine terpisah dan read loop di
+// goroutine pemanggil sampai koneksi ditutup. ctx adalah context request
+// upgrade dan harus tetap hidup selama Run berjalan.
+func (c *Client) Run(ctx context.Context) {
+	c.ctx = ctx
+	c.hub.register(c)
+	go c.writePump()
+	c.readPump()
+}
+
+// trySend mengantrikan pesan tanpa blocking, false jika antrian penuh
+func (c *Client) trySend(data []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return true
+	}
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// close menutup antrian kirim karena client terlalu lambat; writePump
+// kemudian menutup koneksi
+func (c *Client) close() {
+	c.closeWith(websocket.ClosePolicyViolation, "too slow")
+}
+
+// closeWith menutup antrian kirim dengan close frame tertentu. Pemanggilan
+// berikutnya diabaikan.
+func (c *Client) closeWith(code int, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		c.closeCode = code
+		c.closeReason = reason
+		close(c.send)
+	}
+}
+
+// readPump membaca pesan dari client
+func (c *Client) readPump() {
+	defer func() {
+		c.leaveAll()
+		c.hub.unregister(c)
+		c.close()
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var msg Message
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		c.handle(msg)
+	}
+}
+
+// writePump mengirim pesan dari antrian dan ping berkala ke client
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.mu.Lock()
+				code, reason := c.closeCode, c.closeReason
+				c.mu.Unlock()
+				_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handle memproses satu pesan dari client
+func (c *Client) handle(msg Message) {
+	switch msg.Type {
+	case "subscribe":
+		if err := c.authorize(c.userID, msg.Room); err != nil {
+			c.reply(Message{Type: "error", Room: msg.Room, Message: err.Error()})
+			return
+		}
+		c.mu.Lock()
+		c.rooms[msg.Room] = struct{}{}
+		c.mu.Unlock()
+		c.hub.join(c, msg.Room)
+		c.reply(Message{Type: "subscribed", Room: msg.Room})
+
+	case "unsubscribe":
+		if c.inRoom(msg.Room) {
+			c.leave(msg.Room)
+		}
+
+	case "presence":
+		if !c.inRoom(msg.Room) {
+			c.reply(Message{Type: "error", Room: msg.Room, Message: "not subscribed to room"})
+			return
+		}
+		// State diteruskan ke user lain, jadi hanya nilai yang dikenal yang diterima
+		if !slices.Contains(presenceStates, msg.State) {
+			c.reply(Message{Type: "error", Room: msg.Room, Message: "invalid presence state, use viewing, editing or idle"})
+			return
+		}
+		c.hub.Broadcast(c.ctx, msg.Room, c.presence(msg.State), c)
+
+	default:
+		c.reply(Message{Type: "error", Message: "unknown message type"})
+	}
+}
+
+// reply mengirim pesan hanya ke client ini
+func (c *Client) reply(msg Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	if !c.trySend(data) {
+		c.close()
+	}
+}
+
+func (c *Client) presence(state string) Message {
+	return Message{Type: "presence", State: state, UserID: c.userID, Username: c.username}
+}
+
+func (c *Client) inRoom(room string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.rooms[room]
+	return ok
+}
+
+// leave keluar dari room dan memberi tahu anggota lain
+func (c *Client) leave(room string) {
+	c.mu.Lock()
+	delete(c.rooms, room)
+	c.mu.Unlock()
+
+	c.hub.leave(c, room)
+	c.hub.Broadcast(c.ctx, room, c.presence("left"), c)
+}
+
+// reauthorize keluar dari room yang tidak lagi diizinkan untuk user ini
+func (c *Client) reauthorize() {
+	for _, room := range c.joinedRooms() {
+		if err := c.authorize(c.userID, room); err != nil {
+			c.leave(room)
+			c.reply(Message{Type: "revoked", Room: room, Message: err.Error()})
+		}
+	}
+}
+
+func (c *Client) joinedRooms() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rooms := make([]string, 0, len(c.rooms))
+	for room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+func (c *Client) leaveAll() {
+	for _, room := range c.joinedRooms() {
+		c.leave(room)
+	}
+}
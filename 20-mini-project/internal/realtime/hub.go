@@ -0,0 +1,177 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/gorilla/websocket"
+)
+
+// Message adalah pesan JSON yang dikirim lewat WebSocket (dua arah)
+type Message struct {
+	Type     string      `json:"type"`               // subscribe, unsubscribe, presence, event, subscribed, revoked, error
+	Room     string      `json:"room,omitempty"`     // contoh: todo:12, board:3
+	State    string      `json:"state,omitempty"`    // presence: viewing, editing, idle, left
+	UserID   uint        `json:"user_id,omitempty"`  // presence: user pengirim
+	Username string      `json:"username,omitempty"` // presence: user pengirim
+	Event    interface{} `json:"event,omitempty"`    // event: perubahan todo
+	Message  string      `json:"message,omitempty"`  // error: penjelasan
+}
+
+// TodoRoom nama room untuk perubahan satu todo
+func TodoRoom(todoID uint) string {
+	return fmt.Sprintf("todo:%d", todoID)
+}
+
+// BoardRoom nama room untuk semua todo milik satu user
+func BoardRoom(userID uint) string {
+	return fmt.Sprintf("board:%d", userID)
+}
+
+// Hub menyimpan client yang terhubung beserta room yang mereka ikuti.
+// Broadcast tidak pernah blocking: client yang antrian kirimnya penuh
+// dianggap lambat dan diputus.
+type Hub struct {
+	mu      sync.RWMutex
+	rooms   map[string]map[*Client]struct{}
+	clients map[*Client]struct{}
+	closing bool
+	wg      sync.WaitGroup // client yang masih terhubung
+}
+
+// NewHub membuat hub baru
+func NewHub() *Hub {
+	return &Hub{
+		rooms:   make(map[string]map[*Client]struct{}),
+		clients: make(map[*Client]struct{}),
+	}
+}
+
+// HandleEvent meneruskan perubahan todo ke room todo dan board terkait.
+// Bisa langsung didaftarkan sebagai event.Bus handler.
+func (h *Hub) HandleEvent(e event.Event) {
+	msg := Message{Type: "event", Event: e}
+	ctx := context.Background()
+	h.Broadcast(ctx, TodoRoom(e.TodoID), msg, nil)
+	h.Broadcast(ctx, BoardRoom(e.UserID), msg, nil)
+}
+
+// Broadcast mengirim pesan ke semua client di room kecuali except
+func (h *Hub) Broadcast(ctx context.Context, room string, msg Message, except *Client) {
+	msg.Room = room
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.ErrorContext(ctx, "websocket: failed to encode message", "room", room, "type", msg.Type, "error", err)
+		return
+	}
+
+	var slow []*Client
+	h.mu.RLock()
+	for client := range h.rooms[room] {
+		if client == except {
+			continue
+		}
+		if !client.trySend(data) {
+			slow = append(slow, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range slow {
+		client.close()
+	}
+}
+
+// Reauthorize memeriksa ulang akses semua koneksi milik userID ke room yang
+// sedang diikuti, misalnya setelah board tidak lagi dibagikan. Room yang tidak
+// lagi diizinkan ditinggalkan dan client menerima pesan "revoked".
+func (h *Hub) Reauthorize(userID uint) {
+	var clients []*Client
+	h.mu.RLock()
+	for client := range h.clients {
+		if client.userID == userID {
+			clients = append(clients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		client.reauthorize()
+	}
+}
+
+// Close memutus semua client dengan close frame "going away" saat server
+// shutdown, sehingga client reconnect ke instance lain. Close menunggu
+// sampai semua koneksi tertutup atau ctx berakhir.
+func (h *Hub) Close(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	clients := make([]*Client, 0, len(h.clients))
+	for client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.Unlock()
+
+	for _, client := range clients {
+		client.closeWith(websocket.CloseGoingAway, "server is shutting down")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// register mencatat client yang terhubung. Client yang masuk setelah Close
+// langsung diputus.
+func (h *Hub) register(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[client] = struct{}{}
+	h.wg.Add(1)
+	if h.closing {
+		client.closeWith(websocket.CloseGoingAway, "server is shutting down")
+	}
+}
+
+// unregister menghapus client yang sudah terputus
+func (h *Hub) unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[client]; ok {
+		delete(h.clients, client)
+		h.wg.Done()
+	}
+}
+
+// join menambahkan client ke room
+func (h *Hub) join(client *Client, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*Client]struct{})
+	}
+	h.rooms[room][client] = struct{}{}
+}
+
+// leave mengeluarkan client dari room
+func (h *Hub) leave(client *Client, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.rooms[room], client)
+	if len(h.rooms[room]) == 0 {
+		delete(h.rooms, room)
+	}
+}
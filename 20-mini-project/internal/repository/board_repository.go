@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BoardRepository handles kanban board settings data access
+type BoardRepository struct {
+	db *gorm.DB
+}
+
+// NewBoardRepository creates a new board repository instance
+func NewBoardRepository(db *gorm.DB) *BoardRepository {
+	return &BoardRepository{db: db}
+}
+
+// FindSettingByUserID retrieves board settings of a user
+func (r *BoardRepository) FindSettingByUserID(ctx context.Context, userID uint) (*model.BoardSetting, error) {
+	var setting model.BoardSetting
+	err := r.db.WithContext(ctx).First(&setting, "user_id = ?", userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Return nil setting, no error for not found
+		}
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// SaveSetting creates or updates board settings of a user
+func (r *BoardRepository) SaveSetting(ctx context.Context, setting *model.BoardSetting) error {
+	return r.db.WithContext(ctx).Save(setting).Error
+}
+
+// CreateShare shares the owner's board with a collaborator; sharing twice is a no-op
+func (r *BoardRepository) CreateShare(ctx context.Context, share *model.BoardShare) error {
+	return r.db.WithContext(ctx).Omit("User").Clauses(clause.OnConflict{DoNothing: true}).Create(share).Error
+}
+
+// DeleteShare removes a collaborator, reporting whether the share existed
+func (r *BoardRepository) DeleteShare(ctx context.Context, ownerID, userID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("owner_id = ? AND user_id = ?", ownerID, userID).Delete(&model.BoardShare{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindSharesByOwnerID lists the collaborators of a board with their user
+func (r *BoardRepository) FindSharesByOwnerID(ctx context.Context, ownerID uint) ([]model.BoardShare, error) {
+	var shares []model.BoardShare
+	err := r.db.WithContext(ctx).Preload("User").Where("owner_id = ?", ownerID).Order("created_at ASC").Find(&shares).Error
+	return shares, err
+}
+
+// FindOwnerIDsSharedWith lists the owners whose board is shared with a user
+func (r *BoardRepository) FindOwnerIDsSharedWith(ctx context.Context, userID uint) ([]uint, error) {
+	var ownerIDs []uint
+	err := r.db.WithContext(ctx).Model(&model.BoardShare{}).Where("user_id = ?", userID).Order("owner_id ASC").Pluck("owner_id", &ownerIDs).Error
+	return ownerIDs, err
+}
+
+// IsShared checks if the owner's board is shared with a user
+func (r *BoardRepository) IsShared(ctx context.Context, ownerID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.BoardShare{}).Where("owner_id = ? AND user_id = ?", ownerID, userID).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository handles idempotency key data access
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository creates a new idempotency repository instance
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve inserts a new key, returning false when the key already exists for the user
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *model.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindByKey retrieves a key of a user
+func (r *IdempotencyRepository) FindByKey(ctx context.Context, userID uint, key string) (*model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+	err := r.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Return nil record, no error for not found
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// SaveResponse stores the response of a reserved key
+func (r *IdempotencyRepository) SaveResponse(ctx context.Context, id uint, statusCode int, contentType string, body []byte) error {
+	return r.db.WithContext(ctx).Model(&model.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status_code":  statusCode,
+		"content_type": contentType,
+		"body":         body,
+	}).Error
+}
+
+// Delete removes a key so it can be reserved again
+func (r *IdempotencyRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.IdempotencyKey{}, id).Error
+}
+
+// DeleteExpired removes keys whose retention period has passed
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
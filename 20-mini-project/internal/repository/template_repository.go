@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"gorm.io/gorm"
+)
+
+// TemplateRepository handles todo template data access
+type TemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewTemplateRepository creates a new template repository instance
+func NewTemplateRepository(db *gorm.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// Create creates a new template together with its checklist items
+func (r *TemplateRepository) Create(ctx context.Context, template *model.TodoTemplate) error {
+	return r.db.WithContext(ctx).Create(template).Error
+}
+
+// FindByID finds a template by ID including its checklist items
+func (r *TemplateRepository) FindByID(ctx context.Context, id uint) (*model.TodoTemplate, error) {
+	var template model.TodoTemplate
+	err := r.db.WithContext(ctx).Preload("Items", orderItemsByPosition).First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindByUserID finds all templates for a specific user
+func (r *TemplateRepository) FindByUserID(ctx context.Context, userID uint) ([]model.TodoTemplate, error) {
+	var templates []model.TodoTemplate
+	err := r.db.WithContext(ctx).Preload("Items", orderItemsByPosition).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&templates).Error
+	return templates, err
+}
+
+// Update updates a template. When replaceItems is true the existing
+// checklist items are removed and replaced with template.Items.
+func (r *TemplateRepository) Update(ctx context.Context, template *model.TodoTemplate, replaceItems bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if !replaceItems {
+			return tx.Omit("Items").Save(template).Error
+		}
+
+		if err := tx.Where("template_id = ?", template.ID).Delete(&model.TodoTemplateItem{}).Error; err != nil {
+			return err
+		}
+		for i := range template.Items {
+			template.Items[i].ID = 0
+		}
+		return tx.Save(template).Error
+	})
+}
+
+// Delete soft deletes a template
+func (r *TemplateRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.TodoTemplate{}, id).Error
+}
+
+func orderItemsByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
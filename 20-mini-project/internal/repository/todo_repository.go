@@ -1,29 +1,69 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// TodoRepository handles todo data access
+// ErrStaleVersion is returned when a todo changed after it was read, so a
+// write based on the old version would silently overwrite the newer change
+var ErrStaleVersion = errors.New("todo version is stale")
+
+// TodoRepository handles todo data access. Writes go to the primary; the
+// read-only listing and lookup methods read from a replica when one is healthy
+// (see database.Cluster.Reader and database.WithPrimary).
 type TodoRepository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	cluster *database.Cluster
 }
 
 // NewTodoRepository creates a new todo repository instance
-func NewTodoRepository(db *gorm.DB) *TodoRepository {
-	return &TodoRepository{db: db}
+func NewTodoRepository(cluster *database.Cluster) *TodoRepository {
+	return &TodoRepository{db: cluster.Primary(), cluster: cluster}
 }
 
 // Create creates a new todo
-func (r *TodoRepository) Create(todo *model.Todo) error {
-	return r.db.Create(todo).Error
+func (r *TodoRepository) Create(ctx context.Context, todo *model.Todo) error {
+	todo.Version = 1
+	return r.db.WithContext(ctx).Create(todo).Error
+}
+
+// CreateWithDependencies creates a todo and the edges to the todos it
+// depends on in one transaction, so a failed edge insert leaves no todo behind
+func (r *TodoRepository) CreateWithDependencies(ctx context.Context, todo *model.Todo, dependsOnIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		todo.Version = 1
+		if err := tx.Create(todo).Error; err != nil {
+			return err
+		}
+		if len(dependsOnIDs) == 0 {
+			return nil
+		}
+		return replaceDependencies(tx, todo.ID, dependsOnIDs)
+	})
+}
+
+// CreateBatch creates several todos in a single insert
+func (r *TodoRepository) CreateBatch(ctx context.Context, todos []model.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	for i := range todos {
+		todos[i].Version = 1
+	}
+	return r.db.WithContext(ctx).Create(&todos).Error
 }
 
-// FindByID finds a todo by ID
-func (r *TodoRepository) FindByID(id uint) (*model.Todo, error) {
+// FindByID finds a todo by ID, optionally preloading associations (e.g. "User")
+func (r *TodoRepository) FindByID(ctx context.Context, id uint, preloads ...string) (*model.Todo, error) {
 	var todo model.Todo
-	err := r.db.First(&todo, id).Error
+	err := withPreloads(r.reader(ctx), preloads).First(&todo, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -31,15 +71,15 @@ func (r *TodoRepository) FindByID(id uint) (*model.Todo, error) {
 }
 
 // FindByUserID finds all todos for a specific user
-func (r *TodoRepository) FindByUserID(userID uint) ([]model.Todo, error) {
+func (r *TodoRepository) FindByUserID(ctx context.Context, userID uint) ([]model.Todo, error) {
 	var todos []model.Todo
-	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&todos).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&todos).Error
 	return todos, err
 }
 
 // FindByUserIDWithFilters finds todos with filters (status, priority)
-func (r *TodoRepository) FindByUserIDWithFilters(userID uint, status, priority string) ([]model.Todo, error) {
-	query := r.db.Where("user_id = ?", userID)
+func (r *TodoRepository) FindByUserIDWithFilters(ctx context.Context, userID uint, status, priority string, preloads ...string) ([]model.Todo, error) {
+	query := withPreloads(r.reader(ctx), preloads).Where("user_id = ?", userID)
 
 	if status != "" {
 		query = query.Where("status = ?", status)
@@ -54,26 +94,336 @@ func (r *TodoRepository) FindByUserIDWithFilters(userID uint, status, priority s
 	return todos, err
 }
 
-// Update updates a todo
-func (r *TodoRepository) Update(todo *model.Todo) error {
-	return r.db.Save(todo).Error
+// FindPageByUserID finds one page of todos with filters and returns the total count
+func (r *TodoRepository) FindPageByUserID(ctx context.Context, userID uint, status, priority string, offset, limit int) ([]model.Todo, int64, error) {
+	query := r.reader(ctx).Model(&model.Todo{}).Where("user_id = ?", userID)
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if priority != "" {
+		query = query.Where("priority = ?", priority)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var todos []model.Todo
+	err := query.Order("created_at DESC").Order("id DESC").Offset(offset).Limit(limit).Find(&todos).Error
+	return todos, total, err
 }
 
-// Delete soft deletes a todo
-func (r *TodoRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Todo{}, id).Error
+// Update saves a todo and bumps its version. The write only succeeds while
+// the stored version is still todo.Version; otherwise it returns ErrStaleVersion.
+func (r *TodoRepository) Update(ctx context.Context, todo *model.Todo) error {
+	return updateVersion(r.db.WithContext(ctx), todo)
+}
+
+// UpdateWithDependencies saves a todo like Update and replaces the list of
+// todos it depends on in the same transaction
+func (r *TodoRepository) UpdateWithDependencies(ctx context.Context, todo *model.Todo, dependsOnIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateVersion(tx, todo); err != nil {
+			return err
+		}
+		return replaceDependencies(tx, todo.ID, dependsOnIDs)
+	})
+}
+
+// Delete soft deletes a todo and removes its dependency edges. Like Update it
+// only succeeds while the stored version is still todo.Version.
+func (r *TodoRepository) Delete(ctx context.Context, todo *model.Todo) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Todo di sisi lain edge ikut berubah (blocked_by/blocking)
+		var related []uint
+		err := tx.Model(&model.TodoDependency{}).
+			Where("todo_id = ? OR depends_on_id = ?", todo.ID, todo.ID).
+			Select("CASE WHEN todo_id = ? THEN depends_on_id ELSE todo_id END", todo.ID).
+			Scan(&related).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("todo_id = ? OR depends_on_id = ?", todo.ID, todo.ID).Delete(&model.TodoDependency{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("version = ?", todo.Version).Delete(&model.Todo{}, todo.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+		return touch(tx, related)
+	})
 }
 
 // ExistsByID checks if a todo exists by ID
-func (r *TodoRepository) ExistsByID(id uint) (bool, error) {
+func (r *TodoRepository) ExistsByID(ctx context.Context, id uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Todo{}).Where("id = ?", id).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.Todo{}).Where("id = ?", id).Count(&count).Error
 	return count > 0, err
 }
 
 // IsOwnedByUser checks if a todo belongs to a specific user
-func (r *TodoRepository) IsOwnedByUser(todoID, userID uint) (bool, error) {
+func (r *TodoRepository) IsOwnedByUser(ctx context.Context, todoID, userID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Todo{}).Where("id = ? AND user_id = ?", todoID, userID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.Todo{}).Where("id = ? AND user_id = ?", todoID, userID).Count(&count).Error
 	return count > 0, err
 }
+
+// CountOwnedByUser counts how many of the given todo IDs belong to a user
+func (r *TodoRepository) CountOwnedByUser(ctx context.Context, todoIDs []uint, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Todo{}).Where("id IN ? AND user_id = ?", todoIDs, userID).Count(&count).Error
+	return count, err
+}
+
+// CountOpen counts how many of the given todo IDs are not completed yet
+func (r *TodoRepository) CountOpen(ctx context.Context, todoIDs []uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Todo{}).Where("id IN ? AND status <> ?", todoIDs, "completed").Count(&count).Error
+	return count, err
+}
+
+// FindDependencyIDs returns the IDs of todos that todoID directly depends on
+func (r *TodoRepository) FindDependencyIDs(ctx context.Context, todoID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.TodoDependency{}).Where("todo_id = ?", todoID).Pluck("depends_on_id", &ids).Error
+	return ids, err
+}
+
+// FindTransitiveDependencyIDs returns every todo reachable from todoIDs by
+// following dependency edges (the dependencies of the dependencies, and so on)
+func (r *TodoRepository) FindTransitiveDependencyIDs(ctx context.Context, todoIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(todoIDs) == 0 {
+		return ids, nil
+	}
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE deps(id) AS (
+			SELECT depends_on_id FROM todo_dependencies WHERE todo_id IN ?
+			UNION
+			SELECT d.depends_on_id FROM todo_dependencies d JOIN deps ON d.todo_id = deps.id
+		)
+		SELECT id FROM deps`, todoIDs).Scan(&ids).Error
+	return ids, err
+}
+
+// LoadDependencies fills BlockedBy and Blocking for the given todos using a single query
+func (r *TodoRepository) LoadDependencies(ctx context.Context, todos ...*model.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(todos))
+	byID := make(map[uint]*model.Todo, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+		byID[todo.ID] = todo
+		todo.BlockedBy = []uint{}
+		todo.Blocking = []uint{}
+	}
+
+	var deps []model.TodoDependency
+	err := r.reader(ctx).Where("todo_id IN ? OR depends_on_id IN ?", ids, ids).
+		Order("todo_id, depends_on_id").
+		Find(&deps).Error
+	if err != nil {
+		return err
+	}
+
+	for _, dep := range deps {
+		if todo, ok := byID[dep.TodoID]; ok {
+			todo.BlockedBy = append(todo.BlockedBy, dep.DependsOnID)
+		}
+		if todo, ok := byID[dep.DependsOnID]; ok {
+			todo.Blocking = append(todo.Blocking, dep.TodoID)
+		}
+	}
+	return nil
+}
+
+// FindBoardByUserID finds all todos of a user ordered by board column position
+func (r *TodoRepository) FindBoardByUserID(ctx context.Context, userID uint) ([]model.Todo, error) {
+	var todos []model.Todo
+	err := r.reader(ctx).Where("user_id = ?", userID).Order("position ASC, id ASC").Find(&todos).Error
+	return todos, err
+}
+
+// WithWIPLimit runs fn, a write that may move a todo into in_progress, in a
+// transaction that first locks the user's board_settings row. Concurrent
+// moves of the same user are serialized, so check sees the in_progress count
+// (excluding excludeID) that fn's write will be added to. check receives the
+// stored limit, nil when the user has not set one, and aborts the write by
+// returning an error. fn gets a repository bound to the transaction and must
+// only use it for writes.
+func (r *TodoRepository) WithWIPLimit(ctx context.Context, userID, excludeID uint, check func(limit *int, inProgress int64) error, fn func(repo *TodoRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Baris dibuat tanpa limit jika belum ada agar selalu ada yang di-lock
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.BoardSetting{UserID: userID}).Error; err != nil {
+			return err
+		}
+		query := tx
+		// SQLite tidak mengenal FOR UPDATE; insert di atas sudah memegang
+		// write lock database sampai transaksi selesai
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		var setting model.BoardSetting
+		if err := query.First(&setting, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		var inProgress int64
+		err := tx.Model(&model.Todo{}).
+			Where("user_id = ? AND status = ? AND id <> ?", userID, "in_progress", excludeID).
+			Count(&inProgress).Error
+		if err != nil {
+			return err
+		}
+		if err := check(setting.WIPLimit, inProgress); err != nil {
+			return err
+		}
+		return fn(&TodoRepository{db: tx, cluster: r.cluster})
+	})
+}
+
+// NextPosition returns the position after the last todo in a board column
+func (r *TodoRepository) NextPosition(ctx context.Context, userID uint, status string) (int, error) {
+	var next int
+	err := r.db.WithContext(ctx).Model(&model.Todo{}).
+		Where("user_id = ? AND status = ?", userID, status).
+		Select("COALESCE(MAX(position), -1) + 1").
+		Scan(&next).Error
+	return next, err
+}
+
+// MoveToPosition moves a todo into a board column at the given position,
+// shifting the todos at or after that position down by one
+func (r *TodoRepository) MoveToPosition(ctx context.Context, todo *model.Todo, status string, position int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Todo{}).
+			Where("user_id = ? AND status = ? AND position >= ? AND id <> ?", todo.UserID, status, position, todo.ID).
+			Updates(map[string]interface{}{
+				"position":   gorm.Expr("position + 1"),
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(todo).
+			Where("version = ?", todo.Version).
+			Updates(map[string]interface{}{"status": status, "position": position, "version": todo.Version + 1})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+
+		todo.Status = status
+		todo.Position = position
+		todo.Version++
+		return nil
+	})
+}
+
+// FindChangedSince finds todos of a user (including soft-deleted ones) that
+// changed after the (changedAt, afterID) cursor, ordered by change time.
+// A todo's change time is its deleted_at when deleted, otherwise updated_at.
+func (r *TodoRepository) FindChangedSince(ctx context.Context, userID uint, changedAt time.Time, afterID uint, includeDeleted bool, limit int) ([]model.Todo, error) {
+	query := r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID)
+	if !includeDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+
+	var todos []model.Todo
+	err := query.
+		Where("(COALESCE(deleted_at, updated_at) > ? OR (COALESCE(deleted_at, updated_at) = ? AND id > ?))",
+			changedAt, changedAt, afterID).
+		Order("COALESCE(deleted_at, updated_at) ASC, id ASC").
+		Limit(limit).
+		Find(&todos).Error
+	return todos, err
+}
+
+// FindByIDUnscoped finds a todo by ID including soft-deleted ones
+func (r *TodoRepository) FindByIDUnscoped(ctx context.Context, id uint) (*model.Todo, error) {
+	var todo model.Todo
+	err := r.db.WithContext(ctx).Unscoped().First(&todo, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// updateVersion saves every column of todo with WHERE version = todo.Version
+// and bumps the version, returning ErrStaleVersion when no row matched
+func updateVersion(tx *gorm.DB, todo *model.Todo) error {
+	version := todo.Version
+	todo.Version++
+	result := tx.Model(todo).
+		Where("version = ?", version).
+		Select("*").Omit("created_at", clause.Associations).
+		Updates(todo)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrStaleVersion
+	}
+	if result.Error != nil {
+		todo.Version = version
+	}
+	return result.Error
+}
+
+// replaceDependencies replaces the dependency edges of todoID and bumps the
+// todos whose blocking list changed because of it
+func replaceDependencies(tx *gorm.DB, todoID uint, dependsOnIDs []uint) error {
+	var previous []uint
+	if err := tx.Model(&model.TodoDependency{}).Where("todo_id = ?", todoID).Pluck("depends_on_id", &previous).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("todo_id = ?", todoID).Delete(&model.TodoDependency{}).Error; err != nil {
+		return err
+	}
+	if len(dependsOnIDs) > 0 {
+		deps := make([]model.TodoDependency, len(dependsOnIDs))
+		for i, id := range dependsOnIDs {
+			deps[i] = model.TodoDependency{TodoID: todoID, DependsOnID: id}
+		}
+		if err := tx.Create(&deps).Error; err != nil {
+			return err
+		}
+	}
+	return touch(tx, append(previous, dependsOnIDs...))
+}
+
+// touch bumps version and updated_at of the given todos so sync clients pull them again
+func touch(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(&model.Todo{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// reader returns the connection for read-only queries
+func (r *TodoRepository) reader(ctx context.Context) *gorm.DB {
+	return r.cluster.Reader(ctx).WithContext(ctx)
+}
+
+// withPreloads adds one Preload per association; GORM loads each with a single IN query
+func withPreloads(db *gorm.DB, preloads []string) *gorm.DB {
+	for _, preload := range preloads {
+		db = db.Preload(preload)
+	}
+	return db
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"gorm.io/gorm"
+)
+
+// claimableStatuses status delivery yang boleh diambil dispatcher
+var claimableStatuses = []string{model.DeliveryStatusPending, model.DeliveryStatusSending}
+
+// WebhookRepository handles webhook and delivery data access
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository instance
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create creates a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *model.Webhook) error {
+	return r.db.WithContext(ctx).Create(webhook).Error
+}
+
+// FindByID finds a webhook by ID
+func (r *WebhookRepository) FindByID(ctx context.Context, id uint) (*model.Webhook, error) {
+	var webhook model.Webhook
+	err := r.db.WithContext(ctx).First(&webhook, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// FindByUserID finds all webhooks for a specific user
+func (r *WebhookRepository) FindByUserID(ctx context.Context, userID uint) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// FindActiveByUserID finds active webhooks for a specific user
+func (r *WebhookRepository) FindActiveByUserID(ctx context.Context, userID uint) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := r.db.WithContext(ctx).Where("user_id = ? AND active = ?", userID, true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+// Update updates a webhook
+func (r *WebhookRepository) Update(ctx context.Context, webhook *model.Webhook) error {
+	return r.db.WithContext(ctx).Save(webhook).Error
+}
+
+// Delete soft deletes a webhook
+func (r *WebhookRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Webhook{}, id).Error
+}
+
+// CreateDeliveries creates delivery records in a single insert
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&deliveries).Error
+}
+
+// FindDeliveryByID finds a delivery by ID
+func (r *WebhookRepository) FindDeliveryByID(ctx context.Context, id uint) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := r.db.WithContext(ctx).First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindDeliveriesByWebhookID finds the latest deliveries of a webhook
+func (r *WebhookRepository) FindDeliveriesByWebhookID(ctx context.Context, webhookID uint, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.WithContext(ctx).Where("webhook_id = ?", webhookID).Order("id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// FindDueDeliveries finds pending deliveries whose next attempt is due, and
+// sending deliveries whose claim lease expired (the instance sending them died)
+func (r *WebhookRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.WithContext(ctx).Where("status IN ? AND next_attempt_at <= ?", claimableStatuses, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDelivery marks a due delivery as being sent by this instance until
+// leaseUntil and counts the attempt. The update only matches the row as it was
+// read (same attempts), so when several instances race for the same delivery
+// exactly one of them gets true.
+func (r *WebhookRepository) ClaimDelivery(ctx context.Context, delivery *model.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).
+		Where("id = ? AND attempts = ? AND status IN ?", delivery.ID, delivery.Attempts, claimableStatuses).
+		Updates(map[string]interface{}{
+			"status":          model.DeliveryStatusSending,
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": leaseUntil,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	delivery.Status = model.DeliveryStatusSending
+	delivery.Attempts++
+	delivery.NextAttemptAt = &leaseUntil
+	return true, nil
+}
+
+// UpdateDelivery updates a delivery record
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Save(delivery).Error
+}
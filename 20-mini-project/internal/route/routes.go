@@ -2,41 +2,75 @@ package route
 
 import (
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/handler"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/metrics"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
+// Middlewares berisi middleware yang membutuhkan dependency dari main
+type Middlewares struct {
+	// Idempotency me-replay response request mutasi yang membawa Idempotency-Key
+	Idempotency gin.HandlerFunc
+	// IPRateLimit limit per IP yang dipasang sebelum AuthMiddleware, agar
+	// request dengan token tidak valid tetap dibatasi
+	IPRateLimit gin.HandlerFunc
+	// RateLimit limit default per user untuk endpoint protected
+	RateLimit gin.HandlerFunc
+	// AuthRateLimit limit ketat per IP untuk login dan register
+	AuthRateLimit gin.HandlerFunc
+	// Monitoring membatasi /metrics untuk pemegang token monitoring
+	Monitoring gin.HandlerFunc
+}
+
 // SetupRoutes configures all application routes
 func SetupRoutes(
 	router *gin.Engine,
 	userHandler *handler.UserHandler,
 	healthHandler *handler.HealthHandler,
 	todoHandler *handler.TodoHandler,
+	templateHandler *handler.TemplateHandler,
+	boardHandler *handler.BoardHandler,
+	webhookHandler *handler.WebhookHandler,
+	eventHandler *handler.EventHandler,
+	webSocketHandler *handler.WebSocketHandler,
+	syncHandler *handler.SyncHandler,
+	graphQLHandler *handler.GraphQLHandler,
+	middlewares Middlewares,
 ) {
-	// Health check endpoint (public)
+	// Health check endpoint (public). /health tetap ada dengan format lama
+	// untuk kompatibilitas.
 	router.GET("/health", healthHandler.HealthCheck)
+	router.GET("/health/live", healthHandler.Live)
+	router.GET("/health/ready", healthHandler.Ready)
+
+	// Prometheus metrics (butuh token monitoring)
+	router.GET("/metrics", middlewares.Monitoring, gin.WrapH(metrics.Handler()))
 
-	// API v1 group
-	v1 := router.Group("/api/v1")
-	{
+	// GraphQL endpoint (protected)
+	router.POST("/graphql", middlewares.IPRateLimit, middleware.AuthMiddleware(), middlewares.RateLimit, graphQLHandler.Query)
+
+	// registerAPI mendaftarkan semua resource route. v1 dan v2 memakai handler
+	// yang sama dan hanya berbeda pada format error response.
+	registerAPI := func(api *gin.RouterGroup) {
 		// Auth routes (public)
-		auth := v1.Group("/auth")
+		auth := api.Group("/auth")
+		auth.Use(middlewares.AuthRateLimit)
 		{
 			auth.POST("/register", userHandler.Register)
 			auth.POST("/login", userHandler.Login)
 		}
 
 		// User routes (protected)
-		users := v1.Group("/users")
-		users.Use(middleware.AuthMiddleware()) // Apply JWT middleware
+		users := api.Group("/users")
+		users.Use(middlewares.IPRateLimit, middleware.AuthMiddleware(), middlewares.RateLimit, middlewares.Idempotency) // Apply JWT middleware
 		{
 			users.GET("/profile", userHandler.GetProfile)
 			users.PUT("/profile", userHandler.UpdateProfile)
 		}
 
 		// Todo routes (protected)
-		todos := v1.Group("/todos")
-		todos.Use(middleware.AuthMiddleware())
+		todos := api.Group("/todos")
+		todos.Use(middlewares.IPRateLimit, middleware.AuthMiddleware(), middlewares.RateLimit, middlewares.Idempotency)
 		{
 			todos.POST("", todoHandler.Create)
 			todos.GET("", todoHandler.GetAll)
@@ -44,5 +78,62 @@ func SetupRoutes(
 			todos.PUT("/:id", todoHandler.Update)
 			todos.DELETE("/:id", todoHandler.Delete)
 		}
+
+		// Template routes (protected)
+		templates := api.Group("/templates")
+		templates.Use(middlewares.IPRateLimit, middleware.AuthMiddleware(), middlewares.RateLimit, middlewares.Idempotency)
+		{
+			templates.POST("", templateHandler.Create)
+			templates.GET("", templateHandler.GetAll)
+			templates.GET("/:id", templateHandler.GetByID)
+			templates.PUT("/:id", templateHandler.Update)
+			templates.DELETE("/:id", templateHandler.Delete)
+			templates.POST("/:id/instantiate", templateHandler.Instantiate)
+		}
+
+		// Kanban board routes (protected)
+		board := api.Group("/board")
+		board.Use(middlewares.IPRateLimit, middleware.AuthMiddleware(), middlewares.RateLimit, middlewares.Idempotency)
+		{
+			board.GET("", boardHandler.GetBoard)
+			board.POST("/todos/:id/move", boardHandler.MoveTodo)
+			board.GET("/settings", boardHandler.GetSettings)
+			board.PUT("/settings", boardHandler.UpdateSettings)
+			board.GET("/shares", boardHandler.GetShares)
+			board.POST("/shares", boardHandler.Share)
+			board.DELETE("/shares/:user_id", boardHandler.Unshare)
+		}
+
+		// Webhook routes (protected)
+		webhooks := api.Group("/webhooks")
+		webhooks.Use(middlewares.IPRateLimit, middleware.AuthMiddleware(), middlewares.RateLimit, middlewares.Idempotency)
+		{
+			webhooks.POST("", webhookHandler.Create)
+			webhooks.GET("", webhookHandler.GetAll)
+			webhooks.PUT("/:id", webhookHandler.Update)
+			webhooks.DELETE("/:id", webhookHandler.Delete)
+			webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+			webhooks.POST("/:id/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+		}
+
+		// Offline-first delta sync routes (protected)
+		sync := api.Group("/sync")
+		sync.Use(middlewares.IPRateLimit, middleware.AuthMiddleware(), middlewares.RateLimit, middlewares.Idempotency)
+		{
+			sync.GET("", syncHandler.Pull)
+			sync.POST("", syncHandler.Push)
+		}
+
+		// Server-Sent Events stream (protected)
+		api.GET("/events", middlewares.IPRateLimit, middleware.AuthMiddleware(), middlewares.RateLimit, eventHandler.Stream)
+
+		// WebSocket collaboration channel (protected, token via header or ?access_token=)
+		api.GET("/ws", middlewares.IPRateLimit, middleware.WebSocketAuthMiddleware(), middlewares.RateLimit, webSocketHandler.Connect)
 	}
+
+	// API v1: error memakai dto.ErrorResponse
+	registerAPI(router.Group("/api/v1", middleware.APIVersion(1)))
+
+	// API v2: error memakai RFC 7807 problem details (application/problem+json)
+	registerAPI(router.Group("/api/v2", middleware.APIVersion(2)))
 }
@@ -1,12 +1,15 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/metrics"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
 )
 
@@ -32,9 +35,12 @@ func NewAuthService(userRepo *repository.UserRepository) *AuthService {
 }
 
 // Register mendaftarkan user baru
-func (s *AuthService) Register(req dto.UserRegisterRequest) (*dto.UserResponse, error) {
+func (s *AuthService) Register(ctx context.Context, req dto.UserRegisterRequest) (*dto.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
+
 	// Business Rule 1: Check if username already exists
-	existsUsername, err := s.userRepo.ExistsByUsername(req.Username)
+	existsUsername, err := s.userRepo.ExistsByUsername(ctx, req.Username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check username: %w", err)
 	}
@@ -43,7 +49,7 @@ func (s *AuthService) Register(req dto.UserRegisterRequest) (*dto.UserResponse,
 	}
 
 	// Business Rule 2: Check if email already exists
-	existsEmail, err := s.userRepo.ExistsByEmail(req.Email)
+	existsEmail, err := s.userRepo.ExistsByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check email: %w", err)
 	}
@@ -66,7 +72,7 @@ func (s *AuthService) Register(req dto.UserRegisterRequest) (*dto.UserResponse,
 	}
 
 	// Save to database via repository
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -75,18 +81,23 @@ func (s *AuthService) Register(req dto.UserRegisterRequest) (*dto.UserResponse,
 }
 
 // Login melakukan autentikasi user dan mengembalikan JWT token
-func (s *AuthService) Login(req dto.UserLoginRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Login(ctx context.Context, req dto.UserLoginRequest) (*dto.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
 	// Find user by username
-	user, err := s.userRepo.FindByUsername(req.Username)
+	user, err := s.userRepo.FindByUsername(ctx, req.Username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
+		metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
 		return nil, ErrInvalidCredentials
 	}
 
 	// Verify password
 	if !utils.CheckPassword(req.Password, user.Password) {
+		metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
 		return nil, ErrInvalidCredentials
 	}
 
@@ -96,6 +107,8 @@ func (s *AuthService) Login(req dto.UserLoginRequest) (*dto.AuthResponse, error)
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
+	metrics.Logins.WithLabelValues(metrics.LoginSucceeded).Inc()
+
 	// Return auth response with token and user data
 	return &dto.AuthResponse{
 		Token: token,
@@ -104,8 +117,11 @@ func (s *AuthService) Login(req dto.UserLoginRequest) (*dto.AuthResponse, error)
 }
 
 // GetProfile mendapatkan profile user berdasarkan ID
-func (s *AuthService) GetProfile(userID uint) (*dto.UserResponse, error) {
-	user, err := s.userRepo.FindByID(userID)
+func (s *AuthService) GetProfile(ctx context.Context, userID uint) (*dto.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.GetProfile")
+	defer span.End()
+
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
@@ -116,10 +132,30 @@ func (s *AuthService) GetProfile(userID uint) (*dto.UserResponse, error) {
 	return s.toUserResponse(user), nil
 }
 
+// GetUsersByIDs mendapatkan banyak user sekaligus, dipakai oleh dataloader GraphQL
+func (s *AuthService) GetUsersByIDs(ctx context.Context, ids []uint) (map[uint]*dto.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.GetUsersByIDs")
+	defer span.End()
+
+	users, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+
+	result := make(map[uint]*dto.UserResponse, len(users))
+	for i := range users {
+		result[users[i].ID] = s.toUserResponse(&users[i])
+	}
+	return result, nil
+}
+
 // UpdateProfile mengupdate profile user
-func (s *AuthService) UpdateProfile(userID uint, req dto.UserUpdateRequest) (*dto.UserResponse, error) {
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uint, req dto.UserUpdateRequest) (*dto.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.UpdateProfile")
+	defer span.End()
+
 	// Find existing user
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
@@ -129,7 +165,7 @@ func (s *AuthService) UpdateProfile(userID uint, req dto.UserUpdateRequest) (*dt
 
 	// Business Rule: Check if email already used by another user
 	if req.Email != "" && req.Email != user.Email {
-		existingUser, err := s.userRepo.FindByEmail(req.Email)
+		existingUser, err := s.userRepo.FindByEmail(ctx, req.Email)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check email: %w", err)
 		}
@@ -145,7 +181,7 @@ func (s *AuthService) UpdateProfile(userID uint, req dto.UserUpdateRequest) (*dt
 	}
 
 	// Save changes
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrShareNotFound is returned when removing a collaborator the board is not shared with
+	ErrShareNotFound = errors.New("board is not shared with this user")
+	// ErrShareWithSelf is returned when a user tries to share a board with themselves
+	ErrShareWithSelf = errors.New("cannot share a board with yourself")
+)
+
+// BoardShareService manages board collaborators. A collaborator can follow
+// the owner's board and todos live (WebSocket rooms, SSE and the gRPC watch
+// stream); changing todos stays limited to the owner.
+type BoardShareService struct {
+	boardRepo *repository.BoardRepository
+	userRepo  *repository.UserRepository
+	todoRepo  *repository.TodoRepository
+	onRevoke  []func(ownerID, userID uint)
+}
+
+// NewBoardShareService creates a new board share service instance
+func NewBoardShareService(boardRepo *repository.BoardRepository, userRepo *repository.UserRepository, todoRepo *repository.TodoRepository) *BoardShareService {
+	return &BoardShareService{
+		boardRepo: boardRepo,
+		userRepo:  userRepo,
+		todoRepo:  todoRepo,
+	}
+}
+
+// OnRevoke registers fn to run after a collaborator is removed, e.g. to
+// disconnect them from rooms they can no longer access. Register before serving requests.
+func (s *BoardShareService) OnRevoke(fn func(ownerID, userID uint)) {
+	s.onRevoke = append(s.onRevoke, fn)
+}
+
+// ShareBoard shares the owner's board with the user with the given username
+func (s *BoardShareService) ShareBoard(ctx context.Context, ownerID uint, username string) (*model.BoardShare, error) {
+	ctx, span := tracing.Start(ctx, "BoardShareService.ShareBoard")
+	defer span.End()
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.ID == ownerID {
+		return nil, ErrShareWithSelf
+	}
+
+	share := &model.BoardShare{OwnerID: ownerID, UserID: user.ID}
+	if err := s.boardRepo.CreateShare(ctx, share); err != nil {
+		return nil, err
+	}
+	share.User = *user
+	return share, nil
+}
+
+// UnshareBoard removes a collaborator from the owner's board
+func (s *BoardShareService) UnshareBoard(ctx context.Context, ownerID, userID uint) error {
+	ctx, span := tracing.Start(ctx, "BoardShareService.UnshareBoard")
+	defer span.End()
+
+	deleted, err := s.boardRepo.DeleteShare(ctx, ownerID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrShareNotFound
+	}
+
+	for _, fn := range s.onRevoke {
+		fn(ownerID, userID)
+	}
+	return nil
+}
+
+// GetShares lists the collaborators of the owner's board
+func (s *BoardShareService) GetShares(ctx context.Context, ownerID uint) ([]model.BoardShare, error) {
+	ctx, span := tracing.Start(ctx, "BoardShareService.GetShares")
+	defer span.End()
+
+	return s.boardRepo.FindSharesByOwnerID(ctx, ownerID)
+}
+
+// CanView reports whether userID may follow the board and todos of ownerID:
+// the owner and every collaborator may
+func (s *BoardShareService) CanView(ctx context.Context, ownerID, userID uint) (bool, error) {
+	if ownerID == userID {
+		return true, nil
+	}
+	return s.boardRepo.IsShared(ctx, ownerID, userID)
+}
+
+// VisibleBoards lists the owners whose board userID may follow: the user's
+// own board first, then every board shared with them. Streams subscribe to
+// these boards and check CanView again per event, so removing a collaborator
+// takes effect on streams that are already open.
+func (s *BoardShareService) VisibleBoards(ctx context.Context, userID uint) ([]uint, error) {
+	ownerIDs, err := s.boardRepo.FindOwnerIDsSharedWith(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]uint{userID}, ownerIDs...), nil
+}
+
+// CanViewTodo reports whether userID may follow a todo: its owner and the
+// owner's collaborators may. A missing todo is reported as not viewable.
+func (s *BoardShareService) CanViewTodo(ctx context.Context, todoID, userID uint) (bool, error) {
+	todo, err := s.todoRepo.FindByID(ctx, todoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return s.CanView(ctx, todo.UserID, userID)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
+)
+
+var (
+	// ErrIdempotencyKeyReused ketika Idempotency-Key dipakai ulang untuk request yang berbeda
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+	// ErrIdempotencyInProgress ketika request dengan key yang sama masih diproses
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+const (
+	// idempotencyLockTimeout batas waktu reservasi yang belum selesai dianggap
+	// ditinggalkan (misalnya server crash) sehingga key boleh dipakai lagi
+	idempotencyLockTimeout = time.Minute
+	// idempotencyCleanupInterval interval penghapusan key yang sudah kedaluwarsa
+	idempotencyCleanupInterval = time.Hour
+)
+
+// IdempotencyService stores responses of mutating requests so retries that
+// carry the same Idempotency-Key are replayed instead of executed twice
+type IdempotencyService struct {
+	idempotencyRepo *repository.IdempotencyRepository
+	ttl             time.Duration
+}
+
+// NewIdempotencyService creates a new idempotency service instance
+func NewIdempotencyService(idempotencyRepo *repository.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+	}
+}
+
+// Begin reserves a key for a request. The returned record is either a fresh
+// reservation owned by the caller, or a completed record whose response
+// must be replayed (check record.Completed()).
+func (s *IdempotencyService) Begin(ctx context.Context, userID uint, key, method, path, fingerprint string) (*model.IdempotencyKey, error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Begin")
+	defer span.End()
+
+	// Maksimal dua kali: percobaan kedua setelah key lama yang kedaluwarsa dihapus
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		record := &model.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      method,
+			Path:        path,
+			Fingerprint: fingerprint,
+			ExpiresAt:   now.Add(s.ttl),
+		}
+
+		reserved, err := s.idempotencyRepo.Reserve(ctx, record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if reserved {
+			return record, nil
+		}
+
+		existing, err := s.idempotencyRepo.FindByKey(ctx, userID, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find idempotency key: %w", err)
+		}
+		if existing == nil {
+			continue // Baru saja dihapus oleh request lain, coba reservasi lagi
+		}
+
+		expired := !existing.ExpiresAt.After(now)
+		abandoned := !existing.Completed() && existing.CreatedAt.Add(idempotencyLockTimeout).Before(now)
+		if expired || abandoned {
+			if err := s.idempotencyRepo.Delete(ctx, existing.ID); err != nil {
+				return nil, fmt.Errorf("failed to delete idempotency key: %w", err)
+			}
+			continue
+		}
+
+		// Business Rule: key hanya berlaku untuk request yang identik
+		if existing.Fingerprint != fingerprint {
+			return nil, ErrIdempotencyKeyReused
+		}
+		if !existing.Completed() {
+			return nil, ErrIdempotencyInProgress
+		}
+		return existing, nil
+	}
+
+	return nil, ErrIdempotencyInProgress
+}
+
+// Complete stores the response of a reserved request. Server errors are not
+// stored so the client can retry the request with the same key.
+func (s *IdempotencyService) Complete(ctx context.Context, record *model.IdempotencyKey, statusCode int, contentType string, body []byte) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Complete")
+	defer span.End()
+
+	if statusCode >= 500 {
+		if err := s.idempotencyRepo.Delete(ctx, record.ID); err != nil {
+			return fmt.Errorf("failed to release idempotency key: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.idempotencyRepo.SaveResponse(ctx, record.ID, statusCode, contentType, body); err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+	return nil
+}
+
+// RunCleanup deletes expired keys periodically until ctx is cancelled
+func (s *IdempotencyService) RunCleanup(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.idempotencyRepo.DeleteExpired(ctx, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, "idempotency: failed to delete expired keys", "error", err)
+		} else if deleted > 0 {
+			slog.InfoContext(ctx, "idempotency: deleted expired keys", "count", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSyncToken is returned when the since token cannot be decoded
+var ErrInvalidSyncToken = errors.New("invalid sync token")
+
+// defaultSyncLimit jumlah perubahan per halaman jika limit tidak diisi
+const defaultSyncLimit = 500
+
+// syncSafetyLag seberapa jauh ke belakang halaman terakhir sebuah pull
+// mengulang perubahan. updated_at diisi saat statement jalan, bukan saat
+// commit, sehingga transaksi yang commit belakangan (atau instance dengan jam
+// sedikit berbeda) bisa menulis waktu perubahan di belakang cursor yang sudah
+// dikirim ke client. Perubahan dalam jendela ini dikirim ulang pada pull
+// berikutnya; client menimpanya berdasarkan id dan version.
+const syncSafetyLag = time.Minute
+
+// Status hasil mutation sync
+const (
+	SyncStatusApplied  = "applied"
+	SyncStatusConflict = "conflict"
+	SyncStatusRejected = "rejected"
+)
+
+// SyncService handles offline-first delta sync for mobile clients
+type SyncService struct {
+	todoRepo    *repository.TodoRepository
+	todoService *TodoService
+	safetyLag   time.Duration
+}
+
+// NewSyncService creates a new sync service instance
+func NewSyncService(todoRepo *repository.TodoRepository, todoService *TodoService) *SyncService {
+	return &SyncService{
+		todoRepo:    todoRepo,
+		todoService: todoService,
+		safetyLag:   syncSafetyLag,
+	}
+}
+
+// Pull returns todos changed after the since token, including tombstones for
+// deleted todos. An empty token returns everything from the beginning. The
+// token of the last page never points past now minus the safety lag, so the
+// next pull repeats the most recent changes instead of missing late commits.
+func (s *SyncService) Pull(ctx context.Context, userID uint, since string, limit int) (*dto.SyncPullResponse, error) {
+	ctx, span := tracing.Start(ctx, "SyncService.Pull")
+	defer span.End()
+
+	// A pull right after a push must see the pushed changes, so sync skips the replicas
+	ctx = database.WithPrimary(ctx)
+
+	changedAt, afterID, err := decodeSyncToken(since)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultSyncLimit
+	}
+
+	// Ambil satu baris lebih untuk mengetahui masih ada halaman berikutnya
+	todos, err := s.todoRepo.FindChangedSince(ctx, userID, changedAt, afterID, true, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(todos) > limit
+	if hasMore {
+		todos = todos[:limit]
+	}
+
+	var live []*model.Todo
+	for i := range todos {
+		if !todos[i].DeletedAt.Valid {
+			live = append(live, &todos[i])
+		}
+	}
+	if err := s.todoRepo.LoadDependencies(ctx, live...); err != nil {
+		return nil, err
+	}
+
+	response := &dto.SyncPullResponse{
+		Changes:   make([]dto.SyncChange, len(todos)),
+		NextToken: since,
+		HasMore:   hasMore,
+	}
+	for i := range todos {
+		todo := &todos[i]
+		change := dto.SyncChange{ID: todo.ID}
+		if todo.DeletedAt.Valid {
+			change.Deleted = true
+			change.DeletedAt = &todo.DeletedAt.Time
+		} else {
+			todoResponse := ToTodoResponse(todo)
+			change.Todo = &todoResponse
+		}
+		response.Changes[i] = change
+	}
+
+	if len(todos) > 0 {
+		last := &todos[len(todos)-1]
+		response.NextToken = encodeSyncToken(changeTime(last), last.ID)
+		// Halaman tengah tetap maju agar pagination selesai; halaman terakhir
+		// mundur ke horizon, walaupun halaman sebelumnya sudah melewatinya,
+		// supaya pull berikutnya membaca ulang seluruh jendela lag
+		horizon := time.Now().Add(-s.safetyLag)
+		if !hasMore && changeTime(last).After(horizon) {
+			response.NextToken = encodeSyncToken(horizon, 0)
+		}
+	} else if since == "" {
+		response.NextToken = encodeSyncToken(time.Time{}, 0)
+	}
+
+	return response, nil
+}
+
+// Push applies a batch of client mutations in order and reports the outcome
+// of each one. Mutations never abort the batch: failures are reported as
+// "conflict" or "rejected" results.
+func (s *SyncService) Push(ctx context.Context, userID uint, req dto.SyncPushRequest) *dto.SyncPushResponse {
+	ctx, span := tracing.Start(ctx, "SyncService.Push")
+	defer span.End()
+	ctx = database.WithPrimary(ctx)
+
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = "version"
+	}
+
+	response := &dto.SyncPushResponse{
+		Results: make([]dto.SyncMutationResult, len(req.Mutations)),
+	}
+	for i, mutation := range req.Mutations {
+		result := s.apply(ctx, userID, strategy, mutation)
+		result.Index = i
+		result.ClientRef = mutation.ClientRef
+		response.Results[i] = result
+	}
+
+	return response
+}
+
+// apply applies a single mutation
+func (s *SyncService) apply(ctx context.Context, userID uint, strategy string, m dto.SyncMutation) dto.SyncMutationResult {
+	if m.Op == "create" {
+		return s.applyCreate(ctx, userID, m)
+	}
+
+	if m.ID == 0 {
+		return rejected("id is required for " + m.Op)
+	}
+
+	current, err := s.todoRepo.FindByIDUnscoped(ctx, m.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return rejected(ErrTodoNotFound.Error())
+		}
+		return internalError(ctx, err)
+	}
+	if current.UserID != userID {
+		return rejected(ErrTodoNotFound.Error())
+	}
+
+	if current.DeletedAt.Valid {
+		if m.Op == "delete" {
+			// Delete bersifat idempotent
+			return dto.SyncMutationResult{Status: SyncStatusApplied}
+		}
+		return dto.SyncMutationResult{Status: SyncStatusConflict, Error: "todo was deleted"}
+	}
+
+	if result, ok := s.checkConflict(ctx, strategy, m, current); !ok {
+		return result
+	}
+
+	// The write is conditional on the version checked above, so a change
+	// committed in between is reported as a conflict instead of being overwritten
+	switch m.Op {
+	case "update":
+		todo, err := s.todoService.updateTodo(ctx, m.ID, userID, m.Data, current.Version)
+		if err != nil {
+			return s.writeFailed(ctx, m.ID, err)
+		}
+		todoResponse := ToTodoResponse(todo)
+		return dto.SyncMutationResult{Status: SyncStatusApplied, Todo: &todoResponse}
+
+	default: // delete
+		if err := s.todoService.deleteTodo(ctx, m.ID, userID, current.Version); err != nil {
+			return s.writeFailed(ctx, m.ID, err)
+		}
+		return dto.SyncMutationResult{Status: SyncStatusApplied}
+	}
+}
+
+// writeFailed turns a failed update/delete into a result, reporting a lost
+// version race as a conflict with the latest server state
+func (s *SyncService) writeFailed(ctx context.Context, todoID uint, err error) dto.SyncMutationResult {
+	if !errors.Is(err, ErrTodoModified) {
+		return rejected(err.Error())
+	}
+	latest, findErr := s.todoRepo.FindByIDUnscoped(ctx, todoID)
+	if findErr != nil {
+		return internalError(ctx, findErr)
+	}
+	if latest.DeletedAt.Valid {
+		return dto.SyncMutationResult{Status: SyncStatusConflict, Error: "todo was deleted"}
+	}
+	return s.conflict(ctx, latest, fmt.Sprintf("version mismatch: server is at %d", latest.Version))
+}
+
+// applyCreate creates a todo from a create mutation
+func (s *SyncService) applyCreate(ctx context.Context, userID uint, m dto.SyncMutation) dto.SyncMutationResult {
+	if m.Data.Title == nil || m.Data.Status == nil || m.Data.Priority == nil {
+		return rejected("title, status and priority are required for create")
+	}
+
+	req := dto.CreateTodoRequest{
+		Title:    *m.Data.Title,
+		Status:   *m.Data.Status,
+		Priority: *m.Data.Priority,
+	}
+	if m.Data.Description != nil {
+		req.Description = *m.Data.Description
+	}
+	if m.Data.DueDate != nil {
+		req.DueDate = *m.Data.DueDate
+	}
+	if m.Data.BlockedBy != nil {
+		req.BlockedBy = *m.Data.BlockedBy
+	}
+
+	todo, err := s.todoService.CreateTodo(ctx, userID, req)
+	if err != nil {
+		return rejected(err.Error())
+	}
+
+	todoResponse := ToTodoResponse(todo)
+	return dto.SyncMutationResult{Status: SyncStatusApplied, Todo: &todoResponse}
+}
+
+// checkConflict decides whether a mutation may overwrite the current server state.
+// It returns ok = false with a conflict/rejected result when it may not.
+func (s *SyncService) checkConflict(ctx context.Context, strategy string, m dto.SyncMutation, current *model.Todo) (dto.SyncMutationResult, bool) {
+	switch strategy {
+	case "lww":
+		if m.ClientUpdatedAt == nil {
+			return rejected("client_updated_at is required for lww strategy"), false
+		}
+		if m.ClientUpdatedAt.Before(current.UpdatedAt) {
+			return s.conflict(ctx, current, "server has a newer change"), false
+		}
+	default: // version
+		if m.BaseVersion == nil {
+			return rejected("base_version is required for version strategy"), false
+		}
+		if *m.BaseVersion != current.Version {
+			return s.conflict(ctx, current, fmt.Sprintf("version mismatch: server is at %d", current.Version)), false
+		}
+	}
+	return dto.SyncMutationResult{}, true
+}
+
+// conflict builds a conflict result carrying the current server todo
+func (s *SyncService) conflict(ctx context.Context, current *model.Todo, reason string) dto.SyncMutationResult {
+	if err := s.todoRepo.LoadDependencies(ctx, current); err != nil {
+		slog.ErrorContext(ctx, "sync: failed to load dependencies", "todo_id", current.ID, "error", err)
+	}
+	todoResponse := ToTodoResponse(current)
+	return dto.SyncMutationResult{Status: SyncStatusConflict, Error: reason, Todo: &todoResponse}
+}
+
+// Helper functions
+
+func rejected(reason string) dto.SyncMutationResult {
+	return dto.SyncMutationResult{Status: SyncStatusRejected, Error: reason}
+}
+
+func internalError(ctx context.Context, err error) dto.SyncMutationResult {
+	slog.ErrorContext(ctx, "sync: mutation failed", "error", err)
+	return rejected("internal error")
+}
+
+// changeTime is when a todo last changed: deletion time for tombstones, otherwise updated_at
+func changeTime(todo *model.Todo) time.Time {
+	if todo.DeletedAt.Valid {
+		return todo.DeletedAt.Time
+	}
+	return todo.UpdatedAt
+}
+
+// encodeSyncToken encodes a (change time, id) cursor as an opaque token
+func encodeSyncToken(changedAt time.Time, id uint) string {
+	raw := fmt.Sprintf("v1.%d.%d", changedAt.UnixNano(), id)
+	if changedAt.IsZero() {
+		raw = fmt.Sprintf("v1.0.%d", id)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeSyncToken decodes a token produced by encodeSyncToken; "" means from the beginning
+func decodeSyncToken(token string) (time.Time, uint, error) {
+	if token == "" {
+		return time.Time{}, 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidSyncToken
+	}
+
+	var nanos int64
+	var id uint
+	if _, err := fmt.Sscanf(string(raw), "v1.%d.%d", &nanos, &id); err != nil {
+		return time.Time{}, 0, ErrInvalidSyncToken
+	}
+	if nanos == 0 {
+		return time.Time{}, id, nil
+	}
+	return time.Unix(0, nanos), id, nil
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestSyncService(t *testing.T) (*SyncService, *gorm.DB) {
+	t.Helper()
+	todoService, db, _ := newTestTodoService(t, TodoSettings{BlockCompletion: true})
+	s := NewSyncService(todoService.todoRepo, todoService)
+	// Tanpa lag agar test bisa memastikan setiap perubahan dikirim tepat satu kali
+	s.safetyLag = 0
+	return s, db
+}
+
+func createTestTodo(t *testing.T, s *SyncService, userID uint, title string, blockedBy ...uint) *model.Todo {
+	t.Helper()
+	todo, err := s.todoService.CreateTodo(context.Background(), userID, dto.CreateTodoRequest{
+		Title: title, Status: "pending", Priority: "medium", BlockedBy: blockedBy,
+	})
+	require.NoError(t, err)
+	return todo
+}
+
+func TestSyncPullPaginatesWithTombstones(t *testing.T) {
+	s, db := newTestSyncService(t)
+	user := newTestUser(t, db, "puller")
+	ctx := context.Background()
+
+	var ids []uint
+	for _, title := range []string{"a", "b", "c", "d", "e"} {
+		ids = append(ids, createTestTodo(t, s, user.ID, title).ID)
+	}
+	require.NoError(t, s.todoService.DeleteTodo(ctx, ids[1], user.ID))
+
+	// Halaman berukuran 2 sampai habis, setiap todo muncul tepat satu kali
+	seen := map[uint]dto.SyncChange{}
+	token := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5, "pagination does not terminate")
+		page, err := s.Pull(ctx, user.ID, token, 2)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(page.Changes), 2)
+		for _, change := range page.Changes {
+			assert.NotContains(t, seen, change.ID, "todo %d pulled twice", change.ID)
+			seen[change.ID] = change
+		}
+		token = page.NextToken
+		if !page.HasMore {
+			break
+		}
+	}
+	require.Len(t, seen, len(ids))
+
+	tombstone := seen[ids[1]]
+	assert.True(t, tombstone.Deleted)
+	assert.NotNil(t, tombstone.DeletedAt)
+	assert.Nil(t, tombstone.Todo)
+	require.NotNil(t, seen[ids[0]].Todo)
+	assert.Equal(t, "a", seen[ids[0]].Todo.Title)
+
+	// Token terakhir hanya mengembalikan perubahan sesudahnya
+	page, err := s.Pull(ctx, user.ID, token, 2)
+	require.NoError(t, err)
+	assert.Empty(t, page.Changes)
+	assert.Equal(t, token, page.NextToken)
+
+	time.Sleep(5 * time.Millisecond)
+	title := "c2"
+	_, err = s.todoService.UpdateTodo(ctx, ids[2], user.ID, dto.UpdateTodoRequest{Title: &title})
+	require.NoError(t, err)
+	page, err = s.Pull(ctx, user.ID, token, 2)
+	require.NoError(t, err)
+	require.Len(t, page.Changes, 1)
+	assert.Equal(t, ids[2], page.Changes[0].ID)
+
+	_, err = s.Pull(ctx, user.ID, "not-a-token", 2)
+	assert.ErrorIs(t, err, ErrInvalidSyncToken)
+}
+
+// Transaksi yang commit belakangan bisa menulis updated_at di belakang cursor
+// yang sudah dikirim; jendela lag membuat pull berikutnya tetap menemukannya
+func TestSyncPullRepeatsSafetyLagWindow(t *testing.T) {
+	s, db := newTestSyncService(t)
+	s.safetyLag = time.Minute
+	user := newTestUser(t, db, "laggard")
+	ctx := context.Background()
+
+	var ids []uint
+	for _, title := range []string{"a", "b", "c"} {
+		ids = append(ids, createTestTodo(t, s, user.ID, title).ID)
+	}
+
+	// Pagination tetap selesai walaupun semua perubahan ada di dalam jendela lag
+	token := ""
+	seen := map[uint]bool{}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5, "pagination does not terminate")
+		page, err := s.Pull(ctx, user.ID, token, 2)
+		require.NoError(t, err)
+		for _, change := range page.Changes {
+			seen[change.ID] = true
+		}
+		token = page.NextToken
+		if !page.HasMore {
+			break
+		}
+	}
+	assert.Len(t, seen, len(ids))
+
+	// Todo dengan updated_at 10 detik lalu, seolah transaksinya baru commit
+	late := &model.Todo{Title: "late", Status: "pending", Priority: "low", Position: 9, Version: 1, UserID: user.ID}
+	require.NoError(t, db.Create(late).Error)
+	require.NoError(t, db.Model(late).UpdateColumn("updated_at", time.Now().Add(-10*time.Second)).Error)
+
+	page, err := s.Pull(ctx, user.ID, token, 10)
+	require.NoError(t, err)
+	pulled := map[uint]bool{}
+	for _, change := range page.Changes {
+		pulled[change.ID] = true
+	}
+	assert.True(t, pulled[late.ID], "late commit is not missed")
+	assert.True(t, pulled[ids[2]], "changes inside the lag window are sent again")
+	assert.False(t, page.HasMore)
+
+	// Perubahan di luar jendela lag tidak dikirim ulang
+	require.NoError(t, db.Model(&model.Todo{}).Where("user_id = ?", user.ID).UpdateColumn("updated_at", time.Now().Add(-time.Hour)).Error)
+	page, err = s.Pull(ctx, user.ID, "", 10)
+	require.NoError(t, err)
+	require.Len(t, page.Changes, len(ids)+1)
+	page, err = s.Pull(ctx, user.ID, page.NextToken, 10)
+	require.NoError(t, err)
+	assert.Empty(t, page.Changes)
+}
+
+func TestSyncPushCreateValidatesTitle(t *testing.T) {
+	s, db := newTestSyncService(t)
+	user := newTestUser(t, db, "creator")
+	empty, tooLong := "", strings.Repeat("a", MaxTitleLength+1)
+	status, priority := "pending", "low"
+
+	response := s.Push(context.Background(), user.ID, dto.SyncPushRequest{Mutations: []dto.SyncMutation{
+		{Op: "create", Data: dto.UpdateTodoRequest{Title: &empty, Status: &status, Priority: &priority}},
+		{Op: "create", Data: dto.UpdateTodoRequest{Title: &tooLong, Status: &status, Priority: &priority}},
+	}})
+	assert.Equal(t, SyncStatusRejected, response.Results[0].Status)
+	assert.Equal(t, ErrTitleRequired.Error(), response.Results[0].Error)
+	assert.Equal(t, SyncStatusRejected, response.Results[1].Status)
+	assert.Equal(t, ErrTitleTooLong.Error(), response.Results[1].Error)
+
+	var count int64
+	require.NoError(t, db.Model(&model.Todo{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestSyncPushVersionStrategy(t *testing.T) {
+	s, db := newTestSyncService(t)
+	user := newTestUser(t, db, "versioned")
+	todo := createTestTodo(t, s, user.ID, "original")
+	ctx := context.Background()
+
+	title := "from client"
+	stale := todo.Version - 1
+	response := s.Push(ctx, user.ID, dto.SyncPushRequest{Mutations: []dto.SyncMutation{
+		{Op: "update", ID: todo.ID, Data: dto.UpdateTodoRequest{Title: &title}},
+		{Op: "update", ID: todo.ID, BaseVersion: &stale, Data: dto.UpdateTodoRequest{Title: &title}},
+		{Op: "update", ID: todo.ID, BaseVersion: &todo.Version, Data: dto.UpdateTodoRequest{Title: &title}},
+		{Op: "update", ID: todo.ID, BaseVersion: &todo.Version, Data: dto.UpdateTodoRequest{Title: &title}},
+		{Op: "delete", ID: todo.ID, BaseVersion: &todo.Version},
+	}})
+
+	results := response.Results
+	assert.Equal(t, SyncStatusRejected, results[0].Status)
+	assert.Equal(t, SyncStatusConflict, results[1].Status)
+	require.NotNil(t, results[1].Todo)
+	assert.Equal(t, "original", results[1].Todo.Title)
+
+	assert.Equal(t, SyncStatusApplied, results[2].Status)
+	require.NotNil(t, results[2].Todo)
+	assert.Equal(t, todo.Version+1, results[2].Todo.Version)
+
+	// Mutation berikutnya dengan base_version lama kalah dari yang baru diterapkan
+	assert.Equal(t, SyncStatusConflict, results[3].Status)
+	assert.Equal(t, SyncStatusConflict, results[4].Status)
+	assert.Equal(t, "from client", results[4].Todo.Title)
+
+	current := todo.Version + 1
+	response = s.Push(ctx, user.ID, dto.SyncPushRequest{Mutations: []dto.SyncMutation{
+		{Op: "delete", ID: todo.ID, BaseVersion: &current},
+		{Op: "delete", ID: todo.ID, BaseVersion: &current},
+		{Op: "update", ID: todo.ID, BaseVersion: &current, Data: dto.UpdateTodoRequest{Title: &title}},
+	}})
+	assert.Equal(t, SyncStatusApplied, response.Results[0].Status)
+	assert.Equal(t, SyncStatusApplied, response.Results[1].Status, "delete is idempotent")
+	assert.Equal(t, SyncStatusConflict, response.Results[2].Status)
+	assert.Equal(t, "todo was deleted", response.Results[2].Error)
+}
+
+func TestSyncPushLastWriteWinsStrategy(t *testing.T) {
+	s, db := newTestSyncService(t)
+	user := newTestUser(t, db, "lww")
+	todo := createTestTodo(t, s, user.ID, "original")
+	ctx := context.Background()
+
+	title := "from client"
+	older := todo.UpdatedAt.Add(-time.Minute)
+	newer := time.Now().Add(time.Minute)
+	response := s.Push(ctx, user.ID, dto.SyncPushRequest{Strategy: "lww", Mutations: []dto.SyncMutation{
+		{Op: "update", ID: todo.ID, Data: dto.UpdateTodoRequest{Title: &title}},
+		{Op: "update", ID: todo.ID, ClientUpdatedAt: &older, Data: dto.UpdateTodoRequest{Title: &title}},
+		{Op: "update", ID: todo.ID, ClientUpdatedAt: &newer, Data: dto.UpdateTodoRequest{Title: &title}},
+	}})
+
+	assert.Equal(t, SyncStatusRejected, response.Results[0].Status)
+	assert.Equal(t, SyncStatusConflict, response.Results[1].Status)
+	assert.Equal(t, "original", response.Results[1].Todo.Title)
+	assert.Equal(t, SyncStatusApplied, response.Results[2].Status)
+	assert.Equal(t, "from client", response.Results[2].Todo.Title)
+}
+
+// Perubahan yang masuk di antara pengecekan konflik dan penulisan tidak
+// boleh tertimpa diam-diam
+func TestConcurrentWriteIsDetected(t *testing.T) {
+	s, db := newTestSyncService(t)
+	user := newTestUser(t, db, "racer")
+	todo := createTestTodo(t, s, user.ID, "original")
+	ctx := context.Background()
+
+	// Dua request membaca versi yang sama, yang kedua menulis terakhir
+	first, err := s.todoRepo.FindByID(ctx, todo.ID)
+	require.NoError(t, err)
+	second, err := s.todoRepo.FindByID(ctx, todo.ID)
+	require.NoError(t, err)
+
+	first.Title = "first"
+	require.NoError(t, s.todoRepo.Update(ctx, first))
+	second.Title = "second"
+	assert.ErrorIs(t, s.todoRepo.Update(ctx, second), repository.ErrStaleVersion)
+	assert.Equal(t, todo.Version, second.Version)
+	assert.ErrorIs(t, s.todoRepo.Delete(ctx, second), repository.ErrStaleVersion)
+
+	stored, err := s.todoRepo.FindByID(ctx, todo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "first", stored.Title)
+	assert.Equal(t, todo.Version+1, stored.Version)
+
+	// Sync yang sudah lolos pengecekan dengan versi lama melaporkan konflik
+	title := "from client"
+	_, err = s.todoService.updateTodo(ctx, todo.ID, user.ID, dto.UpdateTodoRequest{Title: &title}, todo.Version)
+	assert.ErrorIs(t, err, ErrTodoModified)
+	result := s.writeFailed(ctx, todo.ID, err)
+	assert.Equal(t, SyncStatusConflict, result.Status)
+	assert.Equal(t, "first", result.Todo.Title)
+}
+
+func TestDependencyChangeShowsUpInPull(t *testing.T) {
+	s, db := newTestSyncService(t)
+	user := newTestUser(t, db, "deps")
+	blocker := createTestTodo(t, s, user.ID, "blocker")
+	todo := createTestTodo(t, s, user.ID, "todo")
+	ctx := context.Background()
+
+	page, err := s.Pull(ctx, user.ID, "", 10)
+	require.NoError(t, err)
+	token := page.NextToken
+
+	time.Sleep(5 * time.Millisecond)
+	blockedBy := []uint{blocker.ID}
+	updated, err := s.todoService.UpdateTodo(ctx, todo.ID, user.ID, dto.UpdateTodoRequest{BlockedBy: &blockedBy})
+	require.NoError(t, err)
+	assert.Equal(t, todo.Version+1, updated.Version)
+
+	// Kedua sisi edge berubah: blocked_by pada todo, blocking pada blocker
+	page, err = s.Pull(ctx, user.ID, token, 10)
+	require.NoError(t, err)
+	changed := map[uint]*dto.TodoResponse{}
+	for _, change := range page.Changes {
+		changed[change.ID] = change.Todo
+	}
+	require.Contains(t, changed, todo.ID)
+	require.Contains(t, changed, blocker.ID)
+	assert.Equal(t, []uint{blocker.ID}, changed[todo.ID].BlockedBy)
+	assert.Equal(t, []uint{todo.ID}, changed[blocker.ID].Blocking)
+	assert.Equal(t, blocker.Version+1, changed[blocker.ID].Version)
+
+	// Menghapus todo ikut menandai todo di sisi lain edge
+	time.Sleep(5 * time.Millisecond)
+	token = page.NextToken
+	require.NoError(t, s.todoService.DeleteTodo(ctx, todo.ID, user.ID))
+	page, err = s.Pull(ctx, user.ID, token, 10)
+	require.NoError(t, err)
+	require.Len(t, page.Changes, 2)
+	for _, change := range page.Changes {
+		if change.ID == blocker.ID {
+			assert.Empty(t, change.Todo.Blocking)
+		} else {
+			assert.True(t, change.Deleted)
+		}
+	}
+}
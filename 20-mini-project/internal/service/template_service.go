@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/metrics"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTemplateNotFound is returned when template is not found
+	ErrTemplateNotFound = errors.New("template not found")
+	// ErrUnauthorizedTemplateAccess is returned when user tries to access template they don't own
+	ErrUnauthorizedTemplateAccess = errors.New("unauthorized access to template")
+	// ErrInvalidStartDate is returned when start date is not in YYYY-MM-DD format
+	ErrInvalidStartDate = errors.New("invalid start date format, use YYYY-MM-DD")
+)
+
+// TemplateService handles todo template business logic
+type TemplateService struct {
+	templateRepo *repository.TemplateRepository
+	todoRepo     *repository.TodoRepository
+	events       event.Publisher
+	location     *time.Location
+	now          func() time.Time
+}
+
+// NewTemplateService creates a new template service instance. Start dates
+// are calendar dates in location, which also decides what "today" is.
+func NewTemplateService(templateRepo *repository.TemplateRepository, todoRepo *repository.TodoRepository, events event.Publisher, location *time.Location) *TemplateService {
+	return &TemplateService{
+		templateRepo: templateRepo,
+		todoRepo:     todoRepo,
+		events:       events,
+		location:     location,
+		now:          time.Now,
+	}
+}
+
+// CreateTemplate creates a new template for a user
+func (s *TemplateService) CreateTemplate(ctx context.Context, userID uint, req dto.CreateTemplateRequest) (*model.TodoTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.CreateTemplate")
+	defer span.End()
+
+	if !isValidPriority(req.Priority) {
+		return nil, ErrInvalidPriority
+	}
+
+	items, err := toTemplateItems(req.Checklist)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &model.TodoTemplate{
+		Title:         req.Title,
+		Description:   req.Description,
+		Priority:      req.Priority,
+		DueOffsetDays: req.DueOffsetDays,
+		UserID:        userID,
+		Items:         items,
+	}
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// GetTemplateByID retrieves a template by ID with authorization check
+func (s *TemplateService) GetTemplateByID(ctx context.Context, templateID, userID uint) (*model.TodoTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.GetTemplateByID")
+	defer span.End()
+
+	template, err := s.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+
+	if template.UserID != userID {
+		return nil, ErrUnauthorizedTemplateAccess
+	}
+
+	return template, nil
+}
+
+// GetUserTemplates retrieves all templates for a user
+func (s *TemplateService) GetUserTemplates(ctx context.Context, userID uint) ([]model.TodoTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.GetUserTemplates")
+	defer span.End()
+
+	return s.templateRepo.FindByUserID(ctx, userID)
+}
+
+// UpdateTemplate updates a template with authorization check
+func (s *TemplateService) UpdateTemplate(ctx context.Context, templateID, userID uint, req dto.UpdateTemplateRequest) (*model.TodoTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.UpdateTemplate")
+	defer span.End()
+
+	template, err := s.GetTemplateByID(ctx, templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		template.Title = *req.Title
+	}
+
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+
+	if req.Priority != nil {
+		if !isValidPriority(*req.Priority) {
+			return nil, ErrInvalidPriority
+		}
+		template.Priority = *req.Priority
+	}
+
+	if req.DueOffsetDays != nil {
+		template.DueOffsetDays = req.DueOffsetDays
+	}
+
+	replaceItems := req.Checklist != nil
+	if replaceItems {
+		items, err := toTemplateItems(*req.Checklist)
+		if err != nil {
+			return nil, err
+		}
+		template.Items = items
+	}
+
+	if err := s.templateRepo.Update(ctx, template, replaceItems); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// DeleteTemplate deletes a template with authorization check
+func (s *TemplateService) DeleteTemplate(ctx context.Context, templateID, userID uint) error {
+	ctx, span := tracing.Start(ctx, "TemplateService.DeleteTemplate")
+	defer span.End()
+
+	if _, err := s.GetTemplateByID(ctx, templateID, userID); err != nil {
+		return err
+	}
+
+	return s.templateRepo.Delete(ctx, templateID)
+}
+
+// InstantiateTemplate creates todos from a template: one todo for the template
+// itself and one for every checklist item. Due dates are computed by adding each
+// due offset to startDate (YYYY-MM-DD, defaults to today).
+func (s *TemplateService) InstantiateTemplate(ctx context.Context, templateID, userID uint, startDate string) ([]model.Todo, error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.InstantiateTemplate")
+	defer span.End()
+
+	template, err := s.GetTemplateByID(ctx, templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := startOfDay(s.now(), s.location)
+	if startDate != "" {
+		start, err = time.ParseInLocation("2006-01-02", startDate, s.location)
+		if err != nil {
+			return nil, ErrInvalidStartDate
+		}
+	}
+
+	// Created todos are appended to the bottom of the pending column
+	position, err := s.todoRepo.NextPosition(ctx, userID, "pending")
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]model.Todo, 0, len(template.Items)+1)
+	todos = append(todos, model.Todo{
+		Title:       template.Title,
+		Description: template.Description,
+		Status:      "pending",
+		Priority:    template.Priority,
+		Position:    position,
+		DueDate:     dueDateFromOffset(start, template.DueOffsetDays),
+		UserID:      userID,
+	})
+
+	for i, item := range template.Items {
+		todos = append(todos, model.Todo{
+			Title:       item.Title,
+			Description: item.Description,
+			Status:      "pending",
+			Priority:    item.Priority,
+			Position:    position + i + 1,
+			DueDate:     dueDateFromOffset(start, item.DueOffsetDays),
+			UserID:      userID,
+		})
+	}
+
+	if err := s.todoRepo.CreateBatch(ctx, todos); err != nil {
+		return nil, err
+	}
+	metrics.TodosCreated.Add(float64(len(todos)))
+
+	if s.events != nil {
+		for i := range todos {
+			s.events.Publish(event.New(event.TodoCreated, userID, todos[i].ID, ToTodoResponse(&todos[i])))
+		}
+	}
+
+	return todos, nil
+}
+
+// Helper functions
+
+func toTemplateItems(reqs []dto.TemplateItemRequest) ([]model.TodoTemplateItem, error) {
+	items := make([]model.TodoTemplateItem, len(reqs))
+	for i, req := range reqs {
+		priority := req.Priority
+		if priority == "" {
+			priority = "medium"
+		}
+		if !isValidPriority(priority) {
+			return nil, ErrInvalidPriority
+		}
+
+		items[i] = model.TodoTemplateItem{
+			Title:         req.Title,
+			Description:   req.Description,
+			Priority:      priority,
+			DueOffsetDays: req.DueOffsetDays,
+			Position:      i,
+		}
+	}
+	return items, nil
+}
+
+// startOfDay returns midnight of t's calendar date in loc
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+func dueDateFromOffset(start time.Time, offsetDays *int) *time.Time {
+	if offsetDays == nil {
+		return nil
+	}
+	dueDate := start.AddDate(0, 0, *offsetDays)
+	return &dueDate
+}
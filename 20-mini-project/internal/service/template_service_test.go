@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstantiateTemplateUsesConfiguredLocation(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	db := newTestDB(t)
+	user := newTestUser(t, db, "planner")
+	s := NewTemplateService(
+		repository.NewTemplateRepository(db),
+		repository.NewTodoRepository(database.NewCluster(db, database.Options{})),
+		&recordedEvents{},
+		jakarta,
+	)
+	// 20:30 UTC tanggal 1 sudah tanggal 2 jam 03:30 di Jakarta
+	s.now = func() time.Time { return time.Date(2026, 3, 1, 20, 30, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	zero, one := 0, 1
+	template, err := s.CreateTemplate(ctx, user.ID, dto.CreateTemplateRequest{
+		Title:         "release",
+		Priority:      "high",
+		DueOffsetDays: &one,
+		Checklist:     []dto.TemplateItemRequest{{Title: "tag", DueOffsetDays: &zero}},
+	})
+	require.NoError(t, err)
+
+	todos, err := s.InstantiateTemplate(ctx, template.ID, user.ID, "")
+	require.NoError(t, err)
+	require.Len(t, todos, 2)
+	assert.True(t, todos[0].DueDate.Equal(time.Date(2026, 3, 3, 0, 0, 0, 0, jakarta)), "got %s", todos[0].DueDate)
+	assert.True(t, todos[1].DueDate.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, jakarta)), "got %s", todos[1].DueDate)
+
+	// start_date eksplisit juga tanggal di zona waktu yang sama
+	todos, err = s.InstantiateTemplate(ctx, template.ID, user.ID, "2026-03-10")
+	require.NoError(t, err)
+	assert.True(t, todos[1].DueDate.Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, jakarta)), "got %s", todos[1].DueDate)
+
+	_, err = s.InstantiateTemplate(ctx, template.ID, user.ID, "10-03-2026")
+	assert.ErrorIs(t, err, ErrInvalidStartDate)
+}
@@ -1,40 +1,104 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/metrics"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
 	"gorm.io/gorm"
 )
 
+// MaxTitleLength is the longest todo title in characters, the size of todos.title
+const MaxTitleLength = 200
+
 var (
 	// ErrTodoNotFound is returned when todo is not found
 	ErrTodoNotFound = errors.New("todo not found")
 	// ErrUnauthorizedAccess is returned when user tries to access todo they don't own
 	ErrUnauthorizedAccess = errors.New("unauthorized access to todo")
+	// ErrTitleRequired is returned when a todo title is empty
+	ErrTitleRequired = errors.New("title is required")
+	// ErrTitleTooLong is returned when a todo title is longer than MaxTitleLength
+	ErrTitleTooLong = fmt.Errorf("title must be at most %d characters", MaxTitleLength)
 	// ErrInvalidStatus is returned when status value is invalid
 	ErrInvalidStatus = errors.New("invalid status value")
 	// ErrInvalidPriority is returned when priority value is invalid
 	ErrInvalidPriority = errors.New("invalid priority value")
+	// ErrInvalidDueDate is returned when due date is not in YYYY-MM-DD format
+	ErrInvalidDueDate = errors.New("invalid date format, use YYYY-MM-DD")
+	// ErrInvalidDependency is returned when a dependency does not exist or belongs to another user
+	ErrInvalidDependency = errors.New("dependency todo not found")
+	// ErrDependencyCycle is returned when a dependency would create a cycle
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	// ErrTodoBlocked is returned when completing a todo that still has open blockers
+	ErrTodoBlocked = errors.New("todo is blocked by unfinished todos")
+	// ErrWIPLimitReached is returned when moving a todo into a full in_progress column
+	ErrWIPLimitReached = errors.New("work-in-progress limit reached")
+	// ErrTodoModified is returned when a todo changed between reading and writing it
+	ErrTodoModified = errors.New("todo was modified by another request, reload and retry")
+	// ErrInvalidExpand is returned when ?expand names a relation that cannot be embedded
+	ErrInvalidExpand = errors.New("unsupported expand value")
 )
 
+// boardStatuses urutan kolom pada kanban board
+var boardStatuses = []string{"pending", "in_progress", "completed"}
+
+// BoardColumn is one status column of the kanban board
+type BoardColumn struct {
+	Status   string
+	WIPLimit int // 0 berarti tanpa batas
+	Todos    []model.Todo
+}
+
+// TodoSettings are the todo feature flags
+type TodoSettings struct {
+	// BlockCompletion rejects completing a todo while one of its blockers is open
+	BlockCompletion bool
+	// WIPLimit is the default in_progress limit per user, 0 means unlimited
+	WIPLimit int
+}
+
 // TodoService handles todo business logic
 type TodoService struct {
-	todoRepo *repository.TodoRepository
+	todoRepo  *repository.TodoRepository
+	boardRepo *repository.BoardRepository
+	events    event.Publisher
+	settings  func() TodoSettings // Dibaca per request, sehingga perubahan dari config reload langsung berlaku
 }
 
 // NewTodoService creates a new todo service instance
-func NewTodoService(todoRepo *repository.TodoRepository) *TodoService {
+func NewTodoService(todoRepo *repository.TodoRepository, boardRepo *repository.BoardRepository, events event.Publisher, settings func() TodoSettings) *TodoService {
 	return &TodoService{
-		todoRepo: todoRepo,
+		todoRepo:  todoRepo,
+		boardRepo: boardRepo,
+		events:    events,
+		settings:  settings,
 	}
 }
 
 // CreateTodo creates a new todo for a user
-func (s *TodoService) CreateTodo(userID uint, req dto.CreateTodoRequest) (*model.Todo, error) {
+func (s *TodoService) CreateTodo(ctx context.Context, userID uint, req dto.CreateTodoRequest) (*model.Todo, error) {
+	ctx, span := tracing.Start(ctx, "TodoService.CreateTodo")
+	defer span.End()
+
+	// Reads around a write must see that write, so they skip the replicas
+	ctx = database.WithPrimary(ctx)
+
+	if err := validateTitle(req.Title); err != nil {
+		return nil, err
+	}
+
 	// Validate status
 	if !isValidStatus(req.Status) {
 		return nil, ErrInvalidStatus
@@ -50,30 +114,66 @@ func (s *TodoService) CreateTodo(userID uint, req dto.CreateTodoRequest) (*model
 	if req.DueDate != "" {
 		parsedDate, err := time.Parse("2006-01-02", req.DueDate)
 		if err != nil {
-			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+			return nil, ErrInvalidDueDate
 		}
 		dueDate = &parsedDate
 	}
 
+	blockedBy := uniqueIDs(req.BlockedBy)
+	if err := s.validateDependencies(ctx, 0, userID, blockedBy); err != nil {
+		return nil, err
+	}
+	if req.Status == "completed" {
+		if err := s.checkBlockers(ctx, blockedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	// New todos go to the bottom of their board column
+	position, err := s.todoRepo.NextPosition(ctx, userID, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
 	todo := &model.Todo{
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      req.Status,
 		Priority:    req.Priority,
+		Position:    position,
 		DueDate:     dueDate,
 		UserID:      userID,
 	}
 
-	if err := s.todoRepo.Create(todo); err != nil {
+	err = s.write(ctx, userID, 0, req.Status == "in_progress", func(repo *repository.TodoRepository) error {
+		return repo.CreateWithDependencies(ctx, todo, blockedBy)
+	})
+	if err != nil {
 		return nil, err
 	}
+	metrics.TodosCreated.Inc()
+	recordCompletion("", todo.Status)
 
+	if err := s.todoRepo.LoadDependencies(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	s.publish(event.TodoCreated, todo)
 	return todo, nil
 }
 
-// GetTodoByID retrieves a todo by ID with authorization check
-func (s *TodoService) GetTodoByID(todoID, userID uint) (*model.Todo, error) {
-	todo, err := s.todoRepo.FindByID(todoID)
+// GetTodoByID retrieves a todo by ID with authorization check.
+// expand lists related objects to embed, currently only "user".
+func (s *TodoService) GetTodoByID(ctx context.Context, todoID, userID uint, expand ...string) (*model.Todo, error) {
+	ctx, span := tracing.Start(ctx, "TodoService.GetTodoByID")
+	defer span.End()
+
+	preloads, err := expandPreloads(expand)
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := s.todoRepo.FindByID(ctx, todoID, preloads...)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrTodoNotFound
@@ -86,11 +186,18 @@ func (s *TodoService) GetTodoByID(todoID, userID uint) (*model.Todo, error) {
 		return nil, ErrUnauthorizedAccess
 	}
 
+	if err := s.todoRepo.LoadDependencies(ctx, todo); err != nil {
+		return nil, err
+	}
+
 	return todo, nil
 }
 
-// GetUserTodos retrieves all todos for a user with optional filters
-func (s *TodoService) GetUserTodos(userID uint, status, priority string) ([]model.Todo, error) {
+// GetUserTodos retrieves all todos for a user with optional filters and expanded relations
+func (s *TodoService) GetUserTodos(ctx context.Context, userID uint, status, priority string, expand ...string) ([]model.Todo, error) {
+	ctx, span := tracing.Start(ctx, "TodoService.GetUserTodos")
+	defer span.End()
+
 	// Validate filters if provided
 	if status != "" && !isValidStatus(status) {
 		return nil, ErrInvalidStatus
@@ -100,19 +207,90 @@ func (s *TodoService) GetUserTodos(userID uint, status, priority string) ([]mode
 		return nil, ErrInvalidPriority
 	}
 
-	return s.todoRepo.FindByUserIDWithFilters(userID, status, priority)
+	preloads, err := expandPreloads(expand)
+	if err != nil {
+		return nil, err
+	}
+
+	todos, err := s.todoRepo.FindByUserIDWithFilters(ctx, userID, status, priority, preloads...)
+	if err != nil {
+		return nil, err
+	}
+
+	ptrs := make([]*model.Todo, len(todos))
+	for i := range todos {
+		ptrs[i] = &todos[i]
+	}
+	if err := s.todoRepo.LoadDependencies(ctx, ptrs...); err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// GetUserTodosPage retrieves one page of a user's todos with optional filters
+func (s *TodoService) GetUserTodosPage(ctx context.Context, userID uint, status, priority string, page, limit int) ([]model.Todo, int64, error) {
+	ctx, span := tracing.Start(ctx, "TodoService.GetUserTodosPage")
+	defer span.End()
+
+	if status != "" && !isValidStatus(status) {
+		return nil, 0, ErrInvalidStatus
+	}
+
+	if priority != "" && !isValidPriority(priority) {
+		return nil, 0, ErrInvalidPriority
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	todos, total, err := s.todoRepo.FindPageByUserID(ctx, userID, status, priority, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ptrs := make([]*model.Todo, len(todos))
+	for i := range todos {
+		ptrs[i] = &todos[i]
+	}
+	if err := s.todoRepo.LoadDependencies(ctx, ptrs...); err != nil {
+		return nil, 0, err
+	}
+
+	return todos, total, nil
 }
 
 // UpdateTodo updates a todo with authorization check
-func (s *TodoService) UpdateTodo(todoID, userID uint, req dto.UpdateTodoRequest) (*model.Todo, error) {
+func (s *TodoService) UpdateTodo(ctx context.Context, todoID, userID uint, req dto.UpdateTodoRequest) (*model.Todo, error) {
+	return s.updateTodo(ctx, todoID, userID, req, 0)
+}
+
+// updateTodo implements UpdateTodo. A non-zero expectedVersion makes the
+// update fail with ErrTodoModified unless the todo is still at that version.
+func (s *TodoService) updateTodo(ctx context.Context, todoID, userID uint, req dto.UpdateTodoRequest, expectedVersion uint) (*model.Todo, error) {
+	ctx, span := tracing.Start(ctx, "TodoService.UpdateTodo")
+	defer span.End()
+	ctx = database.WithPrimary(ctx)
+
 	// Check if todo exists and user owns it
-	todo, err := s.GetTodoByID(todoID, userID)
+	todo, err := s.GetTodoByID(ctx, todoID, userID)
 	if err != nil {
 		return nil, err
 	}
+	if expectedVersion != 0 && todo.Version != expectedVersion {
+		return nil, ErrTodoModified
+	}
+	previousStatus := todo.Status
 
 	// Update fields if provided
 	if req.Title != nil {
+		if err := validateTitle(*req.Title); err != nil {
+			return nil, err
+		}
 		todo.Title = *req.Title
 	}
 
@@ -120,10 +298,31 @@ func (s *TodoService) UpdateTodo(todoID, userID uint, req dto.UpdateTodoRequest)
 		todo.Description = *req.Description
 	}
 
+	// Dependencies: a provided list replaces the existing one
+	blockedBy := todo.BlockedBy
+	if req.BlockedBy != nil {
+		blockedBy = uniqueIDs(*req.BlockedBy)
+		if err := s.validateDependencies(ctx, todo.ID, userID, blockedBy); err != nil {
+			return nil, err
+		}
+	}
+
 	if req.Status != nil {
 		if !isValidStatus(*req.Status) {
 			return nil, ErrInvalidStatus
 		}
+		if *req.Status != todo.Status {
+			if err := s.checkMoveAllowed(ctx, *req.Status, blockedBy); err != nil {
+				return nil, err
+			}
+
+			// Status change moves the todo to the bottom of the new column
+			position, err := s.todoRepo.NextPosition(ctx, userID, *req.Status)
+			if err != nil {
+				return nil, err
+			}
+			todo.Position = position
+		}
 		todo.Status = *req.Status
 	}
 
@@ -140,32 +339,386 @@ func (s *TodoService) UpdateTodo(todoID, userID uint, req dto.UpdateTodoRequest)
 		} else {
 			parsedDate, err := time.Parse("2006-01-02", *req.DueDate)
 			if err != nil {
-				return nil, errors.New("invalid date format, use YYYY-MM-DD")
+				return nil, ErrInvalidDueDate
 			}
 			todo.DueDate = &parsedDate
 		}
 	}
 
-	if err := s.todoRepo.Update(todo); err != nil {
-		return nil, err
+	// Dependencies are written with the todo so the change bumps its version
+	intoInProgress := todo.Status == "in_progress" && previousStatus != "in_progress"
+	err = s.write(ctx, userID, todo.ID, intoInProgress, func(repo *repository.TodoRepository) error {
+		if req.BlockedBy != nil {
+			return repo.UpdateWithDependencies(ctx, todo, blockedBy)
+		}
+		return repo.Update(ctx, todo)
+	})
+	if err != nil {
+		return nil, todoWriteError(err)
 	}
+	recordCompletion(previousStatus, todo.Status)
 
+	if req.BlockedBy != nil {
+		if err := s.todoRepo.LoadDependencies(ctx, todo); err != nil {
+			return nil, err
+		}
+	}
+
+	s.publish(event.TodoUpdated, todo)
 	return todo, nil
 }
 
 // DeleteTodo deletes a todo with authorization check
-func (s *TodoService) DeleteTodo(todoID, userID uint) error {
+func (s *TodoService) DeleteTodo(ctx context.Context, todoID, userID uint) error {
+	return s.deleteTodo(ctx, todoID, userID, 0)
+}
+
+// deleteTodo implements DeleteTodo; expectedVersion works as in updateTodo
+func (s *TodoService) deleteTodo(ctx context.Context, todoID, userID uint, expectedVersion uint) error {
+	ctx, span := tracing.Start(ctx, "TodoService.DeleteTodo")
+	defer span.End()
+	ctx = database.WithPrimary(ctx)
+
 	// Check if todo exists and user owns it
-	_, err := s.GetTodoByID(todoID, userID)
+	todo, err := s.GetTodoByID(ctx, todoID, userID)
 	if err != nil {
 		return err
 	}
+	if expectedVersion != 0 && todo.Version != expectedVersion {
+		return ErrTodoModified
+	}
 
-	return s.todoRepo.Delete(todoID)
+	if err := s.todoRepo.Delete(ctx, todo); err != nil {
+		return todoWriteError(err)
+	}
+
+	s.publish(event.TodoDeleted, todo)
+	return nil
+}
+
+// ============================================
+// KANBAN BOARD
+// ============================================
+
+// GetBoard returns the user's todos grouped into status columns, ordered by position
+func (s *TodoService) GetBoard(ctx context.Context, userID uint) ([]BoardColumn, error) {
+	ctx, span := tracing.Start(ctx, "TodoService.GetBoard")
+	defer span.End()
+
+	todos, err := s.todoRepo.FindBoardByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ptrs := make([]*model.Todo, len(todos))
+	for i := range todos {
+		ptrs[i] = &todos[i]
+	}
+	if err := s.todoRepo.LoadDependencies(ctx, ptrs...); err != nil {
+		return nil, err
+	}
+
+	wipLimit, err := s.GetWIPLimit(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]BoardColumn, len(boardStatuses))
+	index := make(map[string]int, len(boardStatuses))
+	for i, status := range boardStatuses {
+		columns[i] = BoardColumn{Status: status, Todos: []model.Todo{}}
+		index[status] = i
+	}
+	columns[index["in_progress"]].WIPLimit = wipLimit
+
+	for _, todo := range todos {
+		if i, ok := index[todo.Status]; ok {
+			columns[i].Todos = append(columns[i].Todos, todo)
+		}
+	}
+
+	return columns, nil
+}
+
+// MoveTodo moves a todo to a board column at the given position.
+// A nil position keeps the current position when staying in the same
+// column, or appends to the bottom of the new column.
+func (s *TodoService) MoveTodo(ctx context.Context, todoID, userID uint, status string, position *int) (*model.Todo, error) {
+	ctx, span := tracing.Start(ctx, "TodoService.MoveTodo")
+	defer span.End()
+	ctx = database.WithPrimary(ctx)
+
+	if !isValidStatus(status) {
+		return nil, ErrInvalidStatus
+	}
+
+	todo, err := s.GetTodoByID(ctx, todoID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != todo.Status {
+		if err := s.checkMoveAllowed(ctx, status, todo.BlockedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	var target int
+	switch {
+	case position != nil:
+		target = *position
+	case status == todo.Status:
+		return todo, nil
+	default:
+		target, err = s.todoRepo.NextPosition(ctx, userID, status)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	previousStatus := todo.Status
+	intoInProgress := status == "in_progress" && previousStatus != "in_progress"
+	err = s.write(ctx, userID, todo.ID, intoInProgress, func(repo *repository.TodoRepository) error {
+		return repo.MoveToPosition(ctx, todo, status, target)
+	})
+	if err != nil {
+		return nil, todoWriteError(err)
+	}
+	recordCompletion(previousStatus, todo.Status)
+
+	s.publish(event.TodoUpdated, todo)
+	return todo, nil
+}
+
+// GetWIPLimit returns the user's in_progress limit, falling back to the configured default
+func (s *TodoService) GetWIPLimit(ctx context.Context, userID uint) (int, error) {
+	ctx, span := tracing.Start(ctx, "TodoService.GetWIPLimit")
+	defer span.End()
+
+	setting, err := s.boardRepo.FindSettingByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if setting == nil || setting.WIPLimit == nil {
+		return s.settings().WIPLimit, nil
+	}
+	return *setting.WIPLimit, nil
+}
+
+// SetWIPLimit stores the user's in_progress limit (0 disables the limit)
+func (s *TodoService) SetWIPLimit(ctx context.Context, userID uint, limit int) error {
+	ctx, span := tracing.Start(ctx, "TodoService.SetWIPLimit")
+	defer span.End()
+
+	return s.boardRepo.SaveSetting(ctx, &model.BoardSetting{
+		UserID:   userID,
+		WIPLimit: &limit,
+	})
+}
+
+// checkMoveAllowed validates moving a todo into a different status column.
+// The in_progress WIP limit is checked together with the write, see write.
+func (s *TodoService) checkMoveAllowed(ctx context.Context, status string, blockedBy []uint) error {
+	if status == "completed" {
+		return s.checkBlockers(ctx, blockedBy)
+	}
+	return nil
+}
+
+// write runs fn, a todo write, against the repository. A write that moves a
+// todo of userID into in_progress runs in one transaction with the WIP limit
+// check, so two concurrent moves cannot both take the last free slot.
+func (s *TodoService) write(ctx context.Context, userID, todoID uint, intoInProgress bool, fn func(repo *repository.TodoRepository) error) error {
+	if !intoInProgress {
+		return fn(s.todoRepo)
+	}
+	return s.todoRepo.WithWIPLimit(ctx, userID, todoID, s.checkWIPLimit, fn)
+}
+
+// checkWIPLimit returns ErrWIPLimitReached when the in_progress column already
+// holds inProgress todos at or above the limit. stored is the user's own
+// limit; nil falls back to the configured default.
+func (s *TodoService) checkWIPLimit(stored *int, inProgress int64) error {
+	limit := s.settings().WIPLimit
+	if stored != nil {
+		limit = *stored
+	}
+	if limit > 0 && inProgress >= int64(limit) {
+		return ErrWIPLimitReached
+	}
+	return nil
+}
+
+// validateDependencies checks that every dependency belongs to the user and
+// that todoID (0 for a todo that does not exist yet) would not end up in a cycle
+func (s *TodoService) validateDependencies(ctx context.Context, todoID, userID uint, dependsOnIDs []uint) error {
+	if len(dependsOnIDs) == 0 {
+		return nil
+	}
+
+	owned, err := s.todoRepo.CountOwnedByUser(ctx, dependsOnIDs, userID)
+	if err != nil {
+		return err
+	}
+	if owned != int64(len(dependsOnIDs)) {
+		return ErrInvalidDependency
+	}
+
+	// A new todo has no dependents yet, so it cannot be part of a cycle
+	if todoID == 0 {
+		return nil
+	}
+
+	// Cycle: todoID is one of its own (transitive) dependencies
+	reachable, err := s.todoRepo.FindTransitiveDependencyIDs(ctx, dependsOnIDs)
+	if err != nil {
+		return err
+	}
+	for _, id := range append(reachable, dependsOnIDs...) {
+		if id == todoID {
+			return ErrDependencyCycle
+		}
+	}
+
+	return nil
+}
+
+// checkBlockers returns ErrTodoBlocked when completion is guarded and
+// some of the given dependencies are still open
+func (s *TodoService) checkBlockers(ctx context.Context, dependsOnIDs []uint) error {
+	if !s.settings().BlockCompletion || len(dependsOnIDs) == 0 {
+		return nil
+	}
+
+	open, err := s.todoRepo.CountOpen(ctx, dependsOnIDs)
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return ErrTodoBlocked
+	}
+
+	return nil
+}
+
+// todoWriteError maps a lost version race in the repository to ErrTodoModified
+func todoWriteError(err error) error {
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return ErrTodoModified
+	}
+	return err
+}
+
+// publish sends a todo event to subscribers (webhooks, streams, ...)
+func (s *TodoService) publish(eventType string, todo *model.Todo) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(event.New(eventType, todo.UserID, todo.ID, ToTodoResponse(todo)))
+}
+
+// recordCompletion counts a todo that moved into the completed status
+func recordCompletion(previousStatus, status string) {
+	if previousStatus != "completed" && status == "completed" {
+		metrics.TodosCompleted.Inc()
+	}
+}
+
+// ToTodoResponse converts a todo model into its response DTO
+func ToTodoResponse(todo *model.Todo) dto.TodoResponse {
+	response := dto.TodoResponse{
+		ID:          todo.ID,
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      todo.Status,
+		Priority:    todo.Priority,
+		DueDate:     todo.DueDate,
+		UserID:      todo.UserID,
+		BlockedBy:   todo.BlockedBy,
+		Blocking:    todo.Blocking,
+		Version:     todo.Version,
+		CreatedAt:   todo.CreatedAt,
+		UpdatedAt:   todo.UpdatedAt,
+	}
+
+	// User hanya terisi jika di-preload lewat ?expand=user
+	if todo.User.ID != 0 {
+		response.User = &dto.UserResponse{
+			ID:        todo.User.ID,
+			Username:  todo.User.Username,
+			Email:     todo.User.Email,
+			FullName:  todo.User.FullName,
+			CreatedAt: todo.User.CreatedAt,
+			UpdatedAt: todo.User.UpdatedAt,
+		}
+	}
+
+	// Keep dependency lists as [] instead of null for todos without loaded dependencies
+	if response.BlockedBy == nil {
+		response.BlockedBy = []uint{}
+	}
+	if response.Blocking == nil {
+		response.Blocking = []uint{}
+	}
+
+	return response
+}
+
+// expandRelations memetakan nilai ?expand ke association GORM yang di-preload.
+// Belum ada model project, jadi ?expand=project ditolak seperti relasi lain
+// yang tidak dikenal.
+var expandRelations = map[string]string{
+	"user": "User",
+}
+
+// expandPreloads converts expand values into GORM preloads
+func expandPreloads(expand []string) ([]string, error) {
+	preloads := make([]string, 0, len(expand))
+	for _, name := range expand {
+		preload, ok := expandRelations[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s (supported: %s)", ErrInvalidExpand, name, supportedExpands())
+		}
+		preloads = append(preloads, preload)
+	}
+	return preloads, nil
+}
+
+// supportedExpands lists the accepted ?expand values for error messages
+func supportedExpands() string {
+	names := make([]string, 0, len(expandRelations))
+	for name := range expandRelations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
 }
 
 // Helper functions for validation
 
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// validateTitle aturan judul todo yang sama untuk REST, GraphQL, gRPC, dan sync
+func validateTitle(title string) error {
+	if title == "" {
+		return ErrTitleRequired
+	}
+	if utf8.RuneCountInString(title) > MaxTitleLength {
+		return ErrTitleTooLong
+	}
+	return nil
+}
+
 func isValidStatus(status string) bool {
 	validStatuses := map[string]bool{
 		"pending":     true,
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// recordedEvents menyimpan event yang dipublish service
+type recordedEvents []event.Event
+
+func (r *recordedEvents) Publish(e event.Event) { *r = append(*r, e) }
+
+func newTestTodoService(t *testing.T, settings TodoSettings) (*TodoService, *gorm.DB, *recordedEvents) {
+	t.Helper()
+	db := newTestDB(t)
+	events := &recordedEvents{}
+	todoService := NewTodoService(
+		repository.NewTodoRepository(database.NewCluster(db, database.Options{})),
+		repository.NewBoardRepository(db),
+		events,
+		func() TodoSettings { return settings },
+	)
+	return todoService, db, events
+}
+
+func TestCreateTodoWithDependenciesIsAtomic(t *testing.T) {
+	s, db, events := newTestTodoService(t, TodoSettings{BlockCompletion: true})
+	user := newTestUser(t, db, "creator")
+	ctx := context.Background()
+
+	blocker, err := s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "blocker", Status: "pending", Priority: "low"})
+	require.NoError(t, err)
+	todo, err := s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "blocked", Status: "pending", Priority: "low", BlockedBy: []uint{blocker.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{blocker.ID}, todo.BlockedBy)
+	assert.Equal(t, uint(1), todo.Version)
+
+	// Insert edge gagal: todo tidak boleh tertinggal dan event tidak dikirim
+	require.NoError(t, db.Migrator().DropTable(&model.TodoDependency{}))
+	published := len(*events)
+	_, err = s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "orphan", Status: "pending", Priority: "low", BlockedBy: []uint{blocker.ID}})
+	require.Error(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&model.Todo{}).Where("title = ?", "orphan").Count(&count).Error)
+	assert.Zero(t, count)
+	assert.Len(t, *events, published)
+}
+
+func TestTodoTitleValidation(t *testing.T) {
+	s, db, events := newTestTodoService(t, TodoSettings{})
+	user := newTestUser(t, db, "titles")
+	ctx := context.Background()
+
+	_, err := s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "", Status: "pending", Priority: "low"})
+	assert.ErrorIs(t, err, ErrTitleRequired)
+	_, err = s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: strings.Repeat("a", MaxTitleLength+1), Status: "pending", Priority: "low"})
+	assert.ErrorIs(t, err, ErrTitleTooLong)
+	assert.Empty(t, *events)
+
+	// Batas dihitung per karakter, bukan per byte
+	longest := strings.Repeat("é", MaxTitleLength)
+	todo, err := s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: longest, Status: "pending", Priority: "low"})
+	require.NoError(t, err)
+
+	empty, tooLong := "", longest+"a"
+	_, err = s.UpdateTodo(ctx, todo.ID, user.ID, dto.UpdateTodoRequest{Title: &empty})
+	assert.ErrorIs(t, err, ErrTitleRequired)
+	_, err = s.UpdateTodo(ctx, todo.ID, user.ID, dto.UpdateTodoRequest{Title: &tooLong})
+	assert.ErrorIs(t, err, ErrTitleTooLong)
+
+	stored, err := s.GetTodoByID(ctx, todo.ID, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, longest, stored.Title)
+	assert.Equal(t, uint(1), stored.Version)
+}
+
+func TestWIPLimit(t *testing.T) {
+	s, db, _ := newTestTodoService(t, TodoSettings{WIPLimit: 1})
+	user := newTestUser(t, db, "worker")
+	ctx := context.Background()
+	inProgress := "in_progress"
+
+	_, err := s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "first", Status: "in_progress", Priority: "low"})
+	require.NoError(t, err)
+	_, err = s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "second", Status: "in_progress", Priority: "low"})
+	assert.ErrorIs(t, err, ErrWIPLimitReached)
+
+	// Baris board_settings dibuat untuk lock, limit tetap mengikuti default
+	limit, err := s.GetWIPLimit(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, limit)
+
+	pending, err := s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "pending", Status: "pending", Priority: "low"})
+	require.NoError(t, err)
+	_, err = s.UpdateTodo(ctx, pending.ID, user.ID, dto.UpdateTodoRequest{Status: &inProgress})
+	assert.ErrorIs(t, err, ErrWIPLimitReached)
+	_, err = s.MoveTodo(ctx, pending.ID, user.ID, "in_progress", nil)
+	assert.ErrorIs(t, err, ErrWIPLimitReached)
+
+	// Limit per user menggantikan default, 0 berarti tanpa batas
+	require.NoError(t, s.SetWIPLimit(ctx, user.ID, 2))
+	moved, err := s.MoveTodo(ctx, pending.ID, user.ID, "in_progress", nil)
+	require.NoError(t, err)
+	_, err = s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "third", Status: "in_progress", Priority: "low"})
+	assert.ErrorIs(t, err, ErrWIPLimitReached)
+
+	// Pindah posisi di kolom yang sama tidak dihitung sebagai todo baru
+	position := 0
+	_, err = s.MoveTodo(ctx, moved.ID, user.ID, "in_progress", &position)
+	require.NoError(t, err)
+
+	require.NoError(t, s.SetWIPLimit(ctx, user.ID, 0))
+	_, err = s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "third", Status: "in_progress", Priority: "low"})
+	assert.NoError(t, err)
+}
+
+func TestConcurrentMovesRespectWIPLimit(t *testing.T) {
+	s, db, _ := newTestTodoService(t, TodoSettings{WIPLimit: 1})
+	user := newTestUser(t, db, "racer")
+	ctx := context.Background()
+
+	todos := make([]*model.Todo, 5)
+	for i := range todos {
+		todo, err := s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "todo", Status: "pending", Priority: "low"})
+		require.NoError(t, err)
+		todos[i] = todo
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(todos))
+	for i, todo := range todos {
+		wg.Add(1)
+		go func(i int, todoID uint) {
+			defer wg.Done()
+			_, errs[i] = s.MoveTodo(ctx, todoID, user.ID, "in_progress", nil)
+		}(i, todo.ID)
+	}
+	wg.Wait()
+
+	moved := 0
+	for _, err := range errs {
+		if err == nil {
+			moved++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrWIPLimitReached)
+	}
+	assert.Equal(t, 1, moved)
+
+	var count int64
+	require.NoError(t, db.Model(&model.Todo{}).Where("user_id = ? AND status = ?", user.ID, "in_progress").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMoveTodoShiftsPositions(t *testing.T) {
+	s, db, _ := newTestTodoService(t, TodoSettings{})
+	user := newTestUser(t, db, "mover")
+	ctx := context.Background()
+
+	titles := []string{"a", "b", "c"}
+	ids := map[string]uint{}
+	for _, title := range titles {
+		todo, err := s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: title, Status: "pending", Priority: "low"})
+		require.NoError(t, err)
+		ids[title] = todo.ID
+	}
+	done, err := s.CreateTodo(ctx, user.ID, dto.CreateTodoRequest{Title: "d", Status: "completed", Priority: "low"})
+	require.NoError(t, err)
+
+	column := func(status string) []string {
+		t.Helper()
+		columns, err := s.GetBoard(ctx, user.ID)
+		require.NoError(t, err)
+		for _, c := range columns {
+			if c.Status == status {
+				got := make([]string, len(c.Todos))
+				for i, todo := range c.Todos {
+					got[i] = todo.Title
+				}
+				return got
+			}
+		}
+		return nil
+	}
+
+	// Dalam kolom yang sama: todo lain di posisi tujuan dan setelahnya bergeser
+	position := 0
+	_, err = s.MoveTodo(ctx, ids["c"], user.ID, "pending", &position)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a", "b"}, column("pending"))
+
+	// Ke kolom lain di tengah
+	position = 1
+	_, err = s.MoveTodo(ctx, done.ID, user.ID, "pending", &position)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "d", "a", "b"}, column("pending"))
+	assert.Empty(t, column("completed"))
+
+	// Tanpa posisi: ditambahkan di akhir kolom tujuan
+	_, err = s.MoveTodo(ctx, ids["c"], user.ID, "completed", nil)
+	require.NoError(t, err)
+	_, err = s.MoveTodo(ctx, ids["a"], user.ID, "completed", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a"}, column("completed"))
+	assert.Equal(t, []string{"d", "b"}, column("pending"))
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// webhookMaxAttempts jumlah maksimal percobaan sebelum delivery dianggap gagal
+	webhookMaxAttempts = 8
+	// webhookBaseBackoff jeda sebelum retry pertama, berlipat dua setiap retry
+	webhookBaseBackoff = 10 * time.Second
+	// webhookMaxBackoff jeda maksimal antar retry
+	webhookMaxBackoff = time.Hour
+	// webhookPollInterval interval pengecekan delivery yang jatuh tempo
+	webhookPollInterval = 5 * time.Second
+	// webhookBatchSize jumlah delivery yang diproses per putaran
+	webhookBatchSize = 20
+	// webhookRequestTimeout batas waktu satu request ke endpoint webhook
+	webhookRequestTimeout = 10 * time.Second
+	// webhookClaimLease lama delivery yang sedang dikirim dikunci untuk satu
+	// instance. Jika instance mati di tengah pengiriman, delivery diambil alih
+	// instance lain setelah lease habis.
+	webhookClaimLease = time.Minute
+)
+
+// errWebhookTargetBlocked tujuan webhook mengarah ke alamat internal
+var errWebhookTargetBlocked = errors.New("webhook target address is not allowed")
+
+// blockedWebhookNetworks rentang alamat yang bukan internet publik selain yang
+// sudah dikenali net.IP (loopback, private, link-local, unspecified, multicast)
+var blockedWebhookNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "this network"
+	mustParseCIDR("100.64.0.0/10"), // carrier-grade NAT, sering dipakai jaringan internal cloud
+	mustParseCIDR("192.0.0.0/24"),  // IETF protocol assignments
+	mustParseCIDR("198.18.0.0/15"), // benchmarking
+}
+
+// WebhookDispatcher sends queued webhook deliveries in the background,
+// retrying failed ones with exponential backoff. Only public addresses are
+// allowed as targets so webhooks cannot be used to reach internal services.
+type WebhookDispatcher struct {
+	webhookRepo *repository.WebhookRepository
+	client      *http.Client
+	wake        chan struct{}
+	// allowIP menentukan alamat tujuan yang boleh dihubungi
+	allowIP func(ip net.IP) bool
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher instance
+func NewWebhookDispatcher(webhookRepo *repository.WebhookRepository) *WebhookDispatcher {
+	return newWebhookDispatcher(webhookRepo, isPublicIP)
+}
+
+func newWebhookDispatcher(webhookRepo *repository.WebhookRepository, allowIP func(ip net.IP) bool) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		webhookRepo: webhookRepo,
+		wake:        make(chan struct{}, 1),
+		allowIP:     allowIP,
+	}
+
+	// Alamat diperiksa lagi saat dial, setelah DNS di-resolve, sehingga DNS
+	// rebinding (host publik saat didaftarkan, internal saat dikirim) dan
+	// redirect ke alamat internal ikut ditolak. Proxy dari env tidak dipakai
+	// karena pemeriksaan ini hanya melihat alamat proxy.
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !d.allowIP(ip) {
+				return errWebhookTargetBlocked
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	d.client = &http.Client{Timeout: webhookRequestTimeout, Transport: transport}
+	return d
+}
+
+// CheckURL memastikan URL webhook absolut http(s) dan host-nya hanya
+// resolve ke alamat publik
+func (d *WebhookDispatcher) CheckURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: host %q cannot be resolved", ErrInvalidWebhookURL, u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !d.allowIP(addr.IP) {
+			return fmt.Errorf("%w: host %q resolves to a private or reserved address", ErrInvalidWebhookURL, u.Hostname())
+		}
+	}
+	return nil
+}
+
+// Notify wakes the dispatcher up so new deliveries are sent immediately
+func (d *WebhookDispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default: // Sudah ada sinyal yang menunggu
+	}
+}
+
+// Run processes due deliveries until ctx is cancelled
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.processDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// processDue sends every delivery whose next attempt is due
+func (d *WebhookDispatcher) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := d.webhookRepo.FindDueDeliveries(ctx, time.Now(), webhookBatchSize)
+		if err != nil {
+			slog.ErrorContext(ctx, "webhook: failed to load due deliveries", "error", err)
+			return
+		}
+
+		for i := range deliveries {
+			d.attempt(ctx, &deliveries[i])
+		}
+
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// attempt claims a delivery, sends it once and records the outcome. A
+// delivery already claimed by another instance is skipped.
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery *model.WebhookDelivery) {
+	claimed, err := d.webhookRepo.ClaimDelivery(ctx, delivery, time.Now().Add(webhookClaimLease))
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook: failed to claim delivery", "delivery_id", delivery.ID, "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	webhook, err := d.webhookRepo.FindByID(ctx, delivery.WebhookID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			slog.ErrorContext(ctx, "webhook: failed to load webhook", "webhook_id", delivery.WebhookID, "error", err)
+			// Lease habis lalu delivery dicoba lagi
+			return
+		}
+		// Webhook sudah dihapus, delivery tidak perlu dicoba lagi
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.LastError = "webhook deleted"
+		delivery.NextAttemptAt = nil
+		d.save(ctx, delivery)
+		return
+	}
+
+	statusCode, sendErr := d.send(ctx, webhook, delivery)
+	delivery.LastStatusCode = statusCode
+
+	now := time.Now()
+	switch {
+	case sendErr == nil:
+		delivery.Status = model.DeliveryStatusSucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= webhookMaxAttempts:
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = nil
+	default:
+		next := now.Add(retryBackoff(delivery.Attempts))
+		delivery.Status = model.DeliveryStatusPending
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = &next
+	}
+
+	d.save(ctx, delivery)
+}
+
+// send posts the signed payload to the webhook URL
+func (d *WebhookDispatcher) send(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-api-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", utils.SignPayload(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errWebhookTargetBlocked) {
+			return 0, errWebhookTargetBlocked
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Body tidak disimpan (bisa berisi data dari sistem lain), hanya dibuang
+	// sebagian agar koneksi bisa dipakai ulang
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (d *WebhookDispatcher) save(ctx context.Context, delivery *model.WebhookDelivery) {
+	if err := d.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		slog.ErrorContext(ctx, "webhook: failed to update delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// retryBackoff returns base*2^(attempts-1) capped at webhookMaxBackoff, plus up to 20% jitter.
+// attempts < 1 dianggap retry pertama.
+func retryBackoff(attempts int) time.Duration {
+	// Shift dibatasi agar tidak negatif (panic) dan tidak overflow; 2^16 kali
+	// base sudah jauh di atas webhookMaxBackoff
+	shift := min(max(attempts, 1)-1, 16)
+	backoff := webhookBaseBackoff << shift
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(backoff) / 5))
+	return backoff + jitter
+}
+
+// isPublicIP melaporkan apakah ip alamat internet publik yang boleh menjadi
+// tujuan webhook
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range blockedWebhookNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
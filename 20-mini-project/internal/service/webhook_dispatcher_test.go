@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestDB database SQLite in-memory dengan schema dari migrasi
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.DBDriver = config.DriverSQLite
+	cfg.DBName = config.SQLiteMemory
+	cfg.DBLogLevel = "silent"
+
+	db, err := config.NewDatabase(cfg)
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrations, err := migrate.Embedded(cfg.DBDriver)
+	require.NoError(t, err)
+	migrator, err := migrate.New(sqlDB, cfg.DBDriver, migrations)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func newTestUser(t *testing.T, db *gorm.DB, username string) *model.User {
+	t.Helper()
+	user := &model.User{Username: username, Email: username + "@example.com", Password: "x", FullName: username}
+	require.NoError(t, db.Create(user).Error)
+	return user
+}
+
+// queueTestDelivery membuat webhook ke url dengan satu delivery yang jatuh tempo
+func queueTestDelivery(t *testing.T, db *gorm.DB, url string) (*model.Webhook, *model.WebhookDelivery) {
+	t.Helper()
+	user := newTestUser(t, db, "hook")
+	webhook := &model.Webhook{UserID: user.ID, URL: url, Secret: "s3cret", Events: event.TodoCreated, Active: true}
+	require.NoError(t, db.Create(webhook).Error)
+
+	now := time.Now().Add(-time.Second)
+	delivery := &model.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       "evt-1",
+		EventType:     event.TodoCreated,
+		Payload:       `{"id":"evt-1"}`,
+		Status:        model.DeliveryStatusPending,
+		NextAttemptAt: &now,
+	}
+	require.NoError(t, db.Create(delivery).Error)
+	return webhook, delivery
+}
+
+func reloadDelivery(t *testing.T, db *gorm.DB, id uint) model.WebhookDelivery {
+	t.Helper()
+	var delivery model.WebhookDelivery
+	require.NoError(t, db.First(&delivery, id).Error)
+	return delivery
+}
+
+// allowAnyIP dipakai test yang mengirim ke httptest server di 127.0.0.1
+func allowAnyIP(net.IP) bool { return true }
+
+func TestRetryBackoffIsCappedWithJitter(t *testing.T) {
+	for attempts := -1; attempts <= 200; attempts++ {
+		base := webhookMaxBackoff
+		if attempts <= 1 {
+			base = webhookBaseBackoff
+		} else if attempts <= 10 {
+			base = min(webhookBaseBackoff<<(attempts-1), webhookMaxBackoff)
+		}
+		for i := 0; i < 20; i++ {
+			got := retryBackoff(attempts)
+			assert.GreaterOrEqual(t, got, base, "attempt %d", attempts)
+			assert.Less(t, got, base+base/5+1, "attempt %d", attempts)
+		}
+	}
+}
+
+func TestCheckURLRejectsInternalTargets(t *testing.T) {
+	d := NewWebhookDispatcher(nil)
+	ctx := context.Background()
+
+	for _, raw := range []string{
+		"ftp://example.com/hook",
+		"/relative",
+		"http://127.0.0.1/hook",
+		"http://localhost:5432",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.1.2.3/hook",
+		"http://172.16.0.1/hook",
+		"http://192.168.1.1/hook",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0:8080/hook",
+		"http://[::1]/hook",
+		"http://[fd00::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	} {
+		assert.ErrorIs(t, d.CheckURL(ctx, raw), ErrInvalidWebhookURL, raw)
+	}
+	assert.NoError(t, d.CheckURL(ctx, "https://93.184.216.34/hook"))
+}
+
+func TestDispatcherSignsPayload(t *testing.T) {
+	db := newTestDB(t)
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(r.Header.Get("X-Webhook-Timestamp") + "." + string(body)))
+		if r.Header.Get("X-Webhook-Signature") != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, event.TodoCreated, r.Header.Get("X-Webhook-Event"))
+		received.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, delivery := queueTestDelivery(t, db, server.URL)
+	newWebhookDispatcher(repository.NewWebhookRepository(db), allowAnyIP).processDue(context.Background())
+
+	got := reloadDelivery(t, db, delivery.ID)
+	assert.Equal(t, int32(1), received.Load())
+	assert.Equal(t, model.DeliveryStatusSucceeded, got.Status)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, http.StatusNoContent, got.LastStatusCode)
+	assert.NotNil(t, got.DeliveredAt)
+}
+
+func TestDispatcherRetriesWithoutStoringResponseBody(t *testing.T) {
+	db := newTestDB(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("internal secret"))
+	}))
+	defer server.Close()
+
+	_, delivery := queueTestDelivery(t, db, server.URL)
+	d := newWebhookDispatcher(repository.NewWebhookRepository(db), allowAnyIP)
+	start := time.Now()
+	d.processDue(context.Background())
+
+	got := reloadDelivery(t, db, delivery.ID)
+	assert.Equal(t, model.DeliveryStatusPending, got.Status)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, got.LastStatusCode)
+	assert.Equal(t, "unexpected status 500", got.LastError)
+	require.NotNil(t, got.NextAttemptAt)
+	assert.WithinRange(t, *got.NextAttemptAt, start.Add(webhookBaseBackoff), time.Now().Add(webhookBaseBackoff+webhookBaseBackoff/5))
+
+	// Percobaan terakhir yang gagal menandai delivery failed
+	due := time.Now().Add(-time.Second)
+	require.NoError(t, db.Model(&got).Updates(map[string]interface{}{"attempts": webhookMaxAttempts - 1, "next_attempt_at": due}).Error)
+	d.processDue(context.Background())
+
+	got = reloadDelivery(t, db, delivery.ID)
+	assert.Equal(t, model.DeliveryStatusFailed, got.Status)
+	assert.Equal(t, webhookMaxAttempts, got.Attempts)
+	assert.Nil(t, got.NextAttemptAt)
+}
+
+func TestDispatcherBlocksInternalTargetAtDial(t *testing.T) {
+	db := newTestDB(t)
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	// URL tersimpan sudah mengarah ke loopback (misalnya DNS berubah setelah didaftarkan)
+	_, delivery := queueTestDelivery(t, db, server.URL)
+	NewWebhookDispatcher(repository.NewWebhookRepository(db)).processDue(context.Background())
+
+	got := reloadDelivery(t, db, delivery.ID)
+	assert.Zero(t, hits.Load())
+	assert.Equal(t, model.DeliveryStatusPending, got.Status)
+	assert.Equal(t, errWebhookTargetBlocked.Error(), got.LastError)
+}
+
+func TestClaimDeliveryOnlyOnce(t *testing.T) {
+	db := newTestDB(t)
+	repo := repository.NewWebhookRepository(db)
+	_, delivery := queueTestDelivery(t, db, "https://93.184.216.34/hook")
+	ctx := context.Background()
+
+	// Dua instance membaca baris yang sama lalu berebut meng-claim
+	first, second := *delivery, *delivery
+	lease := time.Now().Add(webhookClaimLease)
+	claimed, err := repo.ClaimDelivery(ctx, &first, lease)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = repo.ClaimDelivery(ctx, &second, lease)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	// Selama lease berjalan delivery tidak jatuh tempo untuk instance lain
+	due, err := repo.FindDueDeliveries(ctx, time.Now(), webhookBatchSize)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	// Setelah lease habis delivery bisa diambil alih
+	due, err = repo.FindDueDeliveries(ctx, lease.Add(time.Second), webhookBatchSize)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, model.DeliveryStatusSending, due[0].Status)
+}
+
+func TestHandleEventDoesNotBlock(t *testing.T) {
+	db := newTestDB(t)
+	repo := repository.NewWebhookRepository(db)
+	webhook, _ := queueTestDelivery(t, db, "https://93.184.216.34/hook")
+	s := NewWebhookService(repo, NewWebhookDispatcher(repo))
+
+	// Tanpa Run yang berjalan, antrean penuh tidak boleh membuat Publish menunggu
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < webhookEventQueueSize+10; i++ {
+			s.HandleEvent(event.New(event.TodoCreated, webhook.UserID, 1, nil))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("HandleEvent blocked")
+	}
+
+	// Run memproses event yang tersisa di antrean saat dihentikan
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Run(ctx)
+
+	var count int64
+	require.NoError(t, db.Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhook.ID).Count(&count).Error)
+	assert.Equal(t, int64(webhookEventQueueSize+1), count)
+}
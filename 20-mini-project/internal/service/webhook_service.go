@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrWebhookNotFound is returned when webhook is not found
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrUnauthorizedWebhookAccess is returned when user tries to access webhook they don't own
+	ErrUnauthorizedWebhookAccess = errors.New("unauthorized access to webhook")
+	// ErrInvalidWebhookURL is returned when webhook URL is not an absolute http(s) URL
+	ErrInvalidWebhookURL = errors.New("webhook url must be an absolute http or https url")
+	// ErrDeliveryNotFound is returned when webhook delivery is not found
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+const (
+	// maxDeliveryLogs batas jumlah delivery log yang dikembalikan per webhook
+	maxDeliveryLogs = 50
+	// webhookEventQueueSize jumlah event yang bisa menunggu dijadikan delivery
+	webhookEventQueueSize = 1024
+)
+
+// WebhookService handles webhook registration and event fan-out
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+	dispatcher  *WebhookDispatcher
+	events      chan event.Event
+}
+
+// NewWebhookService creates a new webhook service instance
+func NewWebhookService(webhookRepo *repository.WebhookRepository, dispatcher *WebhookDispatcher) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		dispatcher:  dispatcher,
+		events:      make(chan event.Event, webhookEventQueueSize),
+	}
+}
+
+// CreateWebhook registers a new webhook endpoint and generates its signing secret
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID uint, req dto.CreateWebhookRequest) (*model.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	if err := s.dispatcher.CheckURL(ctx, req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	webhook := &model.Webhook{
+		UserID: userID,
+		URL:    req.URL,
+		Secret: secret,
+		Events: strings.Join(req.Events, ","),
+		Active: true,
+	}
+
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetWebhookByID retrieves a webhook by ID with authorization check
+func (s *WebhookService) GetWebhookByID(ctx context.Context, webhookID, userID uint) (*model.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetWebhookByID")
+	defer span.End()
+
+	webhook, err := s.webhookRepo.FindByID(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	if webhook.UserID != userID {
+		return nil, ErrUnauthorizedWebhookAccess
+	}
+
+	return webhook, nil
+}
+
+// GetUserWebhooks retrieves all webhooks for a user
+func (s *WebhookService) GetUserWebhooks(ctx context.Context, userID uint) ([]model.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetUserWebhooks")
+	defer span.End()
+
+	return s.webhookRepo.FindByUserID(ctx, userID)
+}
+
+// UpdateWebhook updates a webhook with authorization check
+func (s *WebhookService) UpdateWebhook(ctx context.Context, webhookID, userID uint, req dto.UpdateWebhookRequest) (*model.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.UpdateWebhook")
+	defer span.End()
+
+	webhook, err := s.GetWebhookByID(ctx, webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := s.dispatcher.CheckURL(ctx, *req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+
+	if req.Events != nil {
+		webhook.Events = strings.Join(*req.Events, ",")
+	}
+
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// DeleteWebhook deletes a webhook with authorization check
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID, userID uint) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteWebhook")
+	defer span.End()
+
+	if _, err := s.GetWebhookByID(ctx, webhookID, userID); err != nil {
+		return err
+	}
+
+	return s.webhookRepo.Delete(ctx, webhookID)
+}
+
+// GetDeliveries retrieves the latest delivery logs of a webhook
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID, userID uint) ([]model.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer span.End()
+
+	if _, err := s.GetWebhookByID(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepo.FindDeliveriesByWebhookID(ctx, webhookID, maxDeliveryLogs)
+}
+
+// Redeliver queues a fresh delivery with the same payload as an earlier one
+func (s *WebhookService) Redeliver(ctx context.Context, webhookID, deliveryID, userID uint) (*model.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Redeliver")
+	defer span.End()
+
+	if _, err := s.GetWebhookByID(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	original, err := s.webhookRepo.FindDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	if original.WebhookID != webhookID {
+		return nil, ErrDeliveryNotFound
+	}
+
+	now := time.Now()
+	delivery := model.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        model.DeliveryStatusPending,
+		NextAttemptAt: &now,
+	}
+
+	deliveries := []model.WebhookDelivery{delivery}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return nil, err
+	}
+
+	s.dispatcher.Notify()
+	return &deliveries[0], nil
+}
+
+// HandleEvent hands an event to Run, which queues a delivery for every
+// subscribed webhook. Registered as an event.Bus subscriber, so it only
+// enqueues and never touches the database on the request path.
+func (s *WebhookService) HandleEvent(e event.Event) {
+	select {
+	case s.events <- e:
+	default:
+		slog.Error("webhook: event queue full, dropping event", "event_id", e.ID, "event_type", e.Type)
+	}
+}
+
+// Run turns queued events into deliveries until ctx is cancelled. Events still
+// in the queue at that point are processed before returning.
+func (s *WebhookService) Run(ctx context.Context) {
+	// Event yang sudah diambil dari antrean tetap disimpan walaupun ctx berakhir
+	queueCtx := context.WithoutCancel(ctx)
+	for {
+		select {
+		case e := <-s.events:
+			s.queueDeliveries(queueCtx, e)
+		case <-ctx.Done():
+			for {
+				select {
+				case e := <-s.events:
+					s.queueDeliveries(queueCtx, e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// queueDeliveries creates a pending delivery for every active webhook of the
+// event owner that subscribed to the event type
+func (s *WebhookService) queueDeliveries(ctx context.Context, e event.Event) {
+	webhooks, err := s.webhookRepo.FindActiveByUserID(ctx, e.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook: failed to load webhooks", "user_id", e.UserID, "error", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook: failed to encode event", "event_id", e.ID, "error", err)
+		return
+	}
+
+	now := time.Now()
+	var deliveries []model.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !subscribesTo(webhook, e.Type) {
+			continue
+		}
+		deliveries = append(deliveries, model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       e.ID,
+			EventType:     e.Type,
+			Payload:       string(payload),
+			Status:        model.DeliveryStatusPending,
+			NextAttemptAt: &now,
+		})
+	}
+
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		slog.ErrorContext(ctx, "webhook: failed to queue deliveries", "event_id", e.ID, "error", err)
+		return
+	}
+
+	if len(deliveries) > 0 {
+		s.dispatcher.Notify()
+	}
+}
+
+// Helper functions
+
+func subscribesTo(webhook model.Webhook, eventType string) bool {
+	for _, e := range strings.Split(webhook.Events, ",") {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
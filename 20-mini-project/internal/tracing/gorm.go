@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey key instance GORM untuk menyimpan span selama query berjalan
+const spanKey = "tracing:span"
+
+// GormPlugin membuat span untuk setiap query GORM sebagai child dari span
+// di context statement, sehingga query terlihat di bawah span service dan HTTP.
+// Repository harus memakai db.WithContext(ctx) agar span terhubung.
+type GormPlugin struct{}
+
+// Name implements gorm.Plugin
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize implements gorm.Plugin
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	type register func(name string, fn func(*gorm.DB)) error
+
+	callback := db.Callback()
+	hooks := []struct {
+		operation string
+		before    register
+		after     register
+	}{
+		{"INSERT", callback.Create().Before("gorm:create").Register, callback.Create().After("gorm:create").Register},
+		{"SELECT", callback.Query().Before("gorm:query").Register, callback.Query().After("gorm:query").Register},
+		{"UPDATE", callback.Update().Before("gorm:update").Register, callback.Update().After("gorm:update").Register},
+		{"DELETE", callback.Delete().Before("gorm:delete").Register, callback.Delete().After("gorm:delete").Register},
+		{"ROW", callback.Row().Before("gorm:row").Register, callback.Row().After("gorm:row").Register},
+		{"RAW", callback.Raw().Before("gorm:raw").Register, callback.Raw().After("gorm:raw").Register},
+	}
+
+	for _, hook := range hooks {
+		if err := hook.before("tracing:before_"+hook.operation, p.before(hook.operation)); err != nil {
+			return err
+		}
+		if err := hook.after("tracing:after_"+hook.operation, p.after(hook.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before memulai span client untuk satu operasi database
+func (GormPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := Tracer().Start(db.Statement.Context, operation, trace.WithSpanKind(trace.SpanKindClient))
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// after melengkapi span dengan query, tabel, jumlah baris dan error.
+// Nama span menjadi "<operation> <table>", contoh "SELECT todos".
+func (GormPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(spanKey)
+		if !ok {
+			return
+		}
+		span, ok := value.(trace.Span)
+		if !ok {
+			return
+		}
+		defer span.End()
+
+		if table := db.Statement.Table; table != "" {
+			span.SetName(operation + " " + table)
+			span.SetAttributes(semconv.DBCollectionName(table))
+		}
+		span.SetAttributes(
+			semconv.DBSystemKey.String(db.Dialector.Name()),
+			semconv.DBQueryText(db.Statement.SQL.String()), // Dengan placeholder, tanpa nilai parameter
+			attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+		)
+
+		// Record not found adalah hasil normal di repository, bukan error
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			span.RecordError(db.Error)
+			span.SetStatus(codes.Error, db.Error.Error())
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package tracing menyiapkan OpenTelemetry tracing: exporter, propagasi
+// W3C traceparent, dan helper span untuk service layer dan GORM
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName nama tracer untuk semua span aplikasi
+const instrumentationName = "github.com/adityapryg/golang-demo/20-mini-project"
+
+// Exporter yang didukung
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Options konfigurasi tracing
+type Options struct {
+	// Exporter: none (default), stdout, atau otlp
+	Exporter    string
+	ServiceName string
+	// OTLPEndpoint alamat collector OTLP gRPC, contoh localhost:4317
+	OTLPEndpoint string
+	// OTLPInsecure mengirim tanpa TLS (collector lokal / sidecar)
+	OTLPInsecure bool
+	// SampleRatio fraksi trace baru yang direkam (0..1); trace dari upstream
+	// mengikuti keputusan sampling parent-nya
+	SampleRatio float64
+}
+
+// Setup memasang propagator W3C dan tracer provider global. Fungsi shutdown
+// yang dikembalikan mengirim span yang tersisa dan harus dipanggil saat berhenti.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	// Propagator dipasang walaupun exporter none, agar traceparent dari upstream tetap diteruskan
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(opts.Exporter) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	case ExporterOTLP:
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.OTLPInsecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, clientOpts...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, use none, stdout or otlp", opts.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", opts.Exporter, err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(opts.ServiceName)),
+		resource.WithFromEnv(), // OTEL_RESOURCE_ATTRIBUTES
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer mengembalikan tracer aplikasi dari provider global
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start memulai span internal, dipakai di awal method service:
+//
+//	ctx, span := tracing.Start(ctx, "TodoService.CreateTodo")
+//	defer span.End()
+func Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name)
+}
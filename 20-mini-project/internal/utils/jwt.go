@@ -2,12 +2,33 @@ package utils
 
 import (
 	"errors"
+	"sync/atomic"
 	"time"
 
-	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// ErrJWTSecretNotSet dikembalikan jika SetJWTSecret belum dipanggil
+var ErrJWTSecretNotSet = errors.New("jwt secret is not configured")
+
+// jwtSecret secret HMAC untuk sign dan verifikasi token, diisi saat startup
+var jwtSecret atomic.Pointer[[]byte]
+
+// SetJWTSecret mengatur secret yang dipakai GenerateToken dan ValidateToken
+func SetJWTSecret(secret string) {
+	key := []byte(secret)
+	jwtSecret.Store(&key)
+}
+
+// signingKey mengembalikan secret yang sudah diatur
+func signingKey() ([]byte, error) {
+	key := jwtSecret.Load()
+	if key == nil || len(*key) == 0 {
+		return nil, ErrJWTSecretNotSet
+	}
+	return *key, nil
+}
+
 // Claims struktur JWT claims
 type Claims struct {
 	UserID   uint   `json:"user_id"`
@@ -33,9 +54,12 @@ func GenerateToken(userID uint, username string) (string, error) {
 	// Buat token dengan claims
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	// Sign token dengan secret key dari env
-	cfg := config.LoadConfig()
-	tokenString, err := token.SignedString([]byte(cfg.JWTSecret))
+	// Sign token dengan secret key dari config
+	key, err := signingKey()
+	if err != nil {
+		return "", err
+	}
+	tokenString, err := token.SignedString(key)
 	if err != nil {
 		return "", err
 	}
@@ -48,13 +72,12 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	// Parse token
-	cfg := config.LoadConfig()
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		// Validasi algoritma
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
 		}
-		return []byte(cfg.JWTSecret), nil
+		return signingKey()
 	})
 
 	if err != nil {
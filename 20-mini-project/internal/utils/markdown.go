@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdownRenderer mengubah Markdown (GFM, termasuk task list) menjadi HTML
+var markdownRenderer = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+)
+
+// htmlPolicy membersihkan HTML hasil render dari tag/atribut berbahaya
+var htmlPolicy = newHTMLPolicy()
+
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+
+	// Izinkan checkbox dari task list: - [ ] item / - [x] item
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+
+	// Link eksternal dibuka di tab baru dan tidak mengirim referrer
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return policy
+}
+
+// RenderMarkdown mengubah teks Markdown menjadi HTML yang sudah disanitasi
+func RenderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdownRenderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return htmlPolicy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdownStripsUnsafeHTML(t *testing.T) {
+	for name, tc := range map[string]struct {
+		source   string
+		stripped []string
+		kept     []string
+	}{
+		"script tag": {
+			source:   "halo\n\n<script>alert(1)</script>",
+			stripped: []string{"<script", "alert(1)"},
+			kept:     []string{"<p>halo</p>"},
+		},
+		"event handler": {
+			source:   `<img src="https://example.com/a.png" onerror="alert(1)">`,
+			stripped: []string{"onerror", "alert(1)"},
+		},
+		"javascript link": {
+			source:   "[klik](javascript:alert(1))",
+			stripped: []string{"javascript:", "href"},
+			kept:     []string{"klik"},
+		},
+		"javascript link in html": {
+			source:   `<a href="javascript:alert(1)">klik</a>`,
+			stripped: []string{"javascript:", "href"},
+		},
+		"external link": {
+			source: "[docs](https://example.com)",
+			kept:   []string{`href="https://example.com"`, `target="_blank"`, `rel="nofollow noopener"`},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			html, err := RenderMarkdown(tc.source)
+			require.NoError(t, err)
+			for _, s := range tc.stripped {
+				assert.NotContains(t, html, s)
+			}
+			for _, s := range tc.kept {
+				assert.Contains(t, html, s)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownKeepsTaskList(t *testing.T) {
+	html, err := RenderMarkdown("- [ ] belum\n- [x] selesai")
+	require.NoError(t, err)
+
+	assert.Contains(t, html, `<input disabled="" type="checkbox"> belum`)
+	assert.Contains(t, html, `<input checked="" disabled="" type="checkbox"> selesai`)
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
 	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
 )
@@ -15,7 +17,10 @@ func main() {
 	log.Println("===========================================")
 
 	// Load configuration
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 
 	// Initialize database
 	db, err := config.NewDatabase(cfg)
@@ -25,6 +30,25 @@ func main() {
 
 	log.Println("✓ Connected to database")
 
+	// Pastikan schema ada, database baru belum punya tabel sama sekali
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database handle: %v", err)
+	}
+	migrations, err := migrate.Embedded(cfg.DBDriver)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	migrator, err := migrate.New(sqlDB, cfg.DBDriver, migrations)
+	if err != nil {
+		log.Fatalf("Failed to create migrator: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	log.Println("✓ Database schema is up to date")
+
 	// ============================================
 	// SEED USERS
 	// ============================================
@@ -82,43 +106,43 @@ func main() {
 		{
 			Title:       "Complete project documentation",
 			Description: "Write comprehensive README and API documentation",
-			Status:      model.TodoStatusInProgress,
-			Priority:    5,
+			Status:      "in_progress",
+			Priority:    "high",
 			UserID:      adminUser.ID,
 		},
 		{
 			Title:       "Review pull requests",
 			Description: "Review and merge pending pull requests",
-			Status:      model.TodoStatusPending,
-			Priority:    4,
+			Status:      "pending",
+			Priority:    "medium",
 			UserID:      adminUser.ID,
 		},
 		{
 			Title:       "Fix authentication bug",
 			Description: "Resolve token expiration issue",
-			Status:      model.TodoStatusCompleted,
-			Priority:    5,
+			Status:      "completed",
+			Priority:    "high",
 			UserID:      adminUser.ID,
 		},
 		{
 			Title:       "Learn Clean Architecture",
 			Description: "Study Clean Architecture patterns in Go",
-			Status:      model.TodoStatusInProgress,
-			Priority:    3,
+			Status:      "in_progress",
+			Priority:    "low",
 			UserID:      johnUser.ID,
 		},
 		{
 			Title:       "Build REST API",
 			Description: "Create a REST API using Gin framework",
-			Status:      model.TodoStatusPending,
-			Priority:    4,
+			Status:      "pending",
+			Priority:    "medium",
 			UserID:      johnUser.ID,
 		},
 		{
 			Title:       "Write unit tests",
 			Description: "Add unit tests for all services",
-			Status:      model.TodoStatusPending,
-			Priority:    3,
+			Status:      "pending",
+			Priority:    "low",
 			UserID:      johnUser.ID,
 		},
 	}
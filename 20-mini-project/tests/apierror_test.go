@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/apierror"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/handler"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVersionedAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	for version, prefix := range map[int]string{1: "/api/v1", 2: "/api/v2"} {
+		router.Group(prefix, middleware.APIVersion(version)).
+			GET("/todos", middleware.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+	return router
+}
+
+// Body v1 tetap memakai dto.ErrorResponse dengan message dan field error lama
+func TestAuthErrorsKeepV1Body(t *testing.T) {
+	router := newVersionedAuthRouter()
+
+	for header, want := range map[string]string{
+		"":           `{"success":false,"message":"Token tidak ditemukan","error":"authorization header is missing"}`,
+		"Token abc":  `{"success":false,"message":"Format token tidak valid","error":"authorization header must be 'Bearer \u003ctoken\u003e'"}`,
+		"Bearer a b": `{"success":false,"message":"Format token tidak valid","error":"authorization header must be 'Bearer \u003ctoken\u003e'"}`,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, header)
+		assert.Equal(t, want, w.Body.String(), header)
+	}
+
+	// Error dari validasi token tetap ikut seperti sebelumnya
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+	req.Header.Set("Authorization", "Bearer not-a-jwt")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var response dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Token tidak valid atau expired", response.Message)
+	assert.NotEmpty(t, response.Error)
+}
+
+func TestAuthErrorsUseProblemDetailsOnV2(t *testing.T) {
+	router := newVersionedAuthRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/todos", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, apierror.ProblemContentType, w.Header().Get("Content-Type"))
+	var problem dto.ProblemDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "token_missing", problem.Code)
+	assert.Equal(t, apierror.ErrTokenMissing.Error(), problem.Detail)
+	assert.Equal(t, "/api/v2/todos", problem.Instance)
+}
+
+// Error tanpa error asal (dibuat dengan apierror.New) tetap mengisi field error di v1
+func TestCodedErrorsKeepV1ErrorField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// Tanpa AuthMiddleware userID tidak ada di context
+	router.GET("/api/v1/todos", middleware.APIVersion(1), handler.NewTodoHandler(nil).GetAll)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"success":false,"message":"Unauthorized","error":"User ID not found in context"}`, w.Body.String())
+}
@@ -7,127 +7,110 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/adityapryg/golang-demo/20-mini-project/config"
-	"github.com/adityapryg/golang-demo/20-mini-project/handlers"
-	"github.com/adityapryg/golang-demo/20-mini-project/models"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/handler"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 type AuthTestSuite struct {
 	suite.Suite
+	db     *gorm.DB
 	router *gin.Engine
 }
 
 func (suite *AuthTestSuite) SetupSuite() {
 	// Setup database untuk testing
-	config.ConnectDatabase()
+	suite.db = newTestDatabase(suite.T())
+
+	userHandler := handler.NewUserHandler(service.NewAuthService(repository.NewUserRepository(suite.db)))
 
 	// Setup router
 	gin.SetMode(gin.TestMode)
-	suite.router = gin.Default()
+	suite.router = gin.New()
 
 	auth := suite.router.Group("/api/v1/auth")
 	{
-		auth.POST("/register", handlers.Register)
-		auth.POST("/login", handlers.Login)
+		auth.POST("/register", userHandler.Register)
+		auth.POST("/login", userHandler.Login)
 	}
 }
 
 func (suite *AuthTestSuite) TearDownTest() {
 	// Bersihkan data testing setelah setiap test
-	config.DB.Exec("DELETE FROM users WHERE username LIKE 'testuser%'")
+	suite.db.Exec("DELETE FROM users WHERE username LIKE 'testuser%'")
+}
+
+func (suite *AuthTestSuite) post(path string, body any) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
 }
 
 func (suite *AuthTestSuite) TestRegisterSuccess() {
-	reqBody := models.UserRegisterRequest{
+	w := suite.post("/api/v1/auth/register", dto.UserRegisterRequest{
 		Username: "testuser1",
 		Email:    "testuser1@example.com",
 		Password: "password123",
 		FullName: "Test User",
-	}
-
-	jsonBody, _ := json.Marshal(reqBody)
-	req, _ := http.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-
-	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req)
+	})
 
 	assert.Equal(suite.T(), 201, w.Code)
 
-	var response models.APIResponse
+	var response dto.SuccessResponse
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.True(suite.T(), response.Success)
-	assert.Equal(suite.T(), "Registrasi berhasil", response.Message)
+	assert.Equal(suite.T(), "User registered successfully", response.Message)
 }
 
 func (suite *AuthTestSuite) TestRegisterDuplicateUsername() {
-	// Register pertama kali
-	reqBody := models.UserRegisterRequest{
+	reqBody := dto.UserRegisterRequest{
 		Username: "testuser2",
 		Email:    "testuser2@example.com",
 		Password: "password123",
 		FullName: "Test User 2",
 	}
 
-	jsonBody, _ := json.Marshal(reqBody)
-	req, _ := http.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-
-	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req)
-
-	// Register dengan username yang sama
-	req2, _ := http.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(jsonBody))
-	req2.Header.Set("Content-Type", "application/json")
-
-	w2 := httptest.NewRecorder()
-	suite.router.ServeHTTP(w2, req2)
+	// Register pertama kali, lalu dengan username yang sama
+	suite.post("/api/v1/auth/register", reqBody)
+	w := suite.post("/api/v1/auth/register", reqBody)
 
-	assert.Equal(suite.T(), 400, w2.Code)
+	assert.Equal(suite.T(), 400, w.Code)
 
-	var response models.APIResponse
-	json.Unmarshal(w2.Body.Bytes(), &response)
+	var response dto.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.False(suite.T(), response.Success)
-	assert.Equal(suite.T(), "Username sudah digunakan", response.Message)
+	assert.Equal(suite.T(), "username or email already exists", response.Message)
 }
 
 func (suite *AuthTestSuite) TestLoginSuccess() {
 	// Register user dulu
-	registerBody := models.UserRegisterRequest{
+	suite.post("/api/v1/auth/register", dto.UserRegisterRequest{
 		Username: "testuser3",
 		Email:    "testuser3@example.com",
 		Password: "password123",
 		FullName: "Test User 3",
-	}
-
-	jsonBody, _ := json.Marshal(registerBody)
-	req, _ := http.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req)
+	})
 
-	// Login
-	loginBody := models.UserLoginRequest{
+	w := suite.post("/api/v1/auth/login", dto.UserLoginRequest{
 		Username: "testuser3",
 		Password: "password123",
-	}
-
-	jsonBody, _ = json.Marshal(loginBody)
-	req2, _ := http.NewRequest("POST", "/api/v1/auth/login", bytes.NewBuffer(jsonBody))
-	req2.Header.Set("Content-Type", "application/json")
+	})
 
-	w2 := httptest.NewRecorder()
-	suite.router.ServeHTTP(w2, req2)
+	assert.Equal(suite.T(), 200, w.Code)
 
-	assert.Equal(suite.T(), 200, w2.Code)
-
-	var response models.APIResponse
-	json.Unmarshal(w2.Body.Bytes(), &response)
+	var response dto.SuccessResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.True(suite.T(), response.Success)
-	assert.Equal(suite.T(), "Login berhasil", response.Message)
+	assert.Equal(suite.T(), "Login successful", response.Message)
 
 	// Cek token ada di response
 	data := response.Data.(map[string]interface{})
@@ -136,38 +119,25 @@ func (suite *AuthTestSuite) TestLoginSuccess() {
 
 func (suite *AuthTestSuite) TestLoginWrongPassword() {
 	// Register user dulu
-	registerBody := models.UserRegisterRequest{
+	suite.post("/api/v1/auth/register", dto.UserRegisterRequest{
 		Username: "testuser4",
 		Email:    "testuser4@example.com",
 		Password: "password123",
 		FullName: "Test User 4",
-	}
-
-	jsonBody, _ := json.Marshal(registerBody)
-	req, _ := http.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req)
+	})
 
 	// Login dengan password salah
-	loginBody := models.UserLoginRequest{
+	w := suite.post("/api/v1/auth/login", dto.UserLoginRequest{
 		Username: "testuser4",
 		Password: "wrongpassword",
-	}
-
-	jsonBody, _ = json.Marshal(loginBody)
-	req2, _ := http.NewRequest("POST", "/api/v1/auth/login", bytes.NewBuffer(jsonBody))
-	req2.Header.Set("Content-Type", "application/json")
+	})
 
-	w2 := httptest.NewRecorder()
-	suite.router.ServeHTTP(w2, req2)
+	assert.Equal(suite.T(), 401, w.Code)
 
-	assert.Equal(suite.T(), 401, w2.Code)
-
-	var response models.APIResponse
-	json.Unmarshal(w2.Body.Bytes(), &response)
+	var response dto.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.False(suite.T(), response.Success)
-	assert.Equal(suite.T(), "Username atau password salah", response.Message)
+	assert.Equal(suite.T(), "invalid username or password", response.Message)
 }
 
 func TestAuthTestSuite(t *testing.T) {
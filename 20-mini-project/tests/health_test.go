@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/handler"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/health"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/metrics"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMonitoringToken = "monitoring-secret"
+
+func newHealthRouter(t *testing.T, checkers ...health.Checker) *gin.Engine {
+	t.Helper()
+	checks := health.New("test", time.Second)
+	checks.Register(health.DatabaseChecker(newTestDatabase(t)))
+	checks.Register(checkers...)
+	healthHandler := handler.NewHealthHandler(checks, testMonitoringToken)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health", healthHandler.HealthCheck)
+	router.GET("/health/ready", healthHandler.Ready)
+	router.GET("/metrics", middleware.MonitoringAuth(testMonitoringToken), gin.WrapH(metrics.Handler()))
+	return router
+}
+
+func getWithToken(router *gin.Engine, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLegacyHealthBody(t *testing.T) {
+	router := newHealthRouter(t)
+
+	w := getWithToken(router, "/health", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok","message":"API is running","database":"connected"}`, w.Body.String())
+
+	// Komponen lain yang down tidak mengubah /health, sama seperti sebelumnya
+	router = newHealthRouter(t, health.CheckFunc("cache", func(ctx context.Context) error {
+		return errors.New("cache.internal:6379 unreachable")
+	}))
+	w = getWithToken(router, "/health", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok","message":"API is running","database":"connected"}`, w.Body.String())
+}
+
+func TestReadinessDetailsRequireMonitoringToken(t *testing.T) {
+	router := newHealthRouter(t, health.CheckFunc("cache", func(ctx context.Context) error {
+		return errors.New("cache.internal:6379 unreachable")
+	}))
+
+	for _, token := range []string{"", "wrong"} {
+		w := getWithToken(router, "/health/ready", token)
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.NotContains(t, w.Body.String(), "cache.internal")
+		assert.NotContains(t, w.Body.String(), "open_connections")
+
+		var report health.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, health.Component{Status: health.StatusUp}, report.Components["database"])
+		assert.Equal(t, health.Component{Status: health.StatusDown}, report.Components["cache"])
+	}
+
+	w := getWithToken(router, "/health/ready", testMonitoringToken)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Contains(t, report.Components["cache"].Error, "cache.internal")
+	assert.Contains(t, report.Components["database"].Details, "open_connections")
+}
+
+func TestMetricsRequireMonitoringToken(t *testing.T) {
+	router := newHealthRouter(t)
+
+	assert.Equal(t, http.StatusUnauthorized, getWithToken(router, "/metrics", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, getWithToken(router, "/metrics", "wrong").Code)
+	assert.Equal(t, http.StatusOK, getWithToken(router, "/metrics", testMonitoringToken).Code)
+
+	// Tanpa token yang dikonfigurasi /metrics ditutup
+	gin.SetMode(gin.TestMode)
+	closed := gin.New()
+	closed.GET("/metrics", middleware.MonitoringAuth(""), gin.WrapH(metrics.Handler()))
+	assert.Equal(t, http.StatusForbidden, getWithToken(closed, "/metrics", "").Code)
+	assert.Equal(t, http.StatusForbidden, getWithToken(closed, "/metrics", "Bearer ").Code)
+}
@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// idempotencyTest router dengan middleware Idempotency di depan handler
+// yang menghitung berapa kali request benar-benar dieksekusi
+type idempotencyTest struct {
+	db     *gorm.DB
+	router *gin.Engine
+	calls  atomic.Int32
+	// status response handler, default 201
+	status atomic.Int32
+	// release, jika tidak nil, menahan handler sampai channel ditutup
+	release chan struct{}
+	started chan struct{}
+}
+
+func newIdempotencyTest(t *testing.T) *idempotencyTest {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	it := &idempotencyTest{db: newTestDatabase(t)}
+	it.status.Store(http.StatusCreated)
+	idempotencyService := service.NewIdempotencyService(repository.NewIdempotencyRepository(it.db), time.Hour)
+
+	it.router = gin.New()
+	it.router.POST("/todos",
+		func(c *gin.Context) { c.Set("userID", uint(1)) },
+		middleware.Idempotency(idempotencyService),
+		func(c *gin.Context) {
+			n := it.calls.Add(1)
+			if it.release != nil {
+				close(it.started)
+				<-it.release
+			}
+			c.JSON(int(it.status.Load()), gin.H{"call": n})
+		},
+	)
+	return it
+}
+
+func (it *idempotencyTest) post(key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	it.router.ServeHTTP(w, req)
+	return w
+}
+
+func responseCall(t *testing.T, w *httptest.ResponseRecorder) int {
+	t.Helper()
+	var body struct {
+		Call int `json:"call"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	return body.Call
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	it := newIdempotencyTest(t)
+
+	first := it.post("key-1", `{"title":"a"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+
+	replay := it.post("key-1", `{"title":"a"}`)
+	assert.Equal(t, http.StatusCreated, replay.Code)
+	assert.Equal(t, "true", replay.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, first.Header().Get("Content-Type"), replay.Header().Get("Content-Type"))
+	assert.Equal(t, int32(1), it.calls.Load(), "handler runs once")
+
+	// Key lain, atau tanpa key, dieksekusi normal
+	assert.Equal(t, 2, responseCall(t, it.post("key-2", `{"title":"a"}`)))
+	assert.Equal(t, 3, responseCall(t, it.post("", `{"title":"a"}`)))
+}
+
+func TestIdempotencyRejectsKeyReusedWithDifferentBody(t *testing.T) {
+	it := newIdempotencyTest(t)
+
+	require.Equal(t, http.StatusCreated, it.post("key-1", `{"title":"a"}`).Code)
+
+	w := it.post("key-1", `{"title":"b"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrIdempotencyKeyReused.Error())
+	assert.Equal(t, int32(1), it.calls.Load())
+}
+
+func TestIdempotencyRejectsConcurrentRequest(t *testing.T) {
+	it := newIdempotencyTest(t)
+	it.release = make(chan struct{})
+	it.started = make(chan struct{})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- it.post("key-1", `{"title":"a"}`) }()
+	<-it.started
+
+	// Request pertama masih ditahan di handler
+	w := it.post("key-1", `{"title":"a"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrIdempotencyInProgress.Error())
+
+	close(it.release)
+	first := <-done
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	it.release = nil
+	replay := it.post("key-1", `{"title":"a"}`)
+	assert.Equal(t, "true", replay.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, int32(1), it.calls.Load())
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	it := newIdempotencyTest(t)
+	it.status.Store(http.StatusInternalServerError)
+
+	require.Equal(t, http.StatusInternalServerError, it.post("key-1", `{"title":"a"}`).Code)
+
+	// Response 5xx tidak disimpan, retry dengan key yang sama dieksekusi lagi
+	it.status.Store(http.StatusCreated)
+	w := it.post("key-1", `{"title":"a"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, 2, responseCall(t, w))
+
+	// Response 4xx disimpan seperti response sukses
+	it.status.Store(http.StatusBadRequest)
+	require.Equal(t, http.StatusBadRequest, it.post("key-2", `{}`).Code)
+	it.status.Store(http.StatusCreated)
+	replay := it.post("key-2", `{}`)
+	assert.Equal(t, http.StatusBadRequest, replay.Code)
+	assert.Equal(t, "true", replay.Header().Get(middleware.IdempotentReplayedHeader))
+}
+
+func TestIdempotencyKeyExpires(t *testing.T) {
+	it := newIdempotencyTest(t)
+
+	require.Equal(t, http.StatusCreated, it.post("key-1", `{"title":"a"}`).Code)
+	require.NoError(t, it.db.Model(&model.IdempotencyKey{}).Where("key = ?", "key-1").
+		Update("expires_at", time.Now().Add(-time.Second)).Error)
+
+	// Key kedaluwarsa boleh dipakai lagi, bahkan untuk body yang berbeda
+	w := it.post("key-1", `{"title":"b"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, 2, responseCall(t, w))
+
+	var count int64
+	require.NoError(t, it.db.Model(&model.IdempotencyKey{}).Where("key = ?", "key-1").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// Reservasi yang tidak pernah selesai (misalnya server crash) dilepas setelah lock timeout
+	require.NoError(t, it.db.Create(&model.IdempotencyKey{
+		UserID: 1, Key: "key-2", Method: http.MethodPost, Path: "/todos", Fingerprint: "abandoned",
+		CreatedAt: time.Now().Add(-2 * time.Minute), ExpiresAt: time.Now().Add(time.Hour),
+	}).Error)
+	w = it.post("key-2", `{"title":"a"}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, 3, responseCall(t, w))
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/metrics"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/route"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMetricsRouter memasang route aplikasi dengan middleware Metrics seperti
+// di main. Handler tidak dibutuhkan: request berhenti di AuthMiddleware.
+func newMetricsRouter(monitoringToken string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Metrics())
+
+	next := func(c *gin.Context) { c.Next() }
+	route.SetupRoutes(router, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, route.Middlewares{
+		Idempotency:   next,
+		IPRateLimit:   next,
+		RateLimit:     next,
+		AuthRateLimit: next,
+		Monitoring:    middleware.MonitoringAuth(monitoringToken),
+	})
+	return router
+}
+
+func serve(router *gin.Engine, path, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHTTPMetricsUseRouteTemplate(t *testing.T) {
+	router := newMetricsRouter("monitoring-secret")
+	counter := func(path string) float64 {
+		return testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, path, "401"))
+	}
+	before := counter("/api/v1/todos/:id")
+
+	for _, path := range []string{"/api/v1/todos/1", "/api/v1/todos/2", "/api/v1/todos/3"} {
+		assert.Equal(t, http.StatusUnauthorized, serve(router, path, "").Code)
+	}
+	assert.Equal(t, before+3, counter("/api/v1/todos/:id"))
+
+	// Path yang tidak cocok dengan route manapun digabung ke satu label
+	unmatched := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "unmatched", "404"))
+	assert.Equal(t, http.StatusNotFound, serve(router, "/wp-login.php", "").Code)
+	assert.Equal(t, unmatched+1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "unmatched", "404")))
+
+	body := serve(router, "/metrics", "Bearer monitoring-secret").Body.String()
+	assert.Contains(t, body, `route="/api/v1/todos/:id"`)
+	assert.NotContains(t, body, `route="/api/v1/todos/1"`)
+	assert.NotContains(t, body, "wp-login")
+}
+
+func TestMetricsEndpointRequiresMonitoringToken(t *testing.T) {
+	router := newMetricsRouter("monitoring-secret")
+
+	for authorization, want := range map[string]int{
+		"":                         http.StatusUnauthorized,
+		"Bearer wrong":             http.StatusUnauthorized,
+		"monitoring-secret":        http.StatusUnauthorized,
+		"Bearer monitoring-secret": http.StatusOK,
+	} {
+		w := serve(router, "/metrics", authorization)
+		assert.Equal(t, want, w.Code, authorization)
+		if want == http.StatusOK {
+			assert.Contains(t, w.Body.String(), "todolist_http_requests_total")
+		} else {
+			assert.NotContains(t, w.Body.String(), "todolist_", authorization)
+		}
+	}
+
+	// Tanpa MONITORING_TOKEN endpoint ditutup, token apa pun ditolak
+	router = newMetricsRouter("")
+	for _, authorization := range []string{"", "Bearer "} {
+		w := serve(router, "/metrics", authorization)
+		assert.Equal(t, http.StatusForbidden, w.Code, authorization)
+		assert.NotContains(t, w.Body.String(), "todolist_")
+	}
+}
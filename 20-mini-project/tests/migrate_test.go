@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationsUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+
+	migrations, err := migrate.Embedded(config.DriverSQLite)
+	require.NoError(t, err)
+	migrator, err := migrate.New(sqlDB, config.DriverSQLite, migrations)
+	require.NoError(t, err)
+
+	// newTestDatabase sudah menerapkan semua migrasi, up kedua tidak melakukan apa-apa
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	pending, version, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+	assert.Equal(t, migrations[len(migrations)-1].Version, version)
+
+	// Rollback semua lalu terapkan ulang
+	reverted, err := migrator.Down(ctx, len(migrations))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(migrations))
+	assert.False(t, db.Migrator().HasTable("todos"))
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations))
+	assert.True(t, db.Migrator().HasTable("todos"))
+}
+
+func TestMigrationChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+
+	migrations, err := migrate.Embedded(config.DriverSQLite)
+	require.NoError(t, err)
+
+	// Migrasi yang sudah diterapkan tidak boleh diubah
+	migrations[0].Checksum = "changed"
+	migrator, err := migrate.New(sqlDB, config.DriverSQLite, migrations)
+	require.NoError(t, err)
+
+	_, err = migrator.Up(ctx)
+	assert.ErrorIs(t, err, migrate.ErrChecksumMismatch)
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedRouter(t *testing.T, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies(trustedProxies))
+	limiter := middleware.NewRateLimiter(middleware.NewMemoryRateLimitStore(time.Minute))
+	limit := middleware.RateLimit{Requests: 2, Period: time.Minute}
+
+	router.POST("/auth/login", limiter.Limit("auth", limit), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/todos", limiter.Limit("ip", limit), middleware.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func send(router *gin.Engine, method, path, remoteAddr, forwardedFor, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+// X-Forwarded-For dari client yang bukan proxy terpercaya tidak boleh
+// menghasilkan bucket baru
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	router := newRateLimitedRouter(t, nil)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, send(router, http.MethodPost, "/auth/login", "203.0.113.7:5000", fmt.Sprintf("198.51.100.%d", i), ""))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send(router, http.MethodPost, "/auth/login", "203.0.113.7:5000", "198.51.100.99", ""))
+}
+
+// Dari proxy terpercaya, IP client di X-Forwarded-For yang menentukan bucket
+func TestRateLimitUsesForwardedForFromTrustedProxy(t *testing.T) {
+	router := newRateLimitedRouter(t, []string{"10.0.0.0/8"})
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, send(router, http.MethodPost, "/auth/login", "10.0.0.5:5000", "198.51.100.1", ""))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send(router, http.MethodPost, "/auth/login", "10.0.0.5:5000", "198.51.100.1", ""))
+	assert.Equal(t, http.StatusOK, send(router, http.MethodPost, "/auth/login", "10.0.0.5:5000", "198.51.100.2", ""))
+}
+
+// Limit per IP dipasang sebelum AuthMiddleware, jadi token tidak valid ikut dihitung
+func TestRateLimitAppliesBeforeAuth(t *testing.T) {
+	router := newRateLimitedRouter(t, nil)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusUnauthorized, send(router, http.MethodGet, "/todos", "203.0.113.8:5000", "", "invalid"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send(router, http.MethodGet, "/todos", "203.0.113.8:5000", "", "invalid"))
+}
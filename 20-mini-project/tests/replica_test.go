@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestReadReplicaRouting memakai dua database in-memory terpisah sebagai primary
+// dan replica. Todo hanya ada di primary, jadi hasil FindByID menunjukkan
+// koneksi mana yang dipakai.
+func TestReadReplicaRouting(t *testing.T) {
+	ctx := context.Background()
+	primary := newTestDatabase(t)
+	replica := newTestDatabase(t)
+
+	user := model.User{Username: "replica", Email: "replica@example.com", Password: "x", FullName: "Replica"}
+	require.NoError(t, primary.Create(&user).Error)
+	require.NoError(t, replica.Create(&user).Error)
+
+	cluster := database.NewCluster(primary, database.Options{}, database.Replica{Name: "replica-1", DB: replica})
+	todoRepo := repository.NewTodoRepository(cluster)
+	todo := &model.Todo{UserID: user.ID, Title: "written to primary", Status: "pending", Priority: "medium"}
+	require.NoError(t, todoRepo.Create(ctx, todo))
+
+	// Replica belum lolos health check, baca dari primary
+	_, err := todoRepo.FindByID(ctx, todo.ID)
+	require.NoError(t, err)
+
+	// Replica sehat: query read-only ke replica, kecuali ctx ditandai WithPrimary
+	cluster.Check(ctx)
+	require.True(t, cluster.Replicas()[0].Healthy)
+	_, err = todoRepo.FindByID(ctx, todo.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	todos, err := todoRepo.FindByUserIDWithFilters(ctx, user.ID, "", "")
+	require.NoError(t, err)
+	assert.Empty(t, todos)
+	_, err = todoRepo.FindByID(database.WithPrimary(ctx), todo.ID)
+	assert.NoError(t, err)
+
+	// Replica mati: health check menandainya tidak sehat dan baca kembali ke primary
+	sqlDB, err := replica.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	cluster.Check(ctx)
+	status := cluster.Replicas()[0]
+	assert.False(t, status.Healthy)
+	assert.NotEmpty(t, status.Error)
+	_, err = todoRepo.FindByID(ctx, todo.ID)
+	assert.NoError(t, err)
+}
+
+// Server primary yang didaftarkan sebagai replica tidak boleh dianggap sehat
+// hanya karena fungsi WAL-nya mengembalikan NULL (lag 0). Set TEST_POSTGRES_DSN
+// ke server Postgres yang bukan standby untuk menjalankannya.
+func TestReplicaCheckRejectsNonStandby(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	notStandby, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := notStandby.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	cluster := database.NewCluster(newTestDatabase(t), database.Options{MaxLag: time.Minute}, database.Replica{Name: "primary-as-replica", DB: notStandby})
+	cluster.Check(context.Background())
+
+	status := cluster.Replicas()[0]
+	assert.False(t, status.Healthy)
+	assert.Equal(t, database.ErrNotStandby.Error(), status.Error)
+}
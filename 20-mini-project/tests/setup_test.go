@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/config"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/migrate"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// testConfig config untuk test: SQLite in-memory, jadi tidak butuh Postgres
+func testConfig() *config.Config {
+	cfg := config.Default()
+	cfg.DBDriver = config.DriverSQLite
+	cfg.DBName = config.SQLiteMemory
+	cfg.DBLogLevel = "silent"
+	cfg.GinMode = "test"
+	return cfg
+}
+
+// testTodoSettings feature flag todo dari testConfig
+func testTodoSettings() service.TodoSettings {
+	cfg := testConfig()
+	return service.TodoSettings{BlockCompletion: cfg.TodoBlockCompletion, WIPLimit: cfg.WIPLimit}
+}
+
+// newTestDatabase membuat database baru dengan schema dari migrasi SQLite.
+// Koneksi ditutup otomatis setelah test selesai.
+func newTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	cfg := testConfig()
+	utils.SetJWTSecret(cfg.JWTSecret)
+
+	db, err := config.NewDatabase(cfg)
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrations, err := migrate.Embedded(cfg.DBDriver)
+	require.NoError(t, err)
+	migrator, err := migrate.New(sqlDB, cfg.DBDriver, migrations)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
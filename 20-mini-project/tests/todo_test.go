@@ -6,20 +6,27 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
-	"github.com/adityapryg/golang-demo/20-mini-project/config"
-	"github.com/adityapryg/golang-demo/20-mini-project/handlers"
-	"github.com/adityapryg/golang-demo/20-mini-project/middleware"
-	"github.com/adityapryg/golang-demo/20-mini-project/models"
-	"github.com/adityapryg/golang-demo/20-mini-project/utils"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/handler"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 type TodoTestSuite struct {
 	suite.Suite
+	db     *gorm.DB
 	router *gin.Engine
 	token  string
 	userID uint
@@ -27,11 +34,19 @@ type TodoTestSuite struct {
 
 func (suite *TodoTestSuite) SetupSuite() {
 	// Setup database untuk testing
-	config.ConnectDatabase()
+	suite.db = newTestDatabase(suite.T())
+
+	todoService := service.NewTodoService(
+		repository.NewTodoRepository(database.NewCluster(suite.db, database.Options{})),
+		repository.NewBoardRepository(suite.db),
+		event.NewBus(),
+		testTodoSettings,
+	)
+	todoHandler := handler.NewTodoHandler(todoService)
 
 	// Setup router
 	gin.SetMode(gin.TestMode)
-	suite.router = gin.Default()
+	suite.router = gin.New()
 
 	// Setup routes
 	v1 := suite.router.Group("/api/v1")
@@ -39,65 +54,63 @@ func (suite *TodoTestSuite) SetupSuite() {
 		todos := v1.Group("/todos")
 		todos.Use(middleware.AuthMiddleware())
 		{
-			todos.POST("", handlers.CreateTodo)
-			todos.GET("", handlers.GetAllTodos)
-			todos.GET("/:id", handlers.GetTodoByID)
-			todos.PUT("/:id", handlers.UpdateTodo)
-			todos.DELETE("/:id", handlers.DeleteTodo)
+			todos.POST("", todoHandler.Create)
+			todos.GET("", todoHandler.GetAll)
+			todos.GET("/:id", todoHandler.GetByID)
+			todos.PUT("/:id", todoHandler.Update)
+			todos.DELETE("/:id", todoHandler.Delete)
 		}
 	}
 
 	// Buat user dan token untuk testing
 	hashedPassword, _ := utils.HashPassword("testpassword")
-	user := models.User{
+	user := model.User{
 		Username: "todotest",
 		Email:    "todotest@example.com",
 		Password: hashedPassword,
 		FullName: "Todo Test User",
 	}
-	config.DB.Create(&user)
+	suite.db.Create(&user)
 	suite.userID = user.ID
 
 	suite.token, _ = utils.GenerateToken(user.ID, user.Username)
 }
 
-func (suite *TodoTestSuite) TearDownSuite() {
-	// Bersihkan data testing
-	config.DB.Exec("DELETE FROM todos WHERE user_id = ?", suite.userID)
-	config.DB.Exec("DELETE FROM users WHERE id = ?", suite.userID)
-}
-
-func (suite *TodoTestSuite) TestCreateTodoSuccess() {
-	reqBody := models.TodoCreateRequest{
-		Title:       "Test Todo",
-		Description: "This is a test todo",
-		Status:      models.TodoStatusPending,
-		Priority:    1,
+func (suite *TodoTestSuite) request(method, path string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
 	}
-
-	jsonBody, _ := json.Marshal(reqBody)
-	req, _ := http.NewRequest("POST", "/api/v1/todos", bytes.NewBuffer(jsonBody))
+	req, _ := http.NewRequest(method, path, &buf)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+suite.token)
 
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *TodoTestSuite) TestCreateTodoSuccess() {
+	w := suite.request("POST", "/api/v1/todos", dto.CreateTodoRequest{
+		Title:       "Test Todo",
+		Description: "This is a test todo",
+		Status:      "pending",
+		Priority:    "high",
+	})
 
 	assert.Equal(suite.T(), 201, w.Code)
 
-	var response models.APIResponse
+	var response dto.SuccessResponse
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.True(suite.T(), response.Success)
-	assert.Equal(suite.T(), "Todo berhasil dibuat", response.Message)
+	assert.Equal(suite.T(), "Todo created successfully", response.Message)
 }
 
 func (suite *TodoTestSuite) TestCreateTodoWithoutAuth() {
-	reqBody := models.TodoCreateRequest{
+	jsonBody, _ := json.Marshal(dto.CreateTodoRequest{
 		Title:       "Test Todo Without Auth",
 		Description: "This should fail",
-	}
-
-	jsonBody, _ := json.Marshal(reqBody)
+	})
 	req, _ := http.NewRequest("POST", "/api/v1/todos", bytes.NewBuffer(jsonBody))
 	req.Header.Set("Content-Type", "application/json")
 	// Tidak ada Authorization header
@@ -110,23 +123,19 @@ func (suite *TodoTestSuite) TestCreateTodoWithoutAuth() {
 
 func (suite *TodoTestSuite) TestGetAllTodos() {
 	// Buat beberapa todos dulu
-	todos := []models.Todo{
-		{Title: "Todo 1", Description: "Description 1", UserID: suite.userID, Status: models.TodoStatusPending},
-		{Title: "Todo 2", Description: "Description 2", UserID: suite.userID, Status: models.TodoStatusCompleted},
+	todos := []model.Todo{
+		{Title: "Todo 1", Description: "Description 1", UserID: suite.userID, Status: "pending"},
+		{Title: "Todo 2", Description: "Description 2", UserID: suite.userID, Status: "completed"},
 	}
 	for _, todo := range todos {
-		config.DB.Create(&todo)
+		suite.db.Create(&todo)
 	}
 
-	req, _ := http.NewRequest("GET", "/api/v1/todos", nil)
-	req.Header.Set("Authorization", "Bearer "+suite.token)
-
-	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req)
+	w := suite.request("GET", "/api/v1/todos", nil)
 
 	assert.Equal(suite.T(), 200, w.Code)
 
-	var response models.APIResponse
+	var response dto.SuccessResponse
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.True(suite.T(), response.Success)
 
@@ -137,67 +146,157 @@ func (suite *TodoTestSuite) TestGetAllTodos() {
 
 func (suite *TodoTestSuite) TestUpdateTodo() {
 	// Buat todo dulu
-	todo := models.Todo{
+	todo := model.Todo{
 		Title:       "Original Title",
 		Description: "Original Description",
 		UserID:      suite.userID,
-		Status:      models.TodoStatusPending,
+		Status:      "pending",
 	}
-	config.DB.Create(&todo)
+	suite.db.Create(&todo)
 
 	// Update todo
-	updateBody := models.TodoUpdateRequest{
-		Title:  "Updated Title",
-		Status: models.TodoStatusCompleted,
-	}
-
-	jsonBody, _ := json.Marshal(updateBody)
-	url := fmt.Sprintf("/api/v1/todos/%d", todo.ID)
-	req, _ := http.NewRequest("PUT", url, bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+suite.token)
-
-	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req)
+	title, status := "Updated Title", "completed"
+	w := suite.request("PUT", fmt.Sprintf("/api/v1/todos/%d", todo.ID), dto.UpdateTodoRequest{
+		Title:  &title,
+		Status: &status,
+	})
 
 	assert.Equal(suite.T(), 200, w.Code)
 
-	var response models.APIResponse
+	var response dto.SuccessResponse
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.True(suite.T(), response.Success)
-	assert.Equal(suite.T(), "Todo berhasil diupdate", response.Message)
+	assert.Equal(suite.T(), "Todo updated successfully", response.Message)
 }
 
 func (suite *TodoTestSuite) TestDeleteTodo() {
 	// Buat todo dulu
-	todo := models.Todo{
+	todo := model.Todo{
 		Title:       "To Be Deleted",
 		Description: "This will be deleted",
 		UserID:      suite.userID,
-		Status:      models.TodoStatusPending,
+		Status:      "pending",
 	}
-	config.DB.Create(&todo)
+	suite.db.Create(&todo)
 
-	// Delete todo
-	url := fmt.Sprintf("/api/v1/todos/%d", todo.ID)
-	req, _ := http.NewRequest("DELETE", url, nil)
-	req.Header.Set("Authorization", "Bearer "+suite.token)
-
-	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req)
+	w := suite.request("DELETE", fmt.Sprintf("/api/v1/todos/%d", todo.ID), nil)
 
 	assert.Equal(suite.T(), 200, w.Code)
 
-	var response models.APIResponse
+	var response dto.SuccessResponse
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.True(suite.T(), response.Success)
-	assert.Equal(suite.T(), "Todo berhasil dihapus", response.Message)
+	assert.Equal(suite.T(), "Todo deleted successfully", response.Message)
 
 	// Verifikasi todo sudah dihapus (soft delete)
-	var deletedTodo models.Todo
-	err := config.DB.Unscoped().First(&deletedTodo, todo.ID).Error
+	var deletedTodo model.Todo
+	err := suite.db.Unscoped().First(&deletedTodo, todo.ID).Error
 	assert.NoError(suite.T(), err)
-	assert.NotNil(suite.T(), deletedTodo.DeletedAt)
+	assert.True(suite.T(), deletedTodo.DeletedAt.Valid)
+}
+
+// countQueries menjalankan fn dan menghitung SELECT yang dikirim ke database
+func (suite *TodoTestSuite) countQueries(fn func()) int {
+	var count atomic.Int32
+	name := "test:count_queries"
+	suite.Require().NoError(suite.db.Callback().Query().After("gorm:query").Register(name, func(*gorm.DB) { count.Add(1) }))
+	defer suite.db.Callback().Query().Remove(name)
+
+	fn()
+	return int(count.Load())
+}
+
+func decodeData[T any](suite *TodoTestSuite, w *httptest.ResponseRecorder) T {
+	var response struct {
+		Data T `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+	return response.Data
+}
+
+func keys(m map[string]any) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
+
+func (suite *TodoTestSuite) TestSparseFieldsets() {
+	todo := model.Todo{Title: "Sparse", Description: "Long description", UserID: suite.userID, Status: "pending"}
+	suite.db.Create(&todo)
+
+	w := suite.request("GET", fmt.Sprintf("/api/v1/todos/%d?fields=id,title", todo.ID), nil)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	single := decodeData[map[string]any](suite, w)
+	assert.ElementsMatch(suite.T(), []string{"id", "title"}, keys(single))
+	assert.Equal(suite.T(), "Sparse", single["title"])
+
+	w = suite.request("GET", "/api/v1/todos?fields=id,+status,id", nil)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	list := decodeData[[]map[string]any](suite, w)
+	suite.Require().NotEmpty(list)
+	for _, item := range list {
+		assert.ElementsMatch(suite.T(), []string{"id", "status"}, keys(item))
+	}
+
+	// Relasi yang di-expand ikut dikirim walaupun tidak ada di ?fields=
+	w = suite.request("GET", fmt.Sprintf("/api/v1/todos/%d?fields=title&expand=user", todo.ID), nil)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	single = decodeData[map[string]any](suite, w)
+	assert.ElementsMatch(suite.T(), []string{"title", "user"}, keys(single))
+}
+
+func (suite *TodoTestSuite) TestExpandUserIsPreloaded() {
+	for i := 0; i < 3; i++ {
+		suite.db.Create(&model.Todo{Title: fmt.Sprintf("Expand %d", i), UserID: suite.userID, Status: "pending"})
+	}
+
+	var plain []dto.TodoResponse
+	plainQueries := suite.countQueries(func() {
+		w := suite.request("GET", "/api/v1/todos", nil)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		plain = decodeData[[]dto.TodoResponse](suite, w)
+	})
+	for _, todo := range plain {
+		assert.Nil(suite.T(), todo.User, "user is opt-in")
+	}
+
+	var expanded []dto.TodoResponse
+	expandedQueries := suite.countQueries(func() {
+		w := suite.request("GET", "/api/v1/todos?expand=user", nil)
+		suite.Require().Equal(200, w.Code, w.Body.String())
+		expanded = decodeData[[]dto.TodoResponse](suite, w)
+	})
+	suite.Require().GreaterOrEqual(len(expanded), 3)
+	for _, todo := range expanded {
+		suite.Require().NotNil(todo.User)
+		assert.Equal(suite.T(), suite.userID, todo.User.ID)
+		assert.Equal(suite.T(), "todotest", todo.User.Username)
+	}
+	// Satu query tambahan untuk seluruh list, bukan satu per todo
+	assert.Equal(suite.T(), plainQueries+1, expandedQueries)
+
+	w := suite.request("GET", fmt.Sprintf("/api/v1/todos/%d?expand=user", expanded[0].ID), nil)
+	suite.Require().Equal(200, w.Code, w.Body.String())
+	single := decodeData[dto.TodoResponse](suite, w)
+	suite.Require().NotNil(single.User)
+	assert.Equal(suite.T(), suite.userID, single.User.ID)
+}
+
+func (suite *TodoTestSuite) TestUnknownFieldsAndExpandAreRejected() {
+	todo := model.Todo{Title: "Rejected", UserID: suite.userID, Status: "pending"}
+	suite.db.Create(&todo)
+
+	for _, path := range []string{"/api/v1/todos", fmt.Sprintf("/api/v1/todos/%d", todo.ID)} {
+		w := suite.request("GET", path+"?fields=id,password", nil)
+		assert.Equal(suite.T(), 400, w.Code, path)
+		assert.Contains(suite.T(), w.Body.String(), "unknown field: password", path)
+
+		w = suite.request("GET", path+"?expand=project", nil)
+		assert.Equal(suite.T(), 400, w.Code, path)
+		assert.Contains(suite.T(), w.Body.String(), "unsupported expand value: project (supported: user)", path)
+	}
 }
 
 func TestTodoTestSuite(t *testing.T) {
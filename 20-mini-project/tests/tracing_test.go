@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/handler"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/tracing"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTracingRecorder memasang tracer provider global yang menyimpan span di memory
+func newTracingRecorder(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	// Setup dengan exporter none hanya memasang propagator W3C
+	_, err := tracing.Setup(context.Background(), tracing.Options{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	require.Failf(t, "span not found", "no span %q in %v", name, names)
+	return tracetest.SpanStub{}
+}
+
+// Request HTTP -> TodoService -> query GORM membentuk satu trace berantai,
+// dengan traceparent dari client sebagai parent span HTTP
+func TestTracingPropagatesThroughLayers(t *testing.T) {
+	exporter := newTracingRecorder(t)
+	db := newTestDatabase(t)
+
+	todoService := service.NewTodoService(
+		repository.NewTodoRepository(database.NewCluster(db, database.Options{})),
+		repository.NewBoardRepository(db),
+		event.NewBus(),
+		testTodoSettings,
+	)
+	todoHandler := handler.NewTodoHandler(todoService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Tracing())
+	router.GET("/api/v1/todos", middleware.AuthMiddleware(), todoHandler.GetAll)
+
+	user := model.User{Username: "traced", Email: "traced@example.com", Password: "x", FullName: "Traced"}
+	require.NoError(t, db.Create(&user).Error)
+	token, err := utils.GenerateToken(user.ID, user.Username)
+	require.NoError(t, err)
+	exporter.Reset()
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("traceparent", traceparent)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := exporter.GetSpans()
+	server := findSpan(t, spans, "GET /api/v1/todos")
+	serviceSpan := findSpan(t, spans, "TodoService.GetUserTodos")
+	query := findSpan(t, spans, "SELECT todos")
+
+	// Span HTTP melanjutkan trace dari client
+	upstreamTrace, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	upstreamSpan, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, upstreamTrace, server.SpanContext.TraceID())
+	assert.Equal(t, upstreamSpan, server.Parent.SpanID())
+	assert.True(t, server.Parent.IsRemote())
+
+	// HTTP -> service -> query dalam trace yang sama
+	for _, span := range []tracetest.SpanStub{serviceSpan, query} {
+		assert.Equal(t, upstreamTrace, span.SpanContext.TraceID(), span.Name)
+	}
+	assert.Equal(t, server.SpanContext.SpanID(), serviceSpan.Parent.SpanID())
+	assert.Equal(t, serviceSpan.SpanContext.SpanID(), query.Parent.SpanID())
+	assert.Equal(t, trace.SpanKindClient, query.SpanKind)
+}
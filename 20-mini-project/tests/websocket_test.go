@@ -0,0 +1,324 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/database"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/dto"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/event"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/grpcapi"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/handler"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/middleware"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/model"
+	todov1 "github.com/adityapryg/golang-demo/20-mini-project/internal/pb/todo/v1"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/realtime"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/repository"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/service"
+	"github.com/adityapryg/golang-demo/20-mini-project/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+type collaborationTest struct {
+	server       *httptest.Server
+	grpcAddr     string
+	todoService  *service.TodoService
+	shareService *service.BoardShareService
+	owner        model.User
+	collaborator model.User
+}
+
+func newCollaborationTest(t *testing.T) *collaborationTest {
+	t.Helper()
+	db := newTestDatabase(t)
+
+	todoRepo := repository.NewTodoRepository(database.NewCluster(db, database.Options{}))
+	boardRepo := repository.NewBoardRepository(db)
+	events := event.NewBus()
+	hub := realtime.NewHub()
+	events.Subscribe(hub.HandleEvent)
+	eventLog := event.NewLog(100)
+	events.Subscribe(eventLog.Append)
+	userRepo := repository.NewUserRepository(db)
+	todoService := service.NewTodoService(todoRepo, boardRepo, events, testTodoSettings)
+	shareService := service.NewBoardShareService(boardRepo, userRepo, todoRepo)
+	wsHandler := handler.NewWebSocketHandler(hub, shareService, func() []string { return []string{"https://app.example.com"} })
+	shareService.OnRevoke(wsHandler.Revoke)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ws", middleware.WebSocketAuthMiddleware(), wsHandler.Connect)
+	router.GET("/events", middleware.AuthMiddleware(), handler.NewEventHandler(eventLog, shareService).Stream)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	t.Cleanup(eventLog.Close) // Stream SSE selesai sebelum server.Close menunggunya
+
+	grpcServer := grpcapi.NewServer(service.NewAuthService(userRepo), todoService, shareService, eventLog)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		grpcServer.Shutdown(ctx)
+	})
+
+	test := &collaborationTest{server: server, grpcAddr: listener.Addr().String(), todoService: todoService, shareService: shareService}
+	for _, user := range []*model.User{&test.owner, &test.collaborator} {
+		name := "owner"
+		if user == &test.collaborator {
+			name = "collaborator"
+		}
+		*user = model.User{Username: name, Email: name + "@example.com", Password: "x", FullName: name}
+		require.NoError(t, db.Create(user).Error)
+	}
+	return test
+}
+
+func (ct *collaborationTest) dial(t *testing.T, user model.User, origin string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	token, err := utils.GenerateToken(user.ID, user.Username)
+	require.NoError(t, err)
+
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	url := "ws" + strings.TrimPrefix(ct.server.URL, "http") + "/ws?access_token=" + token
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if err == nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, resp, err
+}
+
+// streamEvents membuka stream SSE dan gRPC WatchTodos sebagai user dan
+// mengembalikan channel tipe event plus judul todo dari masing-masing stream
+func (ct *collaborationTest) streamEvents(t *testing.T, user model.User) (sse, watch <-chan string) {
+	t.Helper()
+	token, err := utils.GenerateToken(user.ID, user.Username)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ct.server.URL+"/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	sseEvents := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, found := strings.CutPrefix(scanner.Text(), "data: ")
+			if !found {
+				continue
+			}
+			var e struct {
+				Type string `json:"type"`
+				Data struct {
+					Title string `json:"title"`
+				} `json:"data"`
+			}
+			if json.Unmarshal([]byte(data), &e) == nil {
+				sseEvents <- e.Type + " " + e.Data.Title
+			}
+		}
+	}()
+
+	conn, err := grpc.NewClient(ct.grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	stream, err := todov1.NewTodoServiceClient(conn).WatchTodos(
+		metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token), &todov1.WatchTodosRequest{})
+	require.NoError(t, err)
+	// Header memastikan stream sudah terdaftar di server sebelum test lanjut
+	_, err = stream.Header()
+	require.NoError(t, err)
+
+	watchEvents := make(chan string, 10)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			watchEvents <- resp.GetEvent().GetType() + " " + resp.GetEvent().GetTodo().GetTitle()
+		}
+	}()
+	return sseEvents, watchEvents
+}
+
+func nextEvent(t *testing.T, events <-chan string) string {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+		return ""
+	}
+}
+
+func sendMessage(t *testing.T, conn *websocket.Conn, msg realtime.Message) {
+	t.Helper()
+	require.NoError(t, conn.WriteJSON(msg))
+}
+
+func receiveMessage(t *testing.T, conn *websocket.Conn) realtime.Message {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var msg realtime.Message
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestWebSocketRejectsCrossSiteOrigin(t *testing.T) {
+	ct := newCollaborationTest(t)
+
+	_, resp, err := ct.dial(t, ct.owner, "https://evil.example.com")
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	_, _, err = ct.dial(t, ct.owner, "https://app.example.com")
+	assert.NoError(t, err, "configured origin")
+	_, _, err = ct.dial(t, ct.owner, ct.server.URL)
+	assert.NoError(t, err, "same origin")
+	_, _, err = ct.dial(t, ct.owner, "")
+	assert.NoError(t, err, "non-browser client without Origin")
+}
+
+func TestWebSocketCollaboratorFollowsSharedBoard(t *testing.T) {
+	ct := newCollaborationTest(t)
+	ctx := context.Background()
+	boardRoom := realtime.BoardRoom(ct.owner.ID)
+
+	todo, err := ct.todoService.CreateTodo(ctx, ct.owner.ID, dto.CreateTodoRequest{Title: "shared", Status: "pending", Priority: "low"})
+	require.NoError(t, err)
+	todoRoom := realtime.TodoRoom(todo.ID)
+
+	conn, _, err := ct.dial(t, ct.collaborator, "")
+	require.NoError(t, err)
+
+	// Tanpa share, board dan todo milik user lain tidak bisa diikuti
+	sendMessage(t, conn, realtime.Message{Type: "subscribe", Room: boardRoom})
+	assert.Equal(t, "error", receiveMessage(t, conn).Type)
+	sendMessage(t, conn, realtime.Message{Type: "subscribe", Room: todoRoom})
+	assert.Equal(t, "error", receiveMessage(t, conn).Type)
+
+	_, err = ct.shareService.ShareBoard(ctx, ct.owner.ID, ct.collaborator.Username)
+	require.NoError(t, err)
+	sendMessage(t, conn, realtime.Message{Type: "subscribe", Room: boardRoom})
+	assert.Equal(t, "subscribed", receiveMessage(t, conn).Type)
+	sendMessage(t, conn, realtime.Message{Type: "subscribe", Room: todoRoom})
+	assert.Equal(t, "subscribed", receiveMessage(t, conn).Type)
+
+	// Presence hanya menerima state yang dikenal
+	sendMessage(t, conn, realtime.Message{Type: "presence", Room: todoRoom, State: strings.Repeat("x", 100)})
+	assert.Equal(t, "error", receiveMessage(t, conn).Type)
+
+	// Perubahan oleh pemilik sampai ke collaborator
+	title := "edited by owner"
+	_, err = ct.todoService.UpdateTodo(ctx, todo.ID, ct.owner.ID, dto.UpdateTodoRequest{Title: &title})
+	require.NoError(t, err)
+	rooms := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		msg := receiveMessage(t, conn)
+		assert.Equal(t, "event", msg.Type)
+		rooms[msg.Room] = true
+	}
+	assert.Equal(t, map[string]bool{boardRoom: true, todoRoom: true}, rooms)
+
+	// Share dicabut: collaborator dikeluarkan dari kedua room
+	require.NoError(t, ct.shareService.UnshareBoard(ctx, ct.owner.ID, ct.collaborator.ID))
+	revoked := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		msg := receiveMessage(t, conn)
+		assert.Equal(t, "revoked", msg.Type)
+		revoked[msg.Room] = true
+	}
+	assert.Equal(t, map[string]bool{boardRoom: true, todoRoom: true}, revoked)
+
+	_, err = ct.todoService.UpdateTodo(ctx, todo.ID, ct.owner.ID, dto.UpdateTodoRequest{Title: &title})
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+	var msg realtime.Message
+	assert.Error(t, conn.ReadJSON(&msg), "no broadcast expected after revoke")
+}
+
+func TestShareBoardValidation(t *testing.T) {
+	ct := newCollaborationTest(t)
+	ctx := context.Background()
+
+	_, err := ct.shareService.ShareBoard(ctx, ct.owner.ID, "nobody")
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+	_, err = ct.shareService.ShareBoard(ctx, ct.owner.ID, ct.owner.Username)
+	assert.ErrorIs(t, err, service.ErrShareWithSelf)
+	assert.ErrorIs(t, ct.shareService.UnshareBoard(ctx, ct.owner.ID, ct.collaborator.ID), service.ErrShareNotFound)
+
+	// Share dua kali tidak membuat duplikat
+	for i := 0; i < 2; i++ {
+		_, err = ct.shareService.ShareBoard(ctx, ct.owner.ID, ct.collaborator.Username)
+		require.NoError(t, err)
+	}
+	shares, err := ct.shareService.GetShares(ctx, ct.owner.ID)
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	assert.Equal(t, ct.collaborator.Username, shares[0].User.Username)
+}
+
+// SSE dan gRPC WatchTodos memakai visibility yang sama dengan room WebSocket
+func TestStreamsFollowSharedBoard(t *testing.T) {
+	ct := newCollaborationTest(t)
+	ctx := context.Background()
+	todo, err := ct.todoService.CreateTodo(ctx, ct.owner.ID, dto.CreateTodoRequest{Title: "owner todo", Status: "pending", Priority: "low"})
+	require.NoError(t, err)
+
+	update := func(userID, todoID uint, title string) {
+		t.Helper()
+		_, err := ct.todoService.UpdateTodo(ctx, todoID, userID, dto.UpdateTodoRequest{Title: &title})
+		require.NoError(t, err)
+	}
+	own, err := ct.todoService.CreateTodo(ctx, ct.collaborator.ID, dto.CreateTodoRequest{Title: "own todo", Status: "pending", Priority: "low"})
+	require.NoError(t, err)
+
+	// Tanpa share, event board pemilik tidak dikirim; event berikutnya yang
+	// diterima adalah perubahan todo milik sendiri
+	sse, watch := ct.streamEvents(t, ct.collaborator)
+	update(ct.owner.ID, todo.ID, "private edit")
+	update(ct.collaborator.ID, own.ID, "own edit 1")
+	assert.Equal(t, "todo.updated own edit 1", nextEvent(t, sse))
+	assert.Equal(t, "todo.updated own edit 1", nextEvent(t, watch))
+
+	// Setelah share, stream baru ikut menerima board pemilik
+	_, err = ct.shareService.ShareBoard(ctx, ct.owner.ID, ct.collaborator.Username)
+	require.NoError(t, err)
+	sse, watch = ct.streamEvents(t, ct.collaborator)
+	update(ct.owner.ID, todo.ID, "shared edit")
+	assert.Equal(t, "todo.updated shared edit", nextEvent(t, sse))
+	assert.Equal(t, "todo.updated shared edit", nextEvent(t, watch))
+
+	// Share dicabut: stream yang sedang terbuka langsung berhenti menerima board pemilik
+	require.NoError(t, ct.shareService.UnshareBoard(ctx, ct.owner.ID, ct.collaborator.ID))
+	update(ct.owner.ID, todo.ID, "after revoke")
+	update(ct.collaborator.ID, own.ID, "own edit 2")
+	assert.Equal(t, "todo.updated own edit 2", nextEvent(t, sse))
+	assert.Equal(t, "todo.updated own edit 2", nextEvent(t, watch))
+}